@@ -0,0 +1,240 @@
+// Command eval runs a labeled dataset through the verifier and writes a
+// JSON report with accuracy, macro-F1, confusion matrix, calibration, cost
+// and latency.
+//
+//	eval -dataset claims.jsonl -out report.json [-provider openai|replay|stub]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"fact-check/internal/config"
+	"fact-check/internal/eval"
+	"fact-check/internal/services"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	fs := flag.NewFlagSet("eval", flag.ContinueOnError)
+	dataset := fs.String("dataset", "", "labeled dataset (.jsonl, or LIAR-style .tsv)")
+	format := fs.String("format", "", "dataset format, jsonl or tsv (default: from the file extension)")
+	out := fs.String("out", "", "write the JSON report to this file instead of stdout")
+	provider := fs.String("provider", "openai", "openai, replay or stub")
+	replay := fs.String("replay", "", "recorded responses to answer from (replay provider)")
+	record := fs.String("record", "", "append provider responses to this file for later replay")
+	stubVerdict := fs.String("stub-verdict", eval.LabelUncertain, "verdict the stub provider returns")
+	concurrency := fs.Int("concurrency", 4, "verifier calls in flight")
+	limit := fs.Int("limit", 0, "evaluate only the first N examples (0 for all)")
+	model := fs.String("model", "", "model to evaluate (default: openai_model from the config)")
+	promptFile := fs.String("prompt", "", "prompt template file to evaluate instead of the built-in prompt")
+	systemFile := fs.String("system", "", "system prompt file for -prompt (default: the built-in system prompt)")
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML config file")
+	quiet := fs.Bool("quiet", false, "do not print progress")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *dataset == "" {
+		fmt.Fprintln(os.Stderr, "eval: -dataset is required")
+		fs.Usage()
+		return 2
+	}
+
+	var loadArgs []string
+	if *configFile != "" {
+		loadArgs = []string{"--config", *configFile}
+	}
+	cfg, err := config.Load(loadArgs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "eval: %v\n", err)
+		return 1
+	}
+	if *model != "" {
+		cfg.OpenAIModel = *model
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(os.Stderr)
+	logger.SetLevel(logrus.WarnLevel)
+
+	examples, err := loadDataset(*dataset, *format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "eval: %v\n", err)
+		return 1
+	}
+	if *limit > 0 && *limit < len(examples) {
+		examples = examples[:*limit]
+	}
+
+	verifier, runModel, err := newVerifier(cfg, logger, *provider, *replay, *stubVerdict)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "eval: %v\n", err)
+		return 1
+	}
+	if *record != "" {
+		file, err := os.OpenFile(*record, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "eval: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		verifier = eval.NewRecorder(verifier, file)
+	}
+
+	opts := eval.Options{Concurrency: *concurrency}
+	if *provider != "stub" {
+		opts.Cost = services.NewUsageService(cfg, nil, nil, logger).EstimateCost
+	}
+	if *promptFile != "" {
+		if opts.Prompt, err = loadPrompt(*promptFile, *systemFile); err != nil {
+			fmt.Fprintf(os.Stderr, "eval: %v\n", err)
+			return 1
+		}
+	}
+	if !*quiet {
+		opts.Progress = func(done, total int, result eval.Result) {
+			fmt.Fprintf(os.Stderr, "\r%d/%d", done, total)
+			if done == total {
+				fmt.Fprintln(os.Stderr)
+			}
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	results := eval.Run(ctx, verifier, examples, opts)
+	report := eval.Summarize(eval.RunInfo{
+		Dataset:     *dataset,
+		Provider:    *provider,
+		Model:       runModel,
+		Prompt:      *promptFile,
+		Concurrency: *concurrency,
+	}, results)
+
+	if err := writeReport(report, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "eval: %v\n", err)
+		return 1
+	}
+	printSummary(os.Stderr, report)
+	return 0
+}
+
+func loadDataset(path, format string) ([]eval.Example, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if format == "" {
+		format = eval.FormatFor(path)
+	}
+	examples, err := eval.Load(file, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("%s: no examples", path)
+	}
+	return examples, nil
+}
+
+// newVerifier returns the provider to evaluate and the model it reports
+func newVerifier(cfg *config.Config, logger *logrus.Logger, provider, replay, stubVerdict string) (services.Verifier, string, error) {
+	switch provider {
+	case "openai":
+		openAI := services.NewOpenAIService(cfg, logger)
+		// Without a key the service answers "uncertain" for everything,
+		// which would be scored as if the model had said so
+		if !openAI.IsAvailable() {
+			return nil, "", fmt.Errorf("OPENAI_API_KEY is not set; point OPENAI_ENDPOINT at a local stub or use -provider replay")
+		}
+		return openAI, cfg.OpenAIModel, nil
+	case "replay":
+		if replay == "" {
+			return nil, "", fmt.Errorf("-replay is required for the replay provider")
+		}
+		file, err := os.Open(replay)
+		if err != nil {
+			return nil, "", err
+		}
+		defer file.Close()
+		replayer, err := eval.LoadReplayer(file)
+		return replayer, "", err
+	case "stub":
+		verdict, ok := eval.NormalizeLabel(stubVerdict)
+		if !ok {
+			return nil, "", fmt.Errorf("unknown stub verdict %q", stubVerdict)
+		}
+		return eval.Stub{Status: verdict}, "stub", nil
+	default:
+		return nil, "", fmt.Errorf("unknown provider %q", provider)
+	}
+}
+
+func loadPrompt(templateFile, systemFile string) (*services.PromptTemplate, error) {
+	text, err := os.ReadFile(templateFile)
+	if err != nil {
+		return nil, err
+	}
+	system := ""
+	if systemFile != "" {
+		content, err := os.ReadFile(systemFile)
+		if err != nil {
+			return nil, err
+		}
+		system = strings.TrimSpace(string(content))
+	}
+	return services.ParsePromptTemplate(nil, system, string(text))
+}
+
+func writeReport(report *eval.Report, path string) error {
+	var w io.Writer = os.Stdout
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+func printSummary(w io.Writer, report *eval.Report) {
+	fmt.Fprintf(w, "examples %d, scored %d, errors %d\n", report.Examples, report.Scored, report.Errors)
+	fmt.Fprintf(w, "accuracy %.4f, macro-F1 %.4f, ECE %.4f over %d\n", report.Accuracy, report.MacroF1, report.Calibration.ECE, report.Calibration.Count)
+	fmt.Fprintf(w, "cost $%.6f, latency p50 %dms p90 %dms\n", report.Cost.TotalUSD, report.Latency.P50MS, report.Latency.P90MS)
+
+	labels := append([]string{}, eval.Labels...)
+	sort.Strings(labels)
+	fmt.Fprintf(w, "%-10s", "truth\\pred")
+	for _, predicted := range labels {
+		fmt.Fprintf(w, " %9s", predicted)
+	}
+	fmt.Fprintln(w)
+	for _, truth := range labels {
+		fmt.Fprintf(w, "%-10s", truth)
+		for _, predicted := range labels {
+			fmt.Fprintf(w, " %9d", report.Confusion[truth][predicted])
+		}
+		fmt.Fprintln(w)
+	}
+}
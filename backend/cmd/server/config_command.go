@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"fact-check/internal/config"
+)
+
+// runConfigCommand implements "server config print [--redacted] [flags]",
+// which prints the effective configuration after all layers are applied
+func runConfigCommand(args []string) int {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "usage: server config print [--redacted] [--config file] [server flags]")
+		return 2
+	}
+
+	// --redacted is handled here, everything else is passed to config.Load
+	redacted := false
+	var loadArgs []string
+	for _, arg := range args[1:] {
+		if arg == "--redacted" || arg == "-redacted" {
+			redacted = true
+			continue
+		}
+		loadArgs = append(loadArgs, arg)
+	}
+
+	cfg, err := config.Load(loadArgs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if redacted {
+		cfg = cfg.Redacted()
+	}
+
+	out, err := cfg.YAML()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to render config: %v\n", err)
+		return 1
+	}
+	os.Stdout.Write(out)
+	return 0
+}
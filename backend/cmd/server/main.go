@@ -3,25 +3,32 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"fact-check/internal/config"
 	"fact-check/internal/database"
-	"fact-check/internal/handlers"
-	"fact-check/internal/middleware"
+	"fact-check/internal/health"
+	"fact-check/internal/logging"
+	"fact-check/internal/metrics"
+	"fact-check/internal/repository"
+	"fact-check/internal/server"
 	"fact-check/internal/services"
+	"fact-check/internal/tracing"
 
-	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand(os.Args[2:]))
+	}
+
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(os.Args[1:])
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
@@ -30,85 +37,167 @@ func main() {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 	logger.SetLevel(cfg.LogLevel)
+	logger.AddHook(tracing.LogrusHook{})
+	logger.AddHook(logging.ContextHook{})
+	// Registered last so fields added by other hooks are scrubbed too
+	logger.AddHook(logging.NewRedactionHook(cfg.JWTSecret, cfg.GoogleClientSecret, cfg.OpenAIAPIKey))
+
+	// Root context, cancelled when shutdown gives up waiting on in-flight requests
+	baseCtx, cancelBase := context.WithCancel(context.Background())
+	defer cancelBase()
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Setup(baseCtx, tracing.Config{
+		Exporter:     cfg.Tracing.Exporter,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		ServiceName:  cfg.Tracing.ServiceName,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+		Environment:  cfg.Environment,
+	})
+	if err != nil {
+		logger.Fatalf("Failed to set up tracing: %v", err)
+	}
 
 	// Initialize database
-	db, err := database.NewConnection(cfg.DatabaseURL)
+	db, err := database.NewConnection(baseCtx, cfg.DatabaseURL, cfg.Database)
 	if err != nil {
 		logger.Fatalf("Failed to connect to database: %v", err)
 	}
 	defer db.Close()
 
+	metrics.RegisterDBStats(db)
+
 	// Run database migrations
-	if err := database.RunMigrations(db); err != nil {
+	if err := database.RunMigrations(baseCtx, db); err != nil {
 		logger.Fatalf("Failed to run database migrations: %v", err)
 	}
 
+	// Initialize repositories
+	userRepo := repository.NewPostgresUserRepository(db)
+	newsRepo := repository.NewPostgresNewsRepository(db)
+	usageRepo := repository.NewPostgresUsageRepository(db)
+	orgRepo := repository.NewPostgresOrganizationRepository(db)
+	webhookRepo := repository.NewPostgresWebhookRepository(db)
+	jobRepo := repository.NewPostgresVerificationJobRepository(db)
+	auditRepo := repository.NewPostgresAuditRepository(db)
+	accountEraser := repository.NewPostgresAccountEraser(db)
+	sourceRepo := repository.NewPostgresSourceRepository(db)
+	translationRepo := repository.NewPostgresTranslationRepository(db)
+	promptRepo := repository.NewPostgresPromptRepository(db)
+	calibrationRepo := repository.NewPostgresCalibrationRepository(db)
+
 	// Initialize services
-	authService := services.NewAuthService(cfg, db, logger)
-	newsService := services.NewNewsService(cfg, db, logger)
+	authService := services.NewAuthService(cfg, userRepo, logger)
+	organizationService := services.NewOrganizationService(orgRepo, logger)
+	webhookService := services.NewWebhookService(cfg, webhookRepo, organizationService, logger)
 	openAIService := services.NewOpenAIService(cfg, logger)
-
-	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService, logger)
-	newsHandler := handlers.NewNewsHandler(newsService, openAIService, logger)
-
-	// Setup Gin router
-	router := gin.New()
-	router.Use(gin.Recovery())
-	router.Use(middleware.CORS())
-	router.Use(middleware.RequestLogger(logger))
-	router.Use(middleware.DefaultRateLimiter())
-
-	// Health check endpoint
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
+	usageService := services.NewUsageService(cfg, usageRepo, userRepo, logger)
+	redactionService := services.NewRedactionService(cfg, orgRepo, logger)
+	moderationService := services.NewModerationService(cfg, openAIService, usageService, redactionService, logger)
+	newsService := services.NewNewsService(cfg, newsRepo, webhookService, moderationService, logger)
+	sourceService := services.NewSourceService(sourceRepo, logger)
+	languageService := services.NewLanguageService(cfg, userRepo, translationRepo, newsService, openAIService, usageService, redactionService, logger)
+	promptService := services.NewPromptService(promptRepo, logger)
+	var verifier services.Verifier = openAIService
+	if cfg.Ensemble.Enabled() {
+		members := make([]services.EnsembleMember, len(cfg.Ensemble.Models))
+		for i, model := range cfg.Ensemble.Models {
+			members[i] = services.EnsembleMember{Model: model, Verifier: openAIService.ForModel(model)}
+		}
+		verifier = services.NewEnsembleVerifier(members, cfg.Ensemble.Voting)
+		logger.Infof("Verifying with an ensemble of %v using %s voting", cfg.Ensemble.Models, cfg.Ensemble.Voting)
+	}
+	calibrationService := services.NewCalibrationService(cfg, calibrationRepo, logger)
+	if err := calibrationService.Load(baseCtx); err != nil {
+		logger.Errorf("Failed to load confidence calibration, using stated confidences: %v", err)
+	}
+	verificationService := services.NewVerificationService(newsService, verifier, usageService, sourceService, languageService, promptService, calibrationService, redactionService, logger)
+	verificationStreams := services.NewVerificationStreams(verificationService, cfg.Timeouts.LLMVerify, cfg.Stream.Retention, logger)
+	verificationQueue := services.NewVerificationQueue(cfg, jobRepo, verificationService, logger)
+	batchService := services.NewBatchService(cfg, jobRepo, verificationQueue, newsService, webhookService, logger)
+	accountService := services.NewAccountService(cfg, services.AccountRepositories{
+		Users:         userRepo,
+		News:          newsRepo,
+		Usage:         usageRepo,
+		Organizations: orgRepo,
+		Webhooks:      webhookRepo,
+		Eraser:        accountEraser,
+		Audit:         auditRepo,
+	}, logger)
+
+	// Hot-reloadable settings: log level, rate limits, model, prices, budgets,
+	// abstention thresholds, PII redaction and moderation rules
+	configs := config.NewManager(cfg, os.Args[1:])
+	logLevels := logging.NewLevelController(logger)
+	configs.Subscribe(func(cfg *config.Config) {
+		logLevels.SetConfigured(cfg.LogLevel)
+		openAIService.ApplyConfig(cfg)
+		usageService.ApplyConfig(cfg)
+		calibrationService.ApplyConfig(cfg)
+		redactionService.ApplyConfig(cfg)
+		moderationService.ApplyConfig(cfg)
 	})
 
-	// API routes
-	api := router.Group("/api/v1")
-	{
-		// Service status endpoint
-		api.GET("/services/status", func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{
-				"openai": gin.H{
-					"available":  false,
-					"configured": false,
-					"message":    "OpenAI API quota exceeded - please check your billing",
-				},
-				"database": gin.H{
-					"status":  "connected",
-					"message": "Database connection established",
-				},
-			})
-		})
-
-		// Auth routes
-		auth := api.Group("/auth")
-		{
-			auth.GET("/login", authHandler.Login)
-			auth.GET("/callback", authHandler.Callback)
-			auth.GET("/me", middleware.AuthMiddleware(authService), authHandler.Me)
-			auth.POST("/logout", middleware.AuthMiddleware(authService), authHandler.Logout)
-		}
+	// Register health checks
+	healthRegistry := health.NewRegistry()
+	healthRegistry.Register(health.Check{
+		Name:     "database",
+		Critical: true,
+		Timeout:  cfg.Health.CheckTimeout,
+		CacheTTL: cfg.Health.CacheTTL,
+		Probe:    database.Probe(db),
+	})
+	healthRegistry.Register(health.Check{
+		Name:     "llm",
+		Timeout:  cfg.Health.CheckTimeout,
+		CacheTTL: cfg.Health.LLMCacheTTL,
+		Probe:    openAIService.Probe,
+	})
+	healthRegistry.Register(health.Check{
+		Name:     "queue",
+		Timeout:  cfg.Health.CheckTimeout,
+		CacheTTL: cfg.Health.CacheTTL,
+		Probe:    verificationQueue.Probe,
+	})
 
-		// News routes
-		news := api.Group("/news")
-		{
-			news.POST("/submit", middleware.AuthMiddleware(authService), newsHandler.Submit)
-			news.GET("/verify/:id", middleware.AuthMiddleware(authService), newsHandler.Verify)
-			news.GET("/user/:id", middleware.AuthMiddleware(authService), newsHandler.GetUserNews)
-		}
-	}
+	router := server.NewRouter(configs, server.Services{
+		Auth:          authService,
+		News:          newsService,
+		Usage:         usageService,
+		Verifications: verificationService,
+		Streams:       verificationStreams,
+		Batches:       batchService,
+		Organizations: organizationService,
+		Webhooks:      webhookService,
+		Accounts:      accountService,
+		Sources:       sourceService,
+		Languages:     languageService,
+		Prompts:       promptService,
+		Calibration:   calibrationService,
+		Health:        healthRegistry,
+		LogLevels:     logLevels,
+	}, logger)
 
 	// Create HTTP server
 	srv := &http.Server{
 		Addr:         ":" + cfg.Port,
 		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+		BaseContext: func(net.Listener) context.Context {
+			return baseCtx
+		},
 	}
 
+	// Send webhook deliveries, run queued verifications, erase accounts past
+	// their deletion grace period and refit the confidence calibration in
+	// the background
+	go webhookService.Run(baseCtx)
+	go verificationQueue.Run(baseCtx)
+	go accountService.Run(baseCtx)
+	go calibrationService.Run(baseCtx)
+
 	// Start server in a goroutine
 	go func() {
 		logger.Infof("Starting server on port %s", cfg.Port)
@@ -117,6 +206,28 @@ func main() {
 		}
 	}()
 
+	// Reload config on SIGHUP or when the config file changes
+	logReload := func(result *config.ReloadResult, err error) {
+		if err != nil {
+			logger.Errorf("Config reload failed, keeping current config: %v", err)
+			return
+		}
+		if len(result.Ignored) > 0 {
+			logger.Warnf("Config changes need a restart to take effect: %v", result.Ignored)
+		}
+		if len(result.Applied) > 0 {
+			logger.Infof("Config reloaded, applied: %v", result.Applied)
+		}
+	}
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			logReload(configs.Reload())
+		}
+	}()
+	go configs.Watch(baseCtx, cfg.ConfigWatch, logReload)
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -124,12 +235,18 @@ func main() {
 	logger.Info("Shutting down server...")
 
 	// Give outstanding requests a deadline for completion
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
+		// Cancel request contexts so in-flight queries and LLM calls stop
+		cancelBase()
 		logger.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Errorf("Failed to flush traces: %v", err)
+	}
+
 	logger.Info("Server exited")
 }
@@ -0,0 +1,249 @@
+// Package calibration maps the confidence a model states onto the
+// probability that its verdict is right, fitted from reviewed outcomes
+// with Platt or isotonic scaling.
+package calibration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Calibration methods
+const (
+	MethodPlatt    = "platt"
+	MethodIsotonic = "isotonic"
+)
+
+// ErrNoSamples is returned when fitting without outcomes of both kinds
+var ErrNoSamples = errors.New("calibration needs correct and incorrect outcomes")
+
+// Sample is a verdict's stated confidence and whether a reviewer agreed
+type Sample struct {
+	Score   float64
+	Correct bool
+}
+
+// Calibrator turns a stated confidence into a calibrated one
+type Calibrator interface {
+	Calibrate(score float64) float64
+}
+
+// Identity leaves confidences unchanged; it is used until a calibration
+// has been fitted
+type Identity struct{}
+
+func (Identity) Calibrate(score float64) float64 {
+	return clamp(score)
+}
+
+// Platt is a logistic fit P(correct) = 1 / (1 + exp(A*score + B))
+type Platt struct {
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+}
+
+func (p *Platt) Calibrate(score float64) float64 {
+	return sigmoid(-(p.A*clamp(score) + p.B))
+}
+
+// FitPlatt fits A and B by Newton's method on the log loss, using Platt's
+// smoothed targets so a perfectly separable sample does not diverge
+func FitPlatt(samples []Sample) (*Platt, error) {
+	var positives, negatives float64
+	for _, sample := range samples {
+		if sample.Correct {
+			positives++
+		} else {
+			negatives++
+		}
+	}
+	if positives == 0 || negatives == 0 {
+		return nil, ErrNoSamples
+	}
+
+	hiTarget := (positives + 1) / (positives + 2)
+	loTarget := 1 / (negatives + 2)
+
+	// Minimize over f(x) = a*x + b with P = sigmoid(f), so A = -a and B = -b
+	a, b := 0.0, math.Log((positives+1)/(negatives+1))
+	for iteration := 0; iteration < 100; iteration++ {
+		var gA, gB, hAA, hAB, hBB float64
+		for _, sample := range samples {
+			x := clamp(sample.Score)
+			target := loTarget
+			if sample.Correct {
+				target = hiTarget
+			}
+			p := sigmoid(a*x + b)
+			d := p - target
+			w := math.Max(p*(1-p), 1e-12)
+			gA += d * x
+			gB += d
+			hAA += w * x * x
+			hAB += w * x
+			hBB += w
+		}
+		// A small ridge keeps the Hessian invertible when all scores are equal
+		hAA += 1e-9
+		hBB += 1e-9
+		det := hAA*hBB - hAB*hAB
+		if det == 0 {
+			break
+		}
+		stepA := (hBB*gA - hAB*gB) / det
+		stepB := (hAA*gB - hAB*gA) / det
+		a -= stepA
+		b -= stepB
+		if math.Abs(stepA) < 1e-10 && math.Abs(stepB) < 1e-10 {
+			break
+		}
+	}
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return nil, fmt.Errorf("platt scaling did not converge")
+	}
+	return &Platt{A: -a, B: -b}, nil
+}
+
+// Isotonic is a non-decreasing piecewise linear map through the points
+// (Scores[i], Probabilities[i]), with Scores sorted ascending
+type Isotonic struct {
+	Scores        []float64 `json:"scores"`
+	Probabilities []float64 `json:"probabilities"`
+}
+
+func (m *Isotonic) Calibrate(score float64) float64 {
+	score = clamp(score)
+	n := len(m.Scores)
+	if n == 0 {
+		return score
+	}
+	if score <= m.Scores[0] {
+		return m.Probabilities[0]
+	}
+	if score >= m.Scores[n-1] {
+		return m.Probabilities[n-1]
+	}
+	i := sort.SearchFloat64s(m.Scores, score)
+	if m.Scores[i] == score {
+		return m.Probabilities[i]
+	}
+	x0, x1 := m.Scores[i-1], m.Scores[i]
+	y0, y1 := m.Probabilities[i-1], m.Probabilities[i]
+	return y0 + (y1-y0)*(score-x0)/(x1-x0)
+}
+
+// FitIsotonic fits a non-decreasing map with the pool adjacent violators
+// algorithm. Each pooled block contributes one point at its mean score.
+func FitIsotonic(samples []Sample) (*Isotonic, error) {
+	var positives int
+	for _, sample := range samples {
+		if sample.Correct {
+			positives++
+		}
+	}
+	if positives == 0 || positives == len(samples) {
+		return nil, ErrNoSamples
+	}
+
+	sorted := append([]Sample(nil), samples...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return clamp(sorted[i].Score) < clamp(sorted[j].Score)
+	})
+
+	type block struct {
+		scoreSum, correctSum, weight float64
+	}
+	var blocks []block
+	for _, sample := range sorted {
+		correct := 0.0
+		if sample.Correct {
+			correct = 1
+		}
+		blocks = append(blocks, block{scoreSum: clamp(sample.Score), correctSum: correct, weight: 1})
+		// Merge backwards while the block means decrease
+		for len(blocks) > 1 {
+			last, prev := blocks[len(blocks)-1], blocks[len(blocks)-2]
+			if prev.correctSum/prev.weight <= last.correctSum/last.weight {
+				break
+			}
+			blocks = blocks[:len(blocks)-2]
+			blocks = append(blocks, block{
+				scoreSum:   prev.scoreSum + last.scoreSum,
+				correctSum: prev.correctSum + last.correctSum,
+				weight:     prev.weight + last.weight,
+			})
+		}
+	}
+
+	model := &Isotonic{}
+	for _, b := range blocks {
+		score := b.scoreSum / b.weight
+		probability := b.correctSum / b.weight
+		// Blocks with the same mean score collapse into one point
+		if n := len(model.Scores); n > 0 && model.Scores[n-1] == score {
+			model.Probabilities[n-1] = probability
+			continue
+		}
+		model.Scores = append(model.Scores, score)
+		model.Probabilities = append(model.Probabilities, probability)
+	}
+	return model, nil
+}
+
+// Fit fits a calibrator with the given method
+func Fit(method string, samples []Sample) (Calibrator, error) {
+	switch method {
+	case MethodPlatt:
+		return FitPlatt(samples)
+	case MethodIsotonic:
+		return FitIsotonic(samples)
+	default:
+		return nil, fmt.Errorf("unknown calibration method %q", method)
+	}
+}
+
+// Decode restores a calibrator stored as JSON by Fit's caller
+func Decode(method string, params []byte) (Calibrator, error) {
+	var calibrator Calibrator
+	switch method {
+	case MethodPlatt:
+		calibrator = &Platt{}
+	case MethodIsotonic:
+		calibrator = &Isotonic{}
+	default:
+		return nil, fmt.Errorf("unknown calibration method %q", method)
+	}
+	if err := json.Unmarshal(params, calibrator); err != nil {
+		return nil, fmt.Errorf("failed to decode %s calibration: %w", method, err)
+	}
+	return calibrator, nil
+}
+
+// Brier is the mean squared error of the calibrated confidences; lower is
+// better
+func Brier(calibrator Calibrator, samples []Sample) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, sample := range samples {
+		target := 0.0
+		if sample.Correct {
+			target = 1
+		}
+		d := calibrator.Calibrate(sample.Score) - target
+		sum += d * d
+	}
+	return sum / float64(len(samples))
+}
+
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+func clamp(score float64) float64 {
+	return math.Max(0, math.Min(1, score))
+}
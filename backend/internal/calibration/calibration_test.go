@@ -0,0 +1,75 @@
+package calibration
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// overconfident returns outcomes of a model that states 90% but is right
+// 60% of the time, and states 50% but is right 20% of the time
+func overconfident() []Sample {
+	var samples []Sample
+	for i := 0; i < 10; i++ {
+		samples = append(samples, Sample{Score: 0.9, Correct: i < 6})
+		samples = append(samples, Sample{Score: 0.5, Correct: i < 2})
+	}
+	return samples
+}
+
+func TestFitPlatt(t *testing.T) {
+	samples := overconfident()
+	platt, err := FitPlatt(samples)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	high, low := platt.Calibrate(0.9), platt.Calibrate(0.5)
+	if math.Abs(high-0.6) > 0.08 || math.Abs(low-0.2) > 0.08 {
+		t.Fatalf("expected about 0.6 and 0.2, got %.3f and %.3f", high, low)
+	}
+	if Brier(platt, samples) >= Brier(Identity{}, samples) {
+		t.Fatal("calibration should lower the Brier score")
+	}
+}
+
+func TestFitIsotonic(t *testing.T) {
+	samples := overconfident()
+	// A violator: a confident miss below a less confident hit
+	samples = append(samples, Sample{Score: 0.7, Correct: false}, Sample{Score: 0.6, Correct: true})
+
+	isotonic, err := FitIsotonic(samples)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 1; i < len(isotonic.Probabilities); i++ {
+		if isotonic.Probabilities[i] < isotonic.Probabilities[i-1] || isotonic.Scores[i] <= isotonic.Scores[i-1] {
+			t.Fatalf("expected an increasing map, got %+v", isotonic)
+		}
+	}
+	if got := isotonic.Calibrate(0.9); got != 0.6 {
+		t.Fatalf("expected 0.6 at 0.9, got %v", got)
+	}
+	if got := isotonic.Calibrate(0.1); got != isotonic.Probabilities[0] {
+		t.Fatalf("expected scores below the range to clamp, got %v", got)
+	}
+
+	// Stored parameters restore the same calibrator
+	params, err := json.Marshal(isotonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(MethodIsotonic, params)
+	if err != nil || decoded.Calibrate(0.75) != isotonic.Calibrate(0.75) {
+		t.Fatalf("decoded calibrator differs (%v)", err)
+	}
+}
+
+func TestFitNeedsBothOutcomes(t *testing.T) {
+	samples := []Sample{{Score: 0.8, Correct: true}, {Score: 0.6, Correct: true}}
+	for _, method := range []string{MethodPlatt, MethodIsotonic} {
+		if _, err := Fit(method, samples); err != ErrNoSamples {
+			t.Errorf("%s: expected ErrNoSamples, got %v", method, err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestDefaultsAreValid(t *testing.T) {
+	if err := Defaults().Validate(); err != nil {
+		t.Fatalf("defaults should validate: %v", err)
+	}
+}
+
+func TestLoadLayersFileEnvAndFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "port: \"9000\"\nopenai_model: gpt-4o\nrate_limit:\n  requests: 10\n  window: 30s\nlog_level: warn\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("OPENAI_MODEL", "gpt-4o-mini")
+	t.Setenv("LOG_LEVEL", "error")
+
+	cfg, err := Load([]string{"--config", path, "--log-level", "debug"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Port != "9000" {
+		t.Errorf("expected port from file, got %s", cfg.Port)
+	}
+	if cfg.RateLimit.Requests != 10 || cfg.RateLimit.Window != 30*time.Second {
+		t.Errorf("expected rate limit from file, got %+v", cfg.RateLimit)
+	}
+	if cfg.OpenAIModel != "gpt-4o-mini" {
+		t.Errorf("expected env to override file, got %s", cfg.OpenAIModel)
+	}
+	if cfg.LogLevel != logrus.DebugLevel {
+		t.Errorf("expected flag to override env, got %s", cfg.LogLevel)
+	}
+	if cfg.Database.MaxOpenConns != 25 {
+		t.Errorf("expected default pool size, got %d", cfg.Database.MaxOpenConns)
+	}
+}
+
+func TestLoadRejectsMalformedEnv(t *testing.T) {
+	t.Setenv("DB_MAX_OPEN_CONNS", "many")
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "DB_MAX_OPEN_CONNS") {
+		t.Fatalf("expected DB_MAX_OPEN_CONNS error, got %v", err)
+	}
+}
+
+func TestValidateProductionRejectsDefaults(t *testing.T) {
+	cfg := Defaults()
+	cfg.Environment = EnvProduction
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected production with default secrets to fail")
+	}
+	for _, want := range []string{"jwt_secret", "database_url", "google_client_id"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error mentioning %s, got %v", want, err)
+		}
+	}
+
+	cfg.JWTSecret = strings.Repeat("s", minProductionSecretLength)
+	cfg.DatabaseURL = "postgres://app:secret@db:5432/factcheck"
+	cfg.GoogleClientID = "client"
+	cfg.GoogleClientSecret = "secret"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected production config to validate: %v", err)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Defaults()
+	cfg.OpenAIAPIKey = "sk-live-key"
+	cfg.DatabaseURL = "postgres://app:hunter2@db:5432/factcheck"
+
+	out, err := cfg.Redacted().YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"sk-live-key", "hunter2", defaultJWTSecret} {
+		if strings.Contains(string(out), secret) {
+			t.Errorf("redacted output contains %q", secret)
+		}
+	}
+	if cfg.OpenAIAPIKey != "sk-live-key" {
+		t.Error("Redacted must not modify the original config")
+	}
+}
+
+func TestManagerReloadAppliesOnlyReloadableSections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("port: \"9000\"\nlog_level: info\nrate_limit:\n  requests: 10\n  window: 1m\n")
+
+	cfg, err := Load([]string{"--config", path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := NewManager(cfg, []string{"--config", path})
+
+	var notified *Config
+	manager.Subscribe(func(c *Config) { notified = c })
+
+	write("port: \"9100\"\nlog_level: debug\nrate_limit:\n  requests: 20\n  window: 1m\n")
+	result, err := manager.Reload()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	current := manager.Current()
+	if current.LogLevel != logrus.DebugLevel || current.RateLimit.Requests != 20 {
+		t.Errorf("reloadable settings not applied: %s %+v", current.LogLevel, current.RateLimit)
+	}
+	if current.Port != "9000" {
+		t.Errorf("port must not change without a restart, got %s", current.Port)
+	}
+	if len(result.Ignored) != 1 || result.Ignored[0] != "port" {
+		t.Errorf("expected port to be reported as ignored, got %v", result.Ignored)
+	}
+	if notified != current {
+		t.Error("subscriber was not notified with the new config")
+	}
+	if cfg.LogLevel != logrus.InfoLevel {
+		t.Error("reload must not modify the previous config")
+	}
+
+	write("rate_limit:\n  requests: -1\n")
+	if _, err := manager.Reload(); err == nil {
+		t.Fatal("expected invalid config to be rejected")
+	}
+	if manager.Current() != current {
+		t.Error("invalid config must leave the current config active")
+	}
+}
@@ -0,0 +1,287 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// loadEnv overlays values from environment variables. Unset variables keep
+// the current value; malformed ones are reported instead of being ignored.
+func (c *Config) loadEnv() error {
+	e := &envReader{}
+
+	e.String("PORT", &c.Port)
+	e.String("DATABASE_URL", &c.DatabaseURL)
+	e.String("JWT_SECRET", &c.JWTSecret)
+	e.String("GOOGLE_CLIENT_ID", &c.GoogleClientID)
+	e.String("GOOGLE_CLIENT_SECRET", &c.GoogleClientSecret)
+	e.String("GOOGLE_REDIRECT_URL", &c.GoogleRedirectURL)
+	e.String("OPENAI_API_KEY", &c.OpenAIAPIKey)
+	e.String("OPENAI_ENDPOINT", &c.OpenAIEndpoint)
+	e.String("OPENAI_MODEL", &c.OpenAIModel)
+	e.Int("OPENAI_MAX_TOKENS", &c.OpenAIMaxTokens)
+
+	e.Duration("SERVER_READ_TIMEOUT", &c.Server.ReadTimeout)
+	e.Duration("SERVER_WRITE_TIMEOUT", &c.Server.WriteTimeout)
+	e.Duration("SERVER_IDLE_TIMEOUT", &c.Server.IdleTimeout)
+	e.Duration("SERVER_SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout)
+
+	e.Int("DB_MAX_OPEN_CONNS", &c.Database.MaxOpenConns)
+	e.Int("DB_MAX_IDLE_CONNS", &c.Database.MaxIdleConns)
+	e.Duration("DB_CONN_MAX_LIFETIME", &c.Database.ConnMaxLifetime)
+	e.Duration("DB_CONN_MAX_IDLE_TIME", &c.Database.ConnMaxIdleTime)
+
+	e.Int("RATE_LIMIT_REQUESTS", &c.RateLimit.Requests)
+	e.Duration("RATE_LIMIT_WINDOW", &c.RateLimit.Window)
+
+	e.Duration("LLM_CONNECT_TIMEOUT", &c.LLMClient.ConnectTimeout)
+	e.Duration("LLM_REQUEST_TIMEOUT", &c.LLMClient.RequestTimeout)
+	e.Int("LLM_MAX_RETRIES", &c.LLMClient.MaxRetries)
+	e.Duration("LLM_RETRY_BASE_DELAY", &c.LLMClient.RetryBaseDelay)
+	e.Duration("LLM_RETRY_MAX_DELAY", &c.LLMClient.RetryMaxDelay)
+	e.Int("LLM_BREAKER_FAILURES", &c.LLMClient.BreakerFailures)
+	e.Duration("LLM_BREAKER_COOLDOWN", &c.LLMClient.BreakerCooldown)
+
+	e.Duration("DB_QUERY_TIMEOUT", &c.Timeouts.DBQuery)
+	e.Duration("OAUTH_TIMEOUT", &c.Timeouts.OAuth)
+	e.Duration("LLM_VERIFY_TIMEOUT", &c.Timeouts.LLMVerify)
+
+	e.Duration("HEALTH_CHECK_TIMEOUT", &c.Health.CheckTimeout)
+	e.Duration("HEALTH_CACHE_TTL", &c.Health.CacheTTL)
+	e.Duration("HEALTH_LLM_CACHE_TTL", &c.Health.LLMCacheTTL)
+
+	e.Duration("STREAM_RETENTION", &c.Stream.Retention)
+	e.Duration("STREAM_KEEPALIVE", &c.Stream.Keepalive)
+
+	e.String("TRACING_EXPORTER", &c.Tracing.Exporter)
+	e.String("OTEL_EXPORTER_OTLP_ENDPOINT", &c.Tracing.OTLPEndpoint)
+	e.String("OTEL_SERVICE_NAME", &c.Tracing.ServiceName)
+	e.Float("TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio)
+
+	e.Duration("WEBHOOK_POLL_INTERVAL", &c.Webhooks.PollInterval)
+	e.Duration("WEBHOOK_TIMEOUT", &c.Webhooks.Timeout)
+	e.Int("WEBHOOK_MAX_ATTEMPTS", &c.Webhooks.MaxAttempts)
+	e.Duration("WEBHOOK_RETRY_BASE_DELAY", &c.Webhooks.RetryBaseDelay)
+	e.Duration("WEBHOOK_RETRY_MAX_DELAY", &c.Webhooks.RetryMaxDelay)
+	e.Bool("WEBHOOK_ALLOW_PRIVATE_TARGETS", &c.Webhooks.AllowPrivateTargets)
+
+	e.Int("QUEUE_WORKERS", &c.Queue.Workers)
+	e.Duration("QUEUE_POLL_INTERVAL", &c.Queue.PollInterval)
+	e.Int("QUEUE_MAX_ATTEMPTS", &c.Queue.MaxAttempts)
+	e.Int("BATCH_MAX_ROWS", &c.Queue.MaxBatchRows)
+
+	e.Duration("DELETION_GRACE_PERIOD", &c.Privacy.DeletionGracePeriod)
+	e.Duration("DELETION_CHECK_INTERVAL", &c.Privacy.DeletionCheckInterval)
+
+	e.String("PUBLISHER_NAME", &c.Publisher.Name)
+	e.String("PUBLISHER_URL", &c.Publisher.URL)
+
+	e.Bool("LINK_RESOLVE", &c.Links.Resolve)
+	e.Duration("LINK_RESOLVE_TIMEOUT", &c.Links.Timeout)
+	e.Int("LINK_MAX_REDIRECTS", &c.Links.MaxRedirects)
+	e.Int64("LINK_MAX_BODY_BYTES", &c.Links.MaxBodyBytes)
+	e.Bool("LINK_ALLOW_PRIVATE_TARGETS", &c.Links.AllowPrivateTargets)
+
+	e.String("VERIFY_LANGUAGE_MODE", &c.Language.Mode)
+	e.String("DEFAULT_LOCALE", &c.Language.DefaultLocale)
+
+	e.List("ENSEMBLE_MODELS", &c.Ensemble.Models)
+	e.String("ENSEMBLE_VOTING", &c.Ensemble.Voting)
+
+	e.String("CALIBRATION_METHOD", &c.Calibration.Method)
+	e.Duration("CALIBRATION_INTERVAL", &c.Calibration.Interval)
+	e.Int("CALIBRATION_MIN_SAMPLES", &c.Calibration.MinSamples)
+	e.Int("CALIBRATION_MAX_SAMPLES", &c.Calibration.MaxSamples)
+	e.Float("ABSTAIN_THRESHOLD", &c.Abstention.Threshold)
+	e.FloatMap("ABSTAIN_TOPIC_THRESHOLDS", &c.Abstention.Topics)
+
+	e.Bool("REDACT_PII", &c.Redaction.Enabled)
+	e.List("REDACT_PII_DETECTORS", &c.Redaction.Detectors)
+
+	e.List("MODERATION_REJECT_KEYWORDS", &c.Moderation.RejectKeywords)
+	e.List("MODERATION_QUARANTINE_KEYWORDS", &c.Moderation.QuarantineKeywords)
+	e.Bool("MODERATION_LLM", &c.Moderation.LLM)
+	e.String("MODERATION_ON_ERROR", &c.Moderation.OnError)
+
+	e.Duration("CONFIG_WATCH_INTERVAL", &c.ConfigWatch)
+	e.LogLevel("LOG_LEVEL", &c.LogLevel)
+	e.String("ENVIRONMENT", &c.Environment)
+	e.List("ADMIN_EMAILS", &c.AdminEmails)
+
+	if value, ok := os.LookupEnv("LLM_PRICES"); ok && value != "" {
+		prices, err := parsePrices(value)
+		e.add("LLM_PRICES", err)
+		c.LLMPrices = prices
+	}
+
+	e.Float("BUDGET_USER_DAILY_USD", &c.Budgets.UserDaily)
+	e.Float("BUDGET_USER_MONTHLY_USD", &c.Budgets.UserMonthly)
+	e.FloatMap("BUDGET_ROLE_DAILY_USD", &c.Budgets.RoleDaily)
+	e.FloatMap("BUDGET_ROLE_MONTHLY_USD", &c.Budgets.RoleMonthly)
+	e.Float("BUDGET_GLOBAL_DAILY_USD", &c.Budgets.GlobalDaily)
+	e.Float("BUDGET_GLOBAL_MONTHLY_USD", &c.Budgets.GlobalMonthly)
+
+	return errors.Join(e.errs...)
+}
+
+// envReader overwrites a target only when the variable is set and collects
+// parse errors so they can be reported together
+type envReader struct {
+	errs []error
+}
+
+func (e *envReader) lookup(key string) (string, bool) {
+	value, ok := os.LookupEnv(key)
+	value = strings.TrimSpace(value)
+	return value, ok && value != ""
+}
+
+func (e *envReader) add(key string, err error) {
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: %w", key, err))
+	}
+}
+
+func (e *envReader) String(key string, target *string) {
+	if value, ok := e.lookup(key); ok {
+		*target = value
+	}
+}
+
+func (e *envReader) Int(key string, target *int) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.Atoi(value)
+		e.add(key, err)
+		if err == nil {
+			*target = parsed
+		}
+	}
+}
+
+func (e *envReader) Int64(key string, target *int64) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		e.add(key, err)
+		if err == nil {
+			*target = parsed
+		}
+	}
+}
+
+func (e *envReader) Bool(key string, target *bool) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.ParseBool(value)
+		e.add(key, err)
+		if err == nil {
+			*target = parsed
+		}
+	}
+}
+
+func (e *envReader) Float(key string, target *float64) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.ParseFloat(value, 64)
+		e.add(key, err)
+		if err == nil {
+			*target = parsed
+		}
+	}
+}
+
+func (e *envReader) Duration(key string, target *time.Duration) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := time.ParseDuration(value)
+		e.add(key, err)
+		if err == nil {
+			*target = parsed
+		}
+	}
+}
+
+func (e *envReader) LogLevel(key string, target *logrus.Level) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := logrus.ParseLevel(value)
+		e.add(key, err)
+		if err == nil {
+			*target = parsed
+		}
+	}
+}
+
+func (e *envReader) List(key string, target *[]string) {
+	if value, ok := e.lookup(key); ok {
+		var list []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		*target = list
+	}
+}
+
+func (e *envReader) FloatMap(key string, target *map[string]float64) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := parseFloatMap(value)
+		e.add(key, err)
+		if err == nil {
+			*target = parsed
+		}
+	}
+}
+
+// parsePrices parses "model=prompt/completion,..." where prices are USD per 1K tokens
+func parsePrices(value string) (map[string]ModelPrice, error) {
+	prices := make(map[string]ModelPrice)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		model, price, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid price %q, expected model=prompt/completion", entry)
+		}
+		prompt, completion, ok := strings.Cut(price, "/")
+		if !ok {
+			completion = prompt
+		}
+		promptPrice, err := strconv.ParseFloat(strings.TrimSpace(prompt), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prompt price for %s: %w", model, err)
+		}
+		completionPrice, err := strconv.ParseFloat(strings.TrimSpace(completion), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid completion price for %s: %w", model, err)
+		}
+		prices[strings.TrimSpace(model)] = ModelPrice{PromptPer1K: promptPrice, CompletionPer1K: completionPrice}
+	}
+	return prices, nil
+}
+
+// parseFloatMap parses "key=value,..." into a map
+func parseFloatMap(value string) (map[string]float64, error) {
+	result := make(map[string]float64)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, raw, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid entry %q, expected key=value", entry)
+		}
+		floatValue, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		result[strings.TrimSpace(key)] = floatValue
+	}
+	return result, nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"net/url"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redactedValue = "[REDACTED]"
+
+// Redacted returns a copy of the configuration with secrets masked
+func (c *Config) Redacted() *Config {
+	masked := *c
+	masked.JWTSecret = redactString(c.JWTSecret)
+	masked.GoogleClientSecret = redactString(c.GoogleClientSecret)
+	masked.OpenAIAPIKey = redactString(c.OpenAIAPIKey)
+	masked.DatabaseURL = redactURL(c.DatabaseURL)
+	return &masked
+}
+
+// YAML renders the configuration in the format accepted by --config
+func (c *Config) YAML() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+func redactString(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// redactURL masks the password in a connection URL but keeps the host and
+// database visible
+func redactURL(value string) string {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return redactString(value)
+	}
+	if _, hasPassword := parsed.User.Password(); hasPassword {
+		parsed.User = url.UserPassword(parsed.User.Username(), "xxxxx")
+	}
+	return parsed.String()
+}
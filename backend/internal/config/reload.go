@@ -0,0 +1,151 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// reloadableFields are the top-level yaml keys that can change at runtime.
+// Everything else (ports, database, secrets, timeouts) needs a restart.
+var reloadableFields = map[string]bool{
+	"log_level":         true,
+	"rate_limit":        true,
+	"openai_model":      true,
+	"openai_max_tokens": true,
+	"llm_prices":        true,
+	"budgets":           true,
+	"abstention":        true,
+	"redaction":         true,
+	"moderation":        true,
+}
+
+// ReloadResult describes what a reload changed
+type ReloadResult struct {
+	Applied []string `json:"applied"`
+	// Ignored lists changed settings that only take effect after a restart
+	Ignored []string `json:"ignored"`
+}
+
+// Manager holds the current configuration and re-reads it on demand.
+// Components opt in to hot reload with Subscribe.
+type Manager struct {
+	args    []string
+	current atomic.Pointer[Config]
+
+	mu          sync.Mutex
+	subscribers []func(*Config)
+	fileStamp   string
+}
+
+// NewManager wraps cfg; args are the command-line flags used by Load on reload
+func NewManager(cfg *Config, args []string) *Manager {
+	m := &Manager{args: args}
+	m.current.Store(cfg)
+	m.fileStamp = fileStamp(cfg.ConfigFile)
+	return m
+}
+
+// Current returns the active configuration. Callers must not modify it.
+func (m *Manager) Current() *Config {
+	return m.current.Load()
+}
+
+// Subscribe registers fn to be called with the new configuration after every
+// successful reload that changed a reloadable setting
+func (m *Manager) Subscribe(fn func(*Config)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscribers = append(m.subscribers, fn)
+}
+
+// Reload reads the configuration again and applies the reloadable sections.
+// An invalid configuration is rejected and the current one stays active.
+func (m *Manager) Reload() (*ReloadResult, error) {
+	loaded, err := Load(m.args)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old := m.current.Load()
+	next := *old
+	result := &ReloadResult{}
+
+	oldValue := reflect.ValueOf(old).Elem()
+	loadedValue := reflect.ValueOf(loaded).Elem()
+	nextValue := reflect.ValueOf(&next).Elem()
+	for i := 0; i < oldValue.NumField(); i++ {
+		name := oldValue.Type().Field(i).Tag.Get("yaml")
+		if name == "" || name == "-" {
+			continue
+		}
+		if reflect.DeepEqual(oldValue.Field(i).Interface(), loadedValue.Field(i).Interface()) {
+			continue
+		}
+		if !reloadableFields[name] {
+			result.Ignored = append(result.Ignored, name)
+			continue
+		}
+		nextValue.Field(i).Set(loadedValue.Field(i))
+		result.Applied = append(result.Applied, name)
+	}
+
+	if len(result.Applied) == 0 {
+		return result, nil
+	}
+
+	m.current.Store(&next)
+	for _, fn := range m.subscribers {
+		fn(&next)
+	}
+	return result, nil
+}
+
+// Watch polls the config file and reloads when it changes, until ctx is done.
+// It does nothing when no config file is in use or interval is not positive.
+func (m *Manager) Watch(ctx context.Context, interval time.Duration, onReload func(*ReloadResult, error)) {
+	path := m.Current().ConfigFile
+	if path == "" || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Record the new stamp first so an invalid file is reported once
+			// rather than on every tick
+			stamp := fileStamp(path)
+			m.mu.Lock()
+			changed := stamp != m.fileStamp
+			m.fileStamp = stamp
+			m.mu.Unlock()
+			if changed {
+				onReload(m.Reload())
+			}
+		}
+	}
+}
+
+// fileStamp identifies a version of the file by size and modification time
+func fileStamp(path string) string {
+	if path == "" {
+		return ""
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", info.Size(), info.ModTime().UnixNano())
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// localePattern matches locales of the form "en" or "pt-BR"
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// minProductionSecretLength is the shortest JWT secret accepted in production
+const minProductionSecretLength = 32
+
+// Validate checks the configuration and returns every problem found. In
+// production it also refuses development defaults and missing credentials.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	positive := func(name string, d time.Duration) {
+		check(d > 0, "%s must be positive, got %s", name, d)
+	}
+
+	port, err := strconv.Atoi(c.Port)
+	check(err == nil && port > 0 && port < 65536, "port must be between 1 and 65535, got %q", c.Port)
+
+	switch c.Environment {
+	case EnvDevelopment, EnvStaging, EnvProduction:
+	default:
+		check(false, "environment must be one of development, staging or production, got %q", c.Environment)
+	}
+
+	check(c.DatabaseURL != "", "database_url is required")
+	check(c.JWTSecret != "", "jwt_secret is required")
+	if _, err := url.ParseRequestURI(c.OpenAIEndpoint); err != nil {
+		check(false, "openai_endpoint must be a URL: %v", err)
+	}
+	check(c.OpenAIModel != "", "openai_model is required")
+	check(c.OpenAIMaxTokens > 0, "openai_max_tokens must be positive, got %d", c.OpenAIMaxTokens)
+
+	positive("server.read_timeout", c.Server.ReadTimeout)
+	positive("server.write_timeout", c.Server.WriteTimeout)
+	positive("server.idle_timeout", c.Server.IdleTimeout)
+	positive("server.shutdown_timeout", c.Server.ShutdownTimeout)
+
+	check(c.Database.MaxOpenConns > 0, "database.max_open_conns must be positive, got %d", c.Database.MaxOpenConns)
+	check(c.Database.MaxIdleConns >= 0 && c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+		"database.max_idle_conns must be between 0 and max_open_conns, got %d", c.Database.MaxIdleConns)
+	check(c.Database.ConnMaxLifetime >= 0, "database.conn_max_lifetime must not be negative")
+	check(c.Database.ConnMaxIdleTime >= 0, "database.conn_max_idle_time must not be negative")
+
+	check(c.RateLimit.Requests > 0, "rate_limit.requests must be positive, got %d", c.RateLimit.Requests)
+	positive("rate_limit.window", c.RateLimit.Window)
+
+	positive("llm_client.connect_timeout", c.LLMClient.ConnectTimeout)
+	positive("llm_client.request_timeout", c.LLMClient.RequestTimeout)
+	check(c.LLMClient.MaxRetries >= 0, "llm_client.max_retries must not be negative")
+	positive("llm_client.retry_base_delay", c.LLMClient.RetryBaseDelay)
+	check(c.LLMClient.RetryMaxDelay >= c.LLMClient.RetryBaseDelay, "llm_client.retry_max_delay must not be below retry_base_delay")
+	check(c.LLMClient.BreakerFailures > 0, "llm_client.breaker_failures must be positive")
+	positive("llm_client.breaker_cooldown", c.LLMClient.BreakerCooldown)
+
+	positive("timeouts.db_query", c.Timeouts.DBQuery)
+	positive("timeouts.oauth", c.Timeouts.OAuth)
+	positive("timeouts.llm_verify", c.Timeouts.LLMVerify)
+
+	positive("health.check_timeout", c.Health.CheckTimeout)
+	check(c.Health.CacheTTL >= 0 && c.Health.LLMCacheTTL >= 0, "health cache TTLs must not be negative")
+
+	positive("stream.retention", c.Stream.Retention)
+	positive("stream.keepalive", c.Stream.Keepalive)
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		check(false, "tracing.exporter must be none, stdout or otlp, got %q", c.Tracing.Exporter)
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1")
+
+	positive("webhooks.poll_interval", c.Webhooks.PollInterval)
+	positive("webhooks.timeout", c.Webhooks.Timeout)
+	check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts must be positive, got %d", c.Webhooks.MaxAttempts)
+	positive("webhooks.retry_base_delay", c.Webhooks.RetryBaseDelay)
+	check(c.Webhooks.RetryMaxDelay >= c.Webhooks.RetryBaseDelay, "webhooks.retry_max_delay must not be below retry_base_delay")
+
+	check(c.Queue.Workers > 0, "queue.workers must be positive, got %d", c.Queue.Workers)
+	positive("queue.poll_interval", c.Queue.PollInterval)
+	check(c.Queue.MaxAttempts > 0, "queue.max_attempts must be positive, got %d", c.Queue.MaxAttempts)
+	check(c.Queue.MaxBatchRows > 0, "queue.max_batch_rows must be positive, got %d", c.Queue.MaxBatchRows)
+
+	check(c.Privacy.DeletionGracePeriod >= 0, "privacy.deletion_grace_period must not be negative")
+	positive("privacy.deletion_check_interval", c.Privacy.DeletionCheckInterval)
+
+	check(c.Publisher.Name != "", "publisher.name is required")
+	if c.Publisher.URL != "" {
+		u, err := url.Parse(c.Publisher.URL)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "publisher.url must be an absolute http(s) URL, got %q", c.Publisher.URL)
+	}
+
+	positive("links.timeout", c.Links.Timeout)
+	check(c.Links.MaxRedirects >= 0, "links.max_redirects must not be negative, got %d", c.Links.MaxRedirects)
+	check(c.Links.MaxBodyBytes > 0, "links.max_body_bytes must be positive, got %d", c.Links.MaxBodyBytes)
+
+	switch c.Language.Mode {
+	case LanguageModeSource, LanguageModeTranslate:
+	default:
+		check(false, "language.mode must be source or translate, got %q", c.Language.Mode)
+	}
+	check(localePattern.MatchString(c.Language.DefaultLocale), "language.default_locale must look like en or pt-BR, got %q", c.Language.DefaultLocale)
+
+	switch c.Ensemble.Voting {
+	case VotingMajority, VotingWeighted, VotingUnanimous:
+	default:
+		check(false, "ensemble.voting must be majority, weighted or unanimous, got %q", c.Ensemble.Voting)
+	}
+	for i, model := range c.Ensemble.Models {
+		check(model != "", "ensemble.models[%d] must not be empty", i)
+	}
+
+	switch c.Calibration.Method {
+	case "platt", "isotonic":
+	default:
+		check(false, "calibration.method must be platt or isotonic, got %q", c.Calibration.Method)
+	}
+	positive("calibration.interval", c.Calibration.Interval)
+	check(c.Calibration.MinSamples > 0, "calibration.min_samples must be positive, got %d", c.Calibration.MinSamples)
+	check(c.Calibration.MaxSamples >= c.Calibration.MinSamples, "calibration.max_samples must not be below min_samples")
+	check(c.Abstention.Threshold >= 0 && c.Abstention.Threshold <= 1, "abstention.threshold must be between 0 and 1")
+	for topic, threshold := range c.Abstention.Topics {
+		check(threshold >= 0 && threshold <= 1, "abstention.topics.%s must be between 0 and 1", topic)
+	}
+
+	for _, detector := range c.Redaction.Detectors {
+		check(containsString(PIIDetectors, detector), "redaction.detectors must be among %v, got %q", PIIDetectors, detector)
+	}
+
+	check(containsString(ModerationActions, c.Moderation.OnError), "moderation.on_error must be allow, quarantine or reject, got %q", c.Moderation.OnError)
+	for i, rule := range c.Moderation.Rules {
+		check(rule.Category != "", "moderation.rules[%d].category is required", i)
+		check(rule.Action == "quarantine" || rule.Action == "reject", "moderation.rules[%d].action must be quarantine or reject, got %q", i, rule.Action)
+		check(len(rule.Keywords) > 0 || rule.Pattern != "", "moderation.rules[%d] needs keywords or a pattern", i)
+		if rule.Pattern != "" {
+			_, err := regexp.Compile(rule.Pattern)
+			check(err == nil, "moderation.rules[%d].pattern is not a valid regular expression: %v", i, err)
+		}
+	}
+
+	check(c.ConfigWatch >= 0, "config_watch_interval must not be negative")
+
+	for model, price := range c.LLMPrices {
+		check(price.PromptPer1K >= 0 && price.CompletionPer1K >= 0, "llm_prices.%s must not be negative", model)
+	}
+	check(c.Budgets.UserDaily >= 0 && c.Budgets.UserMonthly >= 0 &&
+		c.Budgets.GlobalDaily >= 0 && c.Budgets.GlobalMonthly >= 0, "budgets must not be negative")
+	for role, limit := range c.Budgets.RoleDaily {
+		check(limit >= 0, "budgets.role_daily_usd.%s must not be negative", role)
+	}
+	for role, limit := range c.Budgets.RoleMonthly {
+		check(limit >= 0, "budgets.role_monthly_usd.%s must not be negative", role)
+	}
+
+	if c.IsProduction() {
+		check(c.JWTSecret != defaultJWTSecret, "jwt_secret must be changed from the development default in production")
+		check(len(c.JWTSecret) >= minProductionSecretLength, "jwt_secret must be at least %d characters in production", minProductionSecretLength)
+		check(c.DatabaseURL != defaultDatabaseURL, "database_url must be set in production")
+		check(c.GoogleClientID != "" && c.GoogleClientSecret != "", "google_client_id and google_client_secret are required in production")
+		check(!c.Webhooks.AllowPrivateTargets, "webhooks.allow_private_targets must be off in production")
+		check(!c.Links.AllowPrivateTargets, "links.allow_private_targets must be off in production")
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
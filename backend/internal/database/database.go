@@ -1,34 +1,43 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"time"
 
+	"fact-check/internal/config"
+
 	_ "github.com/lib/pq"
 )
 
-func NewConnection(databaseURL string) (*sql.DB, error) {
+func NewConnection(ctx context.Context, databaseURL string, pool config.DatabaseConfig) (*sql.DB, error) {
 	db, err := sql.Open("postgres", databaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
 
 	// Set connection pool settings
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(25)
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
 
 	// Retry connection with exponential backoff
 	maxRetries := 30
 	retryDelay := time.Second
 
 	for i := 0; i < maxRetries; i++ {
-		if err := db.Ping(); err != nil {
+		if err := db.PingContext(ctx); err != nil {
 			log.Printf("Database connection attempt %d failed: %v", i+1, err)
 			if i < maxRetries-1 {
 				log.Printf("Retrying in %v...", retryDelay)
-				time.Sleep(retryDelay)
+				select {
+				case <-ctx.Done():
+					return nil, fmt.Errorf("database connection cancelled: %w", ctx.Err())
+				case <-time.After(retryDelay):
+				}
 				retryDelay = time.Duration(float64(retryDelay) * 1.5) // Exponential backoff
 				if retryDelay > 10*time.Second {
 					retryDelay = 10 * time.Second // Cap at 10 seconds
@@ -44,7 +53,7 @@ func NewConnection(databaseURL string) (*sql.DB, error) {
 	return db, nil
 }
 
-func RunMigrations(db *sql.DB) error {
+func RunMigrations(ctx context.Context, db *sql.DB) error {
 	// Create users table
 	createUsersTable := `
 	CREATE TABLE IF NOT EXISTS users (
@@ -79,11 +88,239 @@ func RunMigrations(db *sql.DB) error {
 	CREATE INDEX IF NOT EXISTS idx_users_google_id ON users(google_id);
 	CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);`
 
+	// Add user roles
+	addUserRole := `
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';`
+
+	// Create LLM usage table
+	createLLMUsageTable := `
+	CREATE TABLE IF NOT EXISTS llm_usage (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		news_id UUID REFERENCES news(id) ON DELETE SET NULL,
+		model VARCHAR(100) NOT NULL,
+		prompt_tokens INTEGER NOT NULL DEFAULT 0,
+		completion_tokens INTEGER NOT NULL DEFAULT 0,
+		total_tokens INTEGER NOT NULL DEFAULT 0,
+		cost_usd NUMERIC(12, 6) NOT NULL DEFAULT 0,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_llm_usage_user_created_at ON llm_usage(user_id, created_at);
+	CREATE INDEX IF NOT EXISTS idx_llm_usage_created_at ON llm_usage(created_at);`
+
+	// Create organizations and webhook tables
+	createOrganizationTables := `
+	CREATE TABLE IF NOT EXISTS organizations (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		name VARCHAR(255) NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE IF NOT EXISTS organization_members (
+		organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		role VARCHAR(20) NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'member')),
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (organization_id, user_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON organization_members(user_id);`
+
+	createWebhookTables := `
+	CREATE TABLE IF NOT EXISTS webhook_endpoints (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+		organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE,
+		url VARCHAR(2048) NOT NULL,
+		secret VARCHAR(255) NOT NULL,
+		events TEXT[] NOT NULL,
+		active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+		CHECK ((user_id IS NULL) <> (organization_id IS NULL))
+	);
+	CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_user_id ON webhook_endpoints(user_id);
+	CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_organization_id ON webhook_endpoints(organization_id);
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		endpoint_id UUID NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+		event_id UUID NOT NULL,
+		event VARCHAR(50) NOT NULL,
+		payload JSONB NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+		attempts INTEGER NOT NULL DEFAULT 0,
+		last_status_code INTEGER,
+		last_error TEXT,
+		next_attempt_at TIMESTAMP WITH TIME ZONE,
+		redelivery_of UUID REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+		delivered_at TIMESTAMP WITH TIME ZONE
+	);
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint_created_at ON webhook_deliveries(endpoint_id, created_at);
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';`
+
+	// The verifier also reports 'uncertain' verdicts
+	allowUncertainStatus := `
+	ALTER TABLE news DROP CONSTRAINT IF EXISTS news_status_check;
+	ALTER TABLE news ADD CONSTRAINT news_status_check CHECK (status IN ('pending', 'true', 'false', 'uncertain'));`
+
+	// Create batch and verification queue tables
+	createBatchTables := `
+	CREATE TABLE IF NOT EXISTS batches (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		total INTEGER NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+	);
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS batch_id UUID REFERENCES batches(id) ON DELETE SET NULL;
+	CREATE INDEX IF NOT EXISTS idx_news_batch_id ON news(batch_id);
+	CREATE TABLE IF NOT EXISTS verification_jobs (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		news_id UUID NOT NULL REFERENCES news(id) ON DELETE CASCADE,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		batch_id UUID REFERENCES batches(id) ON DELETE CASCADE,
+		status VARCHAR(20) NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'succeeded', 'failed')),
+		attempts INTEGER NOT NULL DEFAULT 0,
+		verdict VARCHAR(20),
+		error TEXT,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+		started_at TIMESTAMP WITH TIME ZONE,
+		finished_at TIMESTAMP WITH TIME ZONE
+	);
+	CREATE INDEX IF NOT EXISTS idx_verification_jobs_batch_id ON verification_jobs(batch_id);
+	CREATE INDEX IF NOT EXISTS idx_verification_jobs_pending ON verification_jobs(created_at) WHERE status IN ('queued', 'running');`
+
+	// Add account deletion tracking and the audit log
+	createAuditLog := `
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_for TIMESTAMP WITH TIME ZONE;
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+	CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_for ON users(deletion_scheduled_for) WHERE deletion_scheduled_for IS NOT NULL;
+	CREATE TABLE IF NOT EXISTS audit_log (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		action VARCHAR(100) NOT NULL,
+		actor_id UUID,
+		subject_id UUID NOT NULL,
+		details JSONB,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_audit_log_subject_created_at ON audit_log(subject_id, created_at);`
+
+	// Add news topics and an index for the published verdict feeds
+	addNewsTopic := `
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS topic VARCHAR(50);
+	CREATE INDEX IF NOT EXISTS idx_news_topic ON news(topic);
+	CREATE INDEX IF NOT EXISTS idx_news_published ON news(updated_at) WHERE status <> 'pending';`
+
+	// Create the source credibility registry
+	createSourcesTable := `
+	CREATE TABLE IF NOT EXISTS sources (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		domain VARCHAR(255) UNIQUE NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		credibility VARCHAR(20) NOT NULL CHECK (credibility IN ('high', 'mixed', 'low')),
+		ownership VARCHAR(255) NOT NULL DEFAULT '',
+		bias_notes TEXT NOT NULL DEFAULT '',
+		satire BOOLEAN NOT NULL DEFAULT FALSE,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	// Store canonicalized links next to the submitted ones
+	addCanonicalLink := `
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS canonical_link VARCHAR(2048);
+	CREATE INDEX IF NOT EXISTS idx_news_canonical_link ON news(canonical_link);`
+
+	addLanguages := `
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS language VARCHAR(16);
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(16);
+	CREATE TABLE IF NOT EXISTS news_translations (
+		id UUID PRIMARY KEY,
+		news_id UUID NOT NULL REFERENCES news(id) ON DELETE CASCADE,
+		locale VARCHAR(16) NOT NULL,
+		content TEXT NOT NULL,
+		explanation TEXT,
+		model VARCHAR(100) NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (news_id, locale)
+	);`
+
+	createPromptVersions := `
+	CREATE TABLE IF NOT EXISTS prompt_versions (
+		version SERIAL PRIMARY KEY,
+		description TEXT NOT NULL DEFAULT '',
+		system_prompt TEXT NOT NULL,
+		template TEXT NOT NULL,
+		weight INTEGER NOT NULL DEFAULT 0 CHECK (weight >= 0),
+		created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+	);
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS prompt_version INTEGER REFERENCES prompt_versions(version);
+	CREATE INDEX IF NOT EXISTS idx_news_prompt_version ON news(prompt_version);`
+
+	// Keep each ensemble member's verdict and flag disagreements for review
+	addModelVerdicts := `
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS model_verdicts JSONB;
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS needs_review BOOLEAN NOT NULL DEFAULT FALSE;
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL;
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP WITH TIME ZONE;
+	CREATE INDEX IF NOT EXISTS idx_news_needs_review ON news(updated_at) WHERE needs_review;`
+
+	// Store calibrated confidences and let low-confidence verdicts abstain
+	addCalibration := `
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS confidence DOUBLE PRECISION;
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS raw_confidence DOUBLE PRECISION;
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS model_status VARCHAR(20);
+	ALTER TABLE news DROP CONSTRAINT IF EXISTS news_status_check;
+	ALTER TABLE news ADD CONSTRAINT news_status_check CHECK (status IN ('pending', 'true', 'false', 'uncertain', 'unverifiable'));
+	CREATE INDEX IF NOT EXISTS idx_news_reviewed_at ON news(reviewed_at) WHERE reviewed_at IS NOT NULL;
+	CREATE TABLE IF NOT EXISTS calibrations (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		method VARCHAR(20) NOT NULL,
+		params JSONB NOT NULL,
+		samples INTEGER NOT NULL,
+		brier_before DOUBLE PRECISION NOT NULL,
+		brier_after DOUBLE PRECISION NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	// Let organizations choose whether personal data is redacted before
+	// content is sent to an LLM; NULL follows the server default
+	addOrganizationPolicy := `
+	ALTER TABLE organizations ADD COLUMN IF NOT EXISTS redact_pii BOOLEAN;`
+
+	// Hold submissions flagged by moderation until a moderator decides
+	addModeration := `
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS moderation VARCHAR(20) NOT NULL DEFAULT 'allowed'
+		CHECK (moderation IN ('allowed', 'quarantined', 'rejected'));
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS moderation_category VARCHAR(50);
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS moderation_reason TEXT;
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS moderated_by UUID REFERENCES users(id) ON DELETE SET NULL;
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMP WITH TIME ZONE;
+	CREATE INDEX IF NOT EXISTS idx_news_quarantined ON news(created_at) WHERE moderation = 'quarantined';`
+
+	// Batch rows wait in pending until the LLM has classified them
+	allowPendingModeration := `
+	ALTER TABLE news DROP CONSTRAINT IF EXISTS news_moderation_check;
+	ALTER TABLE news ADD CONSTRAINT news_moderation_check CHECK (moderation IN ('allowed', 'pending', 'quarantined', 'rejected'));`
+
+	// Remember which admins ADMIN_EMAILS promoted so they can be demoted
+	addAdminByEmail := `
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS admin_by_email BOOLEAN NOT NULL DEFAULT FALSE;`
+
+	addFetchedHost := `
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS fetched_host VARCHAR(253);`
+
+	addPublishing := `
+	ALTER TABLE news ADD COLUMN IF NOT EXISTS published BOOLEAN NOT NULL DEFAULT FALSE;
+	CREATE INDEX IF NOT EXISTS idx_news_published ON news(updated_at DESC) WHERE published;`
+
 	// Execute migrations
-	migrations := []string{createUsersTable, createNewsTable, createIndexes}
+	migrations := []string{createUsersTable, createNewsTable, createIndexes, addUserRole, createLLMUsageTable, createOrganizationTables, createWebhookTables,
+		allowUncertainStatus, createBatchTables, createAuditLog, addNewsTopic, createSourcesTable,
+		addCanonicalLink, addLanguages, createPromptVersions, addModelVerdicts, addCalibration, addOrganizationPolicy,
+		addModeration, addPublishing, addFetchedHost, allowPendingModeration, addAdminByEmail}
 
 	for _, migration := range migrations {
-		if _, err := db.Exec(migration); err != nil {
+		if _, err := db.ExecContext(ctx, migration); err != nil {
 			return fmt.Errorf("failed to execute migration: %w", err)
 		}
 	}
@@ -91,3 +328,21 @@ func RunMigrations(db *sql.DB) error {
 	log.Println("Database migrations completed successfully")
 	return nil
 }
+
+// Probe returns a health probe that pings the database and reports pool usage
+func Probe(db *sql.DB) func(ctx context.Context) (map[string]interface{}, error) {
+	return func(ctx context.Context) (map[string]interface{}, error) {
+		stats := db.Stats()
+		details := map[string]interface{}{
+			"open_connections": stats.OpenConnections,
+			"in_use":           stats.InUse,
+			"idle":             stats.Idle,
+			"wait_count":       stats.WaitCount,
+		}
+
+		if err := db.PingContext(ctx); err != nil {
+			return details, fmt.Errorf("database ping failed: %w", err)
+		}
+		return details, nil
+	}
+}
@@ -0,0 +1,201 @@
+// Package eval runs labeled claims through a verifier and scores the
+// verdicts: accuracy, macro-F1, confusion matrix, calibration, cost and
+// latency.
+package eval
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Verdict labels, matching the statuses verifiers return
+const (
+	LabelTrue      = "true"
+	LabelFalse     = "false"
+	LabelUncertain = "uncertain"
+)
+
+// Labels lists the verdict labels in report order
+var Labels = []string{LabelTrue, LabelFalse, LabelUncertain}
+
+// Dataset formats
+const (
+	FormatJSONL = "jsonl"
+	FormatTSV   = "tsv"
+)
+
+// ErrInvalidDataset is returned for rows that cannot be read or labeled
+var ErrInvalidDataset = errors.New("invalid dataset")
+
+// Example is one labeled claim
+type Example struct {
+	ID    string `json:"id"`
+	Claim string `json:"claim"`
+	Link  string `json:"link,omitempty"`
+	Label string `json:"label"`
+}
+
+// labelAliases maps the labels of common fact-checking datasets onto the
+// three verdicts: LIAR's six-point scale and FEVER's classes
+var labelAliases = map[string]string{
+	"true":            LabelTrue,
+	"mostly-true":     LabelTrue,
+	"supports":        LabelTrue,
+	"supported":       LabelTrue,
+	"false":           LabelFalse,
+	"barely-true":     LabelFalse,
+	"pants-fire":      LabelFalse,
+	"refutes":         LabelFalse,
+	"refuted":         LabelFalse,
+	"uncertain":       LabelUncertain,
+	"half-true":       LabelUncertain,
+	"not enough info": LabelUncertain,
+	"not_enough_info": LabelUncertain,
+	"unverifiable":    LabelUncertain,
+	"nei":             LabelUncertain,
+}
+
+// NormalizeLabel maps a dataset label onto a verdict label
+func NormalizeLabel(label string) (string, bool) {
+	normalized, ok := labelAliases[strings.ToLower(strings.TrimSpace(label))]
+	return normalized, ok
+}
+
+// FormatFor guesses the dataset format from a file name
+func FormatFor(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tsv", ".txt":
+		return FormatTSV
+	default:
+		return FormatJSONL
+	}
+}
+
+// Load reads a dataset in the given format
+func Load(r io.Reader, format string) ([]Example, error) {
+	switch format {
+	case FormatJSONL:
+		return loadJSONL(r)
+	case FormatTSV:
+		return loadTSV(r)
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidDataset, format)
+	}
+}
+
+// jsonlRow accepts the field names of our own datasets and of FEVER
+type jsonlRow struct {
+	ID        json.RawMessage `json:"id"`
+	Claim     string          `json:"claim"`
+	Content   string          `json:"content"`
+	Statement string          `json:"statement"`
+	Link      string          `json:"link"`
+	Label     string          `json:"label"`
+}
+
+func loadJSONL(r io.Reader) ([]Example, error) {
+	var examples []Example
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var row jsonlRow
+		if err := json.Unmarshal([]byte(text), &row); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidDataset, line, err)
+		}
+		claim := firstNonEmpty(row.Claim, row.Content, row.Statement)
+		example, err := newExample(line, rawID(row.ID), claim, row.Link, row.Label)
+		if err != nil {
+			return nil, err
+		}
+		examples = append(examples, example)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+	return examples, nil
+}
+
+// loadTSV reads the LIAR layout: id, label and statement in the first three
+// columns, any further columns ignored. A header row is skipped.
+func loadTSV(r io.Reader) ([]Example, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = '\t'
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+
+	var examples []Example
+	line := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidDataset, line, err)
+		}
+		if len(record) < 3 {
+			return nil, fmt.Errorf("%w: line %d: expected id, label and statement columns", ErrInvalidDataset, line)
+		}
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[1]), "label") {
+			continue
+		}
+
+		example, err := newExample(line, record[0], record[2], "", record[1])
+		if err != nil {
+			return nil, err
+		}
+		examples = append(examples, example)
+	}
+	return examples, nil
+}
+
+func newExample(line int, id, claim, link, label string) (Example, error) {
+	claim = strings.TrimSpace(claim)
+	if claim == "" {
+		return Example{}, fmt.Errorf("%w: line %d: claim is empty", ErrInvalidDataset, line)
+	}
+	normalized, ok := NormalizeLabel(label)
+	if !ok {
+		return Example{}, fmt.Errorf("%w: line %d: unknown label %q", ErrInvalidDataset, line, label)
+	}
+	if id = strings.TrimSpace(id); id == "" {
+		id = strconv.Itoa(line)
+	}
+	return Example{ID: id, Claim: claim, Link: strings.TrimSpace(link), Label: normalized}, nil
+}
+
+// rawID accepts string and numeric IDs
+func rawID(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	if id := strings.TrimSpace(string(raw)); id != "null" {
+		return id
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
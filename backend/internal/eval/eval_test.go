@@ -0,0 +1,136 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"fact-check/internal/models"
+	"fact-check/internal/services"
+)
+
+func TestLoad(t *testing.T) {
+	jsonl := `{"id": 7, "claim": "The moon is made of cheese", "label": "pants-fire"}
+{"statement": "Water boils at 100C at sea level", "label": "SUPPORTS", "link": "https://example.com"}
+
+{"id": "c3", "content": "Nobody knows", "label": "NOT ENOUGH INFO"}
+`
+	examples, err := Load(strings.NewReader(jsonl), FormatJSONL)
+	if err != nil {
+		t.Fatalf("Load jsonl: %v", err)
+	}
+	want := []Example{
+		{ID: "7", Claim: "The moon is made of cheese", Label: LabelFalse},
+		{ID: "2", Claim: "Water boils at 100C at sea level", Link: "https://example.com", Label: LabelTrue},
+		{ID: "c3", Claim: "Nobody knows", Label: LabelUncertain},
+	}
+	if len(examples) != len(want) {
+		t.Fatalf("got %d examples, want %d: %+v", len(examples), len(want), examples)
+	}
+	for i := range want {
+		if examples[i] != want[i] {
+			t.Errorf("example %d = %+v, want %+v", i, examples[i], want[i])
+		}
+	}
+
+	tsv := "id\tlabel\tstatement\n" +
+		"2635.json\tfalse\tSays the Annies List political group supports abortions.\n" +
+		"10540.json\thalf-true\tHealth care costs are up.\n"
+	examples, err = Load(strings.NewReader(tsv), FormatFor("train.tsv"))
+	if err != nil {
+		t.Fatalf("Load tsv: %v", err)
+	}
+	if len(examples) != 2 || examples[0].ID != "2635.json" || examples[0].Label != LabelFalse || examples[1].Label != LabelUncertain {
+		t.Fatalf("unexpected tsv examples: %+v", examples)
+	}
+
+	if _, err := Load(strings.NewReader(`{"claim": "x", "label": "sort of"}`), FormatJSONL); err == nil {
+		t.Fatal("expected an unknown label to be rejected")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	results := []Result{
+		{ID: "1", Label: LabelTrue, Predicted: LabelTrue, Correct: true, Confidence: 0.95, LatencyMS: 100, CostUSD: 0.01},
+		{ID: "2", Label: LabelTrue, Predicted: LabelFalse, Confidence: 0.85, LatencyMS: 200, CostUSD: 0.01},
+		{ID: "3", Label: LabelFalse, Predicted: LabelFalse, Correct: true, Confidence: 0.85, LatencyMS: 300, CostUSD: 0.01},
+		{ID: "4", Label: LabelUncertain, Predicted: LabelFalse, LatencyMS: 400, CostUSD: 0.01},
+		{ID: "5", Label: LabelFalse, LatencyMS: 500, Error: "provider down"},
+	}
+	report := Summarize(RunInfo{Dataset: "test.jsonl", Provider: "stub"}, results)
+
+	if report.Examples != 5 || report.Scored != 4 || report.Errors != 1 {
+		t.Fatalf("counts = %d/%d/%d", report.Examples, report.Scored, report.Errors)
+	}
+	if report.Accuracy != 0.5 {
+		t.Errorf("accuracy = %v, want 0.5", report.Accuracy)
+	}
+	if got := report.Confusion[LabelTrue][LabelFalse]; got != 1 {
+		t.Errorf("confusion[true][false] = %d, want 1", got)
+	}
+	// true: P=1 R=0.5 F1=0.6667; false: P=1/3 R=1 F1=0.5; uncertain: 0
+	if report.MacroF1 != 0.3889 {
+		t.Errorf("macro F1 = %v, want 0.3889", report.MacroF1)
+	}
+	// bin [0.9,1.0): 1 correct at 0.95; bin [0.8,0.9): 1 of 2 at 0.85
+	if report.Calibration.Count != 3 {
+		t.Errorf("calibrated count = %d, want 3", report.Calibration.Count)
+	}
+	if report.Calibration.ECE != 0.25 {
+		t.Errorf("ECE = %v, want 0.25", report.Calibration.ECE)
+	}
+	if report.Cost.TotalUSD != 0.04 {
+		t.Errorf("total cost = %v, want 0.04", report.Cost.TotalUSD)
+	}
+	if report.Latency.P50MS != 300 || report.Latency.MaxMS != 500 {
+		t.Errorf("latency = %+v", report.Latency)
+	}
+}
+
+type fixedVerifier map[string]string
+
+func (v fixedVerifier) VerifyNews(ctx context.Context, request *services.VerificationRequest) (*services.VerificationResult, error) {
+	return &services.VerificationResult{
+		Status:     v[request.Content],
+		Confidence: 0.9,
+		Model:      "fixed",
+		Usage:      models.TokenUsage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120},
+	}, nil
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	examples := []Example{
+		{ID: "a", Claim: "first", Label: LabelTrue},
+		{ID: "b", Claim: "second", Label: LabelFalse},
+		{ID: "c", Claim: "third", Label: LabelUncertain},
+	}
+	provider := fixedVerifier{"first": LabelTrue, "second": LabelTrue, "third": LabelUncertain}
+
+	var recorded bytes.Buffer
+	cost := func(model string, usage models.TokenUsage) float64 { return 0.001 }
+	live := Run(context.Background(), NewRecorder(provider, &recorded), examples, Options{Concurrency: 2, Cost: cost})
+
+	replayer, err := LoadReplayer(&recorded)
+	if err != nil {
+		t.Fatalf("LoadReplayer: %v", err)
+	}
+	replayed := Run(context.Background(), replayer, append(examples, Example{ID: "d", Claim: "unseen", Label: LabelTrue}), Options{Cost: cost})
+
+	for i, result := range live {
+		if result.ID != examples[i].ID {
+			t.Fatalf("results out of dataset order: %+v", live)
+		}
+		if replayed[i].Predicted != result.Predicted || replayed[i].Confidence != result.Confidence || replayed[i].PromptTokens != 100 {
+			t.Errorf("replayed %+v, recorded %+v", replayed[i], result)
+		}
+	}
+	if replayed[3].Error != ErrNotRecorded.Error() {
+		t.Errorf("unrecorded claim error = %q", replayed[3].Error)
+	}
+
+	report := Summarize(RunInfo{}, replayed)
+	if report.Scored != 3 || report.Errors != 1 || report.Accuracy != 0.6667 {
+		t.Errorf("report = %d scored, %d errors, accuracy %v", report.Scored, report.Errors, report.Accuracy)
+	}
+}
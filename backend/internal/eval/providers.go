@@ -0,0 +1,129 @@
+package eval
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"fact-check/internal/models"
+	"fact-check/internal/services"
+)
+
+// ErrNotRecorded is returned by a Replayer for claims missing from the recording
+var ErrNotRecorded = errors.New("no recorded response for claim")
+
+// Recording is one provider answer, keyed by the claim it was given
+type Recording struct {
+	Claim       string            `json:"claim"`
+	Status      string            `json:"status,omitempty"`
+	Explanation string            `json:"explanation,omitempty"`
+	Confidence  float64           `json:"confidence,omitempty"`
+	Model       string            `json:"model,omitempty"`
+	Usage       models.TokenUsage `json:"usage"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// Recorder passes calls through to a verifier and appends every answer to
+// a JSONL stream that a Replayer can serve later
+type Recorder struct {
+	verifier services.Verifier
+	mu       sync.Mutex
+	encoder  *json.Encoder
+}
+
+func NewRecorder(verifier services.Verifier, w io.Writer) *Recorder {
+	return &Recorder{verifier: verifier, encoder: json.NewEncoder(w)}
+}
+
+func (r *Recorder) VerifyNews(ctx context.Context, request *services.VerificationRequest) (*services.VerificationResult, error) {
+	result, err := r.verifier.VerifyNews(ctx, request)
+
+	recording := Recording{Claim: request.Content}
+	if err != nil {
+		recording.Error = err.Error()
+	} else {
+		recording.Status = result.Status
+		recording.Explanation = result.Explanation
+		recording.Confidence = result.Confidence
+		recording.Model = result.Model
+		recording.Usage = result.Usage
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if encodeErr := r.encoder.Encode(recording); encodeErr != nil && err == nil {
+		return nil, fmt.Errorf("failed to record response: %w", encodeErr)
+	}
+	return result, err
+}
+
+// Replayer answers with recorded responses instead of calling a provider
+type Replayer struct {
+	recordings map[string]Recording
+}
+
+// LoadReplayer reads recordings written by a Recorder. Later recordings of
+// the same claim win.
+func LoadReplayer(r io.Reader) (*Replayer, error) {
+	replayer := &Replayer{recordings: make(map[string]Recording)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var recording Recording
+		if err := json.Unmarshal(scanner.Bytes(), &recording); err != nil {
+			return nil, fmt.Errorf("invalid recording on line %d: %w", line, err)
+		}
+		replayer.recordings[recording.Claim] = recording
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recordings: %w", err)
+	}
+	return replayer, nil
+}
+
+func (r *Replayer) VerifyNews(ctx context.Context, request *services.VerificationRequest) (*services.VerificationResult, error) {
+	recording, ok := r.recordings[request.Content]
+	if !ok {
+		return nil, ErrNotRecorded
+	}
+	if recording.Error != "" {
+		return nil, errors.New(recording.Error)
+	}
+	return &services.VerificationResult{
+		Status:      recording.Status,
+		Explanation: recording.Explanation,
+		Confidence:  recording.Confidence,
+		Model:       recording.Model,
+		Usage:       recording.Usage,
+	}, nil
+}
+
+// Stub answers every claim with the same verdict, for checking a dataset
+// and the pipeline without a provider
+type Stub struct {
+	Status string
+}
+
+func (s Stub) VerifyNews(ctx context.Context, request *services.VerificationRequest) (*services.VerificationResult, error) {
+	return &services.VerificationResult{
+		Status:      s.Status,
+		Explanation: "Stub verdict",
+		Model:       "stub",
+	}, nil
+}
+
+var (
+	_ services.Verifier = (*Recorder)(nil)
+	_ services.Verifier = (*Replayer)(nil)
+	_ services.Verifier = Stub{}
+)
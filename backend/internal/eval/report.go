@@ -0,0 +1,270 @@
+package eval
+
+import (
+	"math"
+	"sort"
+)
+
+// calibrationBins is the number of equal-width confidence bins used for ECE
+const calibrationBins = 10
+
+// Result is the outcome for one example
+type Result struct {
+	ID         string  `json:"id"`
+	Label      string  `json:"label"`
+	Predicted  string  `json:"predicted,omitempty"`
+	Correct    bool    `json:"correct"`
+	Confidence float64 `json:"confidence,omitempty"`
+	Model      string  `json:"model,omitempty"`
+	// LatencyMS is rounded to whole milliseconds so reports diff cleanly
+	LatencyMS        int64   `json:"latency_ms"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+	Error            string  `json:"error,omitempty"`
+}
+
+// Report summarizes an evaluation run. Maps marshal with sorted keys and
+// results keep dataset order, so two reports can be compared with diff.
+type Report struct {
+	Run         RunInfo                   `json:"run"`
+	Examples    int                       `json:"examples"`
+	Scored      int                       `json:"scored"`
+	Errors      int                       `json:"errors"`
+	Accuracy    float64                   `json:"accuracy"`
+	MacroF1     float64                   `json:"macro_f1"`
+	Classes     map[string]ClassMetrics   `json:"classes"`
+	Confusion   map[string]map[string]int `json:"confusion"`
+	Calibration Calibration               `json:"calibration"`
+	Cost        CostSummary               `json:"cost"`
+	Latency     LatencySummary            `json:"latency"`
+	Results     []Result                  `json:"results"`
+}
+
+// RunInfo describes what was evaluated
+type RunInfo struct {
+	Dataset     string `json:"dataset"`
+	Provider    string `json:"provider"`
+	Model       string `json:"model,omitempty"`
+	Prompt      string `json:"prompt,omitempty"`
+	Concurrency int    `json:"concurrency"`
+}
+
+// ClassMetrics are the one-vs-rest scores of a label
+type ClassMetrics struct {
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+	Support   int     `json:"support"`
+}
+
+// Calibration compares stated confidence with observed accuracy. Only
+// verdicts that came with a confidence are counted.
+type Calibration struct {
+	ECE   float64          `json:"ece"`
+	Count int              `json:"count"`
+	Bins  []CalibrationBin `json:"bins"`
+}
+
+// CalibrationBin covers confidences in [Lower, Upper)
+type CalibrationBin struct {
+	Lower          float64 `json:"lower"`
+	Upper          float64 `json:"upper"`
+	Count          int     `json:"count"`
+	Accuracy       float64 `json:"accuracy"`
+	MeanConfidence float64 `json:"mean_confidence"`
+}
+
+type CostSummary struct {
+	TotalUSD         float64 `json:"total_usd"`
+	MeanUSD          float64 `json:"mean_usd"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+}
+
+// LatencySummary is in milliseconds over all calls, failed ones included
+type LatencySummary struct {
+	MeanMS int64 `json:"mean_ms"`
+	P50MS  int64 `json:"p50_ms"`
+	P90MS  int64 `json:"p90_ms"`
+	P99MS  int64 `json:"p99_ms"`
+	MaxMS  int64 `json:"max_ms"`
+}
+
+// Summarize computes the report for results in dataset order. Failed calls
+// count towards cost and latency but not towards the scores.
+func Summarize(run RunInfo, results []Result) *Report {
+	report := &Report{
+		Run:       run,
+		Examples:  len(results),
+		Classes:   map[string]ClassMetrics{},
+		Confusion: map[string]map[string]int{},
+		Results:   results,
+	}
+	for _, label := range Labels {
+		report.Confusion[label] = map[string]int{}
+	}
+
+	correct := 0
+	latencies := make([]int64, 0, len(results))
+	for _, result := range results {
+		latencies = append(latencies, result.LatencyMS)
+		report.Cost.TotalUSD += result.CostUSD
+		report.Cost.PromptTokens += result.PromptTokens
+		report.Cost.CompletionTokens += result.CompletionTokens
+
+		if result.Error != "" {
+			report.Errors++
+			continue
+		}
+		report.Scored++
+		if report.Confusion[result.Label] == nil {
+			report.Confusion[result.Label] = map[string]int{}
+		}
+		report.Confusion[result.Label][result.Predicted]++
+		if result.Correct {
+			correct++
+		}
+	}
+
+	if report.Scored > 0 {
+		report.Accuracy = round(float64(correct) / float64(report.Scored))
+	}
+	report.Classes, report.MacroF1 = classMetrics(report.Confusion)
+	report.Calibration = calibrate(results)
+	report.Cost.TotalUSD = math.Round(report.Cost.TotalUSD*1e6) / 1e6
+	if len(results) > 0 {
+		report.Cost.MeanUSD = math.Round(report.Cost.TotalUSD/float64(len(results))*1e6) / 1e6
+	}
+	report.Latency = summarizeLatency(latencies)
+	return report
+}
+
+// classMetrics returns per-label scores and their unweighted mean over the
+// labels that occur as truth or prediction
+func classMetrics(confusion map[string]map[string]int) (map[string]ClassMetrics, float64) {
+	labels := map[string]bool{}
+	for truth, row := range confusion {
+		for predicted, n := range row {
+			if n > 0 {
+				labels[truth] = true
+				labels[predicted] = true
+			}
+		}
+	}
+
+	classes := map[string]ClassMetrics{}
+	sum := 0.0
+	for label := range labels {
+		truePositives := confusion[label][label]
+		predicted, support := 0, 0
+		for truth, row := range confusion {
+			predicted += row[label]
+			if truth == label {
+				for _, n := range row {
+					support += n
+				}
+			}
+		}
+
+		metrics := ClassMetrics{Support: support}
+		if predicted > 0 {
+			metrics.Precision = float64(truePositives) / float64(predicted)
+		}
+		if support > 0 {
+			metrics.Recall = float64(truePositives) / float64(support)
+		}
+		if metrics.Precision+metrics.Recall > 0 {
+			metrics.F1 = 2 * metrics.Precision * metrics.Recall / (metrics.Precision + metrics.Recall)
+		}
+		sum += metrics.F1
+		metrics.Precision, metrics.Recall, metrics.F1 = round(metrics.Precision), round(metrics.Recall), round(metrics.F1)
+		classes[label] = metrics
+	}
+
+	if len(labels) == 0 {
+		return classes, 0
+	}
+	return classes, round(sum / float64(len(labels)))
+}
+
+// calibrate computes the expected calibration error: the gap between mean
+// confidence and accuracy per bin, weighted by the bin's share of verdicts
+func calibrate(results []Result) Calibration {
+	type bin struct {
+		count, correct int
+		confidence     float64
+	}
+	bins := make([]bin, calibrationBins)
+	calibration := Calibration{Bins: []CalibrationBin{}}
+	for _, result := range results {
+		if result.Error != "" || result.Confidence <= 0 {
+			continue
+		}
+		i := int(result.Confidence * calibrationBins)
+		if i >= calibrationBins {
+			i = calibrationBins - 1
+		}
+		bins[i].count++
+		bins[i].confidence += result.Confidence
+		if result.Correct {
+			bins[i].correct++
+		}
+		calibration.Count++
+	}
+	if calibration.Count == 0 {
+		return calibration
+	}
+
+	ece := 0.0
+	for i, b := range bins {
+		if b.count == 0 {
+			continue
+		}
+		accuracy := float64(b.correct) / float64(b.count)
+		confidence := b.confidence / float64(b.count)
+		ece += float64(b.count) / float64(calibration.Count) * math.Abs(accuracy-confidence)
+		calibration.Bins = append(calibration.Bins, CalibrationBin{
+			Lower:          round(float64(i) / calibrationBins),
+			Upper:          round(float64(i+1) / calibrationBins),
+			Count:          b.count,
+			Accuracy:       round(accuracy),
+			MeanConfidence: round(confidence),
+		})
+	}
+	calibration.ECE = round(ece)
+	return calibration
+}
+
+func summarizeLatency(latencies []int64) LatencySummary {
+	if len(latencies) == 0 {
+		return LatencySummary{}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	var total int64
+	for _, l := range latencies {
+		total += l
+	}
+	return LatencySummary{
+		MeanMS: total / int64(len(latencies)),
+		P50MS:  percentile(latencies, 0.50),
+		P90MS:  percentile(latencies, 0.90),
+		P99MS:  percentile(latencies, 0.99),
+		MaxMS:  latencies[len(latencies)-1],
+	}
+}
+
+// percentile uses the nearest-rank method on sorted values
+func percentile(sorted []int64, p float64) int64 {
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// round keeps four decimals so reports do not churn on float noise
+func round(v float64) float64 {
+	return math.Round(v*1e4) / 1e4
+}
@@ -0,0 +1,91 @@
+package eval
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"fact-check/internal/models"
+	"fact-check/internal/services"
+)
+
+// Options configure a run
+type Options struct {
+	// Concurrency is the number of verifier calls in flight, at least 1
+	Concurrency int
+	// Prompt replaces the verifier's built-in prompt when set
+	Prompt *services.PromptTemplate
+	// Cost prices a call; without it costs are reported as zero
+	Cost func(model string, usage models.TokenUsage) float64
+	// Progress, if set, is called after each example from the worker that
+	// finished it
+	Progress func(done, total int, result Result)
+}
+
+// Run verifies every example and returns the results in dataset order
+func Run(ctx context.Context, verifier services.Verifier, examples []Example, opts Options) []Result {
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]Result, len(examples))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = runOne(ctx, verifier, examples[i], opts)
+				if opts.Progress != nil {
+					mu.Lock()
+					done++
+					opts.Progress(done, len(examples), results[i])
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := range examples {
+		if ctx.Err() != nil {
+			results[i] = Result{ID: examples[i].ID, Label: examples[i].Label, Error: ctx.Err().Error()}
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+func runOne(ctx context.Context, verifier services.Verifier, example Example, opts Options) Result {
+	result := Result{ID: example.ID, Label: example.Label}
+	request := &services.VerificationRequest{
+		Content: example.Claim,
+		Link:    example.Link,
+		Prompt:  opts.Prompt,
+	}
+
+	start := time.Now()
+	verdict, err := verifier.VerifyNews(ctx, request)
+	result.LatencyMS = time.Since(start).Round(time.Millisecond).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Predicted = verdict.Status
+	result.Correct = verdict.Status == example.Label
+	result.Confidence = round(verdict.Confidence)
+	result.Model = verdict.Model
+	result.PromptTokens = verdict.Usage.PromptTokens
+	result.CompletionTokens = verdict.Usage.CompletionTokens
+	if opts.Cost != nil {
+		result.CostUSD = opts.Cost(verdict.Model, verdict.Usage)
+	}
+	return result
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"fact-check/internal/repository"
+	"fact-check/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type AccountHandler struct {
+	accounts *services.AccountService
+	logger   *logrus.Logger
+}
+
+func NewAccountHandler(accounts *services.AccountService, logger *logrus.Logger) *AccountHandler {
+	return &AccountHandler{
+		accounts: accounts,
+		logger:   logger,
+	}
+}
+
+// Export downloads everything stored about the current user as a JSON
+// document, or as a ZIP of one JSON file per section with ?format=zip
+func (h *AccountHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "zip" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or zip"})
+		return
+	}
+
+	export, err := h.accounts.Export(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, "Failed to export account data", err)
+		return
+	}
+
+	filename := "factcheck-export-" + export.ExportedAt.Format("2006-01-02")
+	if format == "json" {
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`.json"`)
+		c.JSON(http.StatusOK, export)
+		return
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`.zip"`)
+	c.Status(http.StatusOK)
+
+	archive := zip.NewWriter(c.Writer)
+	sections := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"news.json", export.News},
+		{"usage.json", export.Usage},
+		{"organizations.json", export.Organizations},
+		{"webhooks.json", export.Webhooks},
+	}
+	for _, section := range sections {
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: section.name, Method: zip.Deflate, Modified: export.ExportedAt})
+		if err == nil {
+			encoder := json.NewEncoder(file)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(section.data)
+		}
+		if err != nil {
+			// Headers are already sent, so the truncated archive is all the client gets
+			h.logger.WithContext(c.Request.Context()).Errorf("Failed to write export archive: %v", err)
+			return
+		}
+	}
+	if err := archive.Close(); err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to write export archive: %v", err)
+	}
+}
+
+// Delete schedules the current user's account for deletion after the grace
+// period
+func (h *AccountHandler) Delete(c *gin.Context) {
+	scheduledFor, err := h.accounts.RequestDeletion(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, "Failed to schedule account deletion", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":                "Account scheduled for deletion",
+		"deletion_scheduled_for": scheduledFor,
+	})
+}
+
+// CancelDeletion withdraws a pending deletion during the grace period
+func (h *AccountHandler) CancelDeletion(c *gin.Context) {
+	cancelled, err := h.accounts.CancelDeletion(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, "Failed to cancel account deletion", err)
+		return
+	}
+	if !cancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "No account deletion is pending"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account deletion cancelled"})
+}
+
+// AuditLog lists audit entries, optionally for one ?subject_id= or ?action=
+func (h *AccountHandler) AuditLog(c *gin.Context) {
+	filter := repository.AuditFilter{Action: c.Query("action"), Limit: 100}
+	if value := c.Query("subject_id"); value != "" {
+		subjectID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid subject_id"})
+			return
+		}
+		filter.SubjectID = &subjectID
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > 1000 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	entries, err := h.accounts.AuditLog(c.Request.Context(), filter)
+	if err != nil {
+		h.respondError(c, "Failed to retrieve audit log", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries, "count": len(entries)})
+}
+
+func (h *AccountHandler) respondError(c *gin.Context, message string, err error) {
+	if errors.Is(err, services.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	h.logger.WithContext(c.Request.Context()).Errorf("%s: %v", message, err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"fact-check/internal/config"
+	"fact-check/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultLogLevelOverride = 15 * time.Minute
+	maxLogLevelOverride     = 24 * time.Hour
+)
+
+type AdminHandler struct {
+	configs   *config.Manager
+	logLevels *logging.LevelController
+	logger    *logrus.Logger
+}
+
+func NewAdminHandler(configs *config.Manager, logLevels *logging.LevelController, logger *logrus.Logger) *AdminHandler {
+	return &AdminHandler{
+		configs:   configs,
+		logLevels: logLevels,
+		logger:    logger,
+	}
+}
+
+type logLevelRequest struct {
+	Level    string `json:"level" binding:"required"`
+	Duration string `json:"duration"`
+}
+
+// GetLogLevel returns the active log level and any temporary override
+func (h *AdminHandler) GetLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, h.logLevels.Status())
+}
+
+// SetLogLevel temporarily changes the log level. It reverts to the
+// configured level after the given duration (default 15m, at most 24h).
+func (h *AdminHandler) SetLogLevel(c *gin.Context) {
+	var req logLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	level, err := logrus.ParseLevel(req.Level)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid log level"})
+		return
+	}
+
+	duration := defaultLogLevelOverride
+	if req.Duration != "" {
+		duration, err = time.ParseDuration(req.Duration)
+		if err != nil || duration <= 0 || duration > maxLogLevelOverride {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Duration must be between 1s and 24h, e.g. \"30m\""})
+			return
+		}
+	}
+
+	status := h.logLevels.Override(level, duration)
+	h.logger.WithContext(c.Request.Context()).Warnf("Log level set to %s for %s", level, duration)
+	c.JSON(http.StatusOK, status)
+}
+
+// ResetLogLevel removes an override and restores the configured level
+func (h *AdminHandler) ResetLogLevel(c *gin.Context) {
+	status := h.logLevels.Reset()
+	h.logger.WithContext(c.Request.Context()).Infof("Log level override removed, using %s", status.Level)
+	c.JSON(http.StatusOK, status)
+}
+
+// ReloadConfig re-reads the configuration and applies the reloadable sections
+func (h *AdminHandler) ReloadConfig(c *gin.Context) {
+	result, err := h.configs.Reload()
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Config reload failed: %v", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Config reload failed", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
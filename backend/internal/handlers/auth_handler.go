@@ -38,9 +38,9 @@ func (h *AuthHandler) Callback(c *gin.Context) {
 	}
 
 	// Handle the OAuth callback
-	response, err := h.authService.HandleCallback(code)
+	response, err := h.authService.HandleCallback(c.Request.Context(), code)
 	if err != nil {
-		h.logger.Errorf("Failed to handle OAuth callback: %v", err)
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to handle OAuth callback: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to authenticate user"})
 		return
 	}
@@ -56,9 +56,9 @@ func (h *AuthHandler) Me(c *gin.Context) {
 		return
 	}
 
-	user, err := h.authService.GetUserByID(userID)
+	user, err := h.authService.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
-		h.logger.Errorf("Failed to get user: %v", err)
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to get user: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user information"})
 		return
 	}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"fact-check/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxBatchUploadBytes caps the size of a batch request body
+const maxBatchUploadBytes = 5 << 20
+
+type BatchHandler struct {
+	batches *services.BatchService
+	logger  *logrus.Logger
+}
+
+func NewBatchHandler(batches *services.BatchService, logger *logrus.Logger) *BatchHandler {
+	return &BatchHandler{
+		batches: batches,
+		logger:  logger,
+	}
+}
+
+// Submit accepts a JSON array, a JSONL or CSV body, or a multipart upload
+// with the file in the "file" field. Valid rows are queued for verification
+// and invalid ones reported per row.
+func (h *BatchHandler) Submit(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBatchUploadBytes)
+
+	body, format, err := batchUpload(c)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	defer body.Close()
+
+	rows, err := h.batches.ParseBatch(format, body)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	result, err := h.batches.Submit(c.Request.Context(), c.GetString("user_id"), rows)
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to submit batch: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit batch"})
+		return
+	}
+
+	if result.Accepted == 0 {
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+// Progress returns aggregate and per-item verification progress of a batch
+func (h *BatchHandler) Progress(c *gin.Context) {
+	batchID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	progress, err := h.batches.Progress(c.Request.Context(), c.GetString("user_id"), batchID)
+	if err != nil {
+		if errors.Is(err, services.ErrBatchNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Batch not found"})
+			return
+		}
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to get batch progress: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve batch"})
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+func (h *BatchHandler) respondError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Upload exceeds the 5 MB limit"})
+	case errors.Is(err, services.ErrInvalidBatch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to read batch upload: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read upload"})
+	}
+}
+
+// batchUpload picks the request body or uploaded file and its format. An
+// explicit ?format= wins over the content type and file extension.
+func batchUpload(c *gin.Context) (io.ReadCloser, string, error) {
+	format := strings.ToLower(c.Query("format"))
+
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		if format == "" {
+			format = formatFromMediaType(mediaType)
+		}
+		return c.Request.Body, format, nil
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		if errors.Is(err, http.ErrMissingFile) {
+			return nil, "", fmt.Errorf(`%w: multipart upload needs a "file" field`, services.ErrInvalidBatch)
+		}
+		return nil, "", err
+	}
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(header.Filename)) {
+		case ".csv":
+			format = services.BatchFormatCSV
+		case ".jsonl", ".ndjson":
+			format = services.BatchFormatJSONL
+		case ".json":
+			format = services.BatchFormatJSON
+		default:
+			partType, _, _ := mime.ParseMediaType(header.Header.Get("Content-Type"))
+			format = formatFromMediaType(partType)
+		}
+	}
+	return file, format, nil
+}
+
+func formatFromMediaType(mediaType string) string {
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return services.BatchFormatCSV
+	case "application/x-ndjson", "application/jsonl", "application/x-jsonlines":
+		return services.BatchFormatJSONL
+	default:
+		return services.BatchFormatJSON
+	}
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"fact-check/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type CalibrationHandler struct {
+	calibrations *services.CalibrationService
+	logger       *logrus.Logger
+}
+
+func NewCalibrationHandler(calibrations *services.CalibrationService, logger *logrus.Logger) *CalibrationHandler {
+	return &CalibrationHandler{
+		calibrations: calibrations,
+		logger:       logger,
+	}
+}
+
+// Get returns the confidence calibration in use
+func (h *CalibrationHandler) Get(c *gin.Context) {
+	calibration := h.calibrations.Current()
+	if calibration == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No calibration has been fitted yet"})
+		return
+	}
+
+	c.JSON(http.StatusOK, calibration)
+}
+
+// Fit refits the calibration now instead of waiting for the periodic job
+func (h *CalibrationHandler) Fit(c *gin.Context) {
+	calibration, err := h.calibrations.Fit(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, services.ErrNotEnoughOutcomes) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to fit calibration: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fit calibration"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, calibration)
+}
@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"fact-check/internal/config"
+	"fact-check/internal/models"
+	"fact-check/internal/repository"
+	"fact-check/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Feed sizes
+const (
+	defaultFeedItems = 50
+	maxFeedItems     = 200
+)
+
+// FeedHandler serves published verdicts as RSS 2.0 and Atom feeds
+type FeedHandler struct {
+	newsService *services.NewsService
+	publisher   config.PublisherConfig
+	logger      *logrus.Logger
+}
+
+func NewFeedHandler(newsService *services.NewsService, publisher config.PublisherConfig, logger *logrus.Logger) *FeedHandler {
+	return &FeedHandler{
+		newsService: newsService,
+		publisher:   publisher,
+		logger:      logger,
+	}
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	SelfLink      atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description,omitempty"`
+	Category    string  `xml:"category,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomEntry struct {
+	Title     string        `xml:"title"`
+	ID        string        `xml:"id"`
+	Updated   string        `xml:"updated"`
+	Published string        `xml:"published"`
+	Link      atomLink      `xml:"link"`
+	Category  *atomCategory `xml:"category"`
+	Summary   string        `xml:"summary,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// RSS serves the latest published verdicts as RSS 2.0
+func (h *FeedHandler) RSS(c *gin.Context) {
+	h.serve(c, "rss")
+}
+
+// Atom serves the latest published verdicts as an Atom feed
+func (h *FeedHandler) Atom(c *gin.Context) {
+	h.serve(c, "atom")
+}
+
+// serve loads the items selected by ?verdict=, ?topic=, ?organization_id=
+// and ?limit= and writes the feed, answering conditional requests with 304
+func (h *FeedHandler) serve(c *gin.Context, format string) {
+	// Only news its submitter published, without verdicts awaiting review
+	filter := repository.PublishedFilter{Topic: c.Query("topic"), Public: true, Limit: defaultFeedItems}
+	if verdict := c.Query("verdict"); verdict != "" {
+		if _, ok := services.VerdictLabel(verdict); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "verdict must be true, false, uncertain or unverifiable"})
+			return
+		}
+		filter.Status = verdict
+	}
+	if value := c.Query("organization_id"); value != "" {
+		orgID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization_id"})
+			return
+		}
+		filter.OrganizationID = &orgID
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxFeedItems {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxFeedItems)})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	items, err := h.newsService.ListPublished(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to list published news: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+		return
+	}
+
+	// The validators cover the query and every item's verdict time, so any
+	// new or re-verified item changes them
+	var lastModified time.Time
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s?%s\n", format, c.Request.URL.RawQuery)
+	for _, news := range items {
+		fmt.Fprintf(hash, "%s %d\n", news.ID, news.UpdatedAt.UnixNano())
+		if news.UpdatedAt.After(lastModified) {
+			lastModified = news.UpdatedAt
+		}
+	}
+	etag := `"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`
+	lastModified = lastModified.UTC().Truncate(time.Second)
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=300")
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+	if notModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	base := h.baseURL(c)
+	self := base + c.Request.URL.RequestURI()
+	title := h.publisher.Name + " verdicts"
+
+	var document interface{}
+	contentType := "application/rss+xml; charset=utf-8"
+	if format == "rss" {
+		feed := rssFeed{Version: "2.0", Atom: "http://www.w3.org/2005/Atom", Channel: rssChannel{
+			Title:       title,
+			Link:        base,
+			Description: "Latest fact-check verdicts published by " + h.publisher.Name,
+			SelfLink:    atomLink{Href: self, Rel: "self", Type: "application/rss+xml"},
+			Items:       []rssItem{},
+		}}
+		if !lastModified.IsZero() {
+			feed.Channel.LastBuildDate = lastModified.Format(time.RFC1123Z)
+		}
+		for _, news := range items {
+			item := rssItem{
+				Title:       itemTitle(news),
+				Link:        base + "/api/v1/news/" + news.ID.String() + "/claim-review",
+				Description: explanation(news),
+				GUID:        rssGUID{Value: "urn:uuid:" + news.ID.String()},
+				PubDate:     news.UpdatedAt.UTC().Format(time.RFC1123Z),
+			}
+			if news.Topic != nil {
+				item.Category = *news.Topic
+			}
+			feed.Channel.Items = append(feed.Channel.Items, item)
+		}
+		document = feed
+	} else {
+		contentType = "application/atom+xml; charset=utf-8"
+		updated := lastModified
+		if updated.IsZero() {
+			updated = time.Unix(0, 0).UTC()
+		}
+		feed := atomFeed{
+			Title:   title,
+			ID:      self,
+			Updated: updated.Format(time.RFC3339),
+			Links:   []atomLink{{Href: self, Rel: "self"}, {Href: base}},
+			Author:  atomAuthor{Name: h.publisher.Name, URI: h.publisher.URL},
+		}
+		for _, news := range items {
+			entry := atomEntry{
+				Title:     itemTitle(news),
+				ID:        "urn:uuid:" + news.ID.String(),
+				Updated:   news.UpdatedAt.UTC().Format(time.RFC3339),
+				Published: news.CreatedAt.UTC().Format(time.RFC3339),
+				Link:      atomLink{Href: base + "/api/v1/news/" + news.ID.String() + "/claim-review"},
+				Summary:   explanation(news),
+			}
+			if news.Topic != nil {
+				entry.Category = &atomCategory{Term: *news.Topic}
+			}
+			feed.Entries = append(feed.Entries, entry)
+		}
+		document = feed
+	}
+
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to encode feed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+		return
+	}
+	c.Data(http.StatusOK, contentType, append([]byte(xml.Header), body...))
+}
+
+// baseURL is the configured publisher URL, or the address the request was
+// made to when none is configured
+func (h *FeedHandler) baseURL(c *gin.Context) string {
+	if h.publisher.URL != "" {
+		return strings.TrimRight(h.publisher.URL, "/")
+	}
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// notModified applies If-None-Match, falling back to If-Modified-Since when
+// no entity tag was sent
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !lastModified.IsZero() {
+		return !lastModified.After(since)
+	}
+	return false
+}
+
+func itemTitle(news *models.News) string {
+	label, _ := services.VerdictLabel(news.Status)
+	claim := strings.Join(strings.Fields(news.Content), " ")
+	if utf8.RuneCountInString(claim) > 100 {
+		claim = string([]rune(claim)[:100]) + "…"
+	}
+	return label + ": " + claim
+}
+
+func explanation(news *models.News) string {
+	if news.Explanation == nil {
+		return ""
+	}
+	return strings.TrimSpace(*news.Explanation)
+}
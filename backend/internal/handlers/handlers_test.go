@@ -1,10 +1,1915 @@
-package handlers
+package handlers_test
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	"fact-check/internal/config"
+	"fact-check/internal/health"
+	"fact-check/internal/logging"
+	"fact-check/internal/models"
+	"fact-check/internal/repository"
+	"fact-check/internal/server"
+	"fact-check/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 )
 
-func TestHandlers(t *testing.T) {
-	// This is a placeholder test to ensure the package compiles
-	t.Log("Handlers package compiles successfully")
+const testSecret = "test-secret-key-for-handlers"
+
+type stubVerifier struct {
+	result *services.VerificationResult
+	deltas []string
+	err    error
+	calls  int
+	last   *services.VerificationRequest
+	// translated counts Translate calls
+	translated int
+	// moderation answers Moderate calls; nil allows everything
+	moderation *services.ModerationResult
+	moderated  int
+	// delay is how long each verification takes
+	delay time.Duration
+}
+
+func (v *stubVerifier) VerifyNews(ctx context.Context, request *services.VerificationRequest) (*services.VerificationResult, error) {
+	v.calls++
+	v.last = request
+	time.Sleep(v.delay)
+	return v.result, v.err
+}
+
+func (v *stubVerifier) VerifyNewsStream(ctx context.Context, request *services.VerificationRequest, onDelta func(string)) (*services.VerificationResult, error) {
+	for _, delta := range v.deltas {
+		onDelta(delta)
+	}
+	return v.VerifyNews(ctx, request)
+}
+
+// Translate tags the text with the target locale instead of translating it
+func (v *stubVerifier) Translate(ctx context.Context, text, from, to string) (*services.TranslationResult, error) {
+	v.translated++
+	if v.err != nil {
+		return nil, v.err
+	}
+	return &services.TranslationResult{
+		Text:  "[" + to + "] " + text,
+		Model: "gpt-3.5-turbo",
+		Usage: models.TokenUsage{PromptTokens: 20, CompletionTokens: 20, TotalTokens: 40},
+	}, nil
+}
+
+func (v *stubVerifier) Moderate(ctx context.Context, text string) (*services.ModerationResult, error) {
+	v.moderated++
+	if v.moderation == nil {
+		return &services.ModerationResult{Action: "allow", Model: "gpt-3.5-turbo"}, nil
+	}
+	return v.moderation, nil
+}
+
+type testAPI struct {
+	router   http.Handler
+	users    *repository.MemoryUserRepository
+	news     *repository.MemoryNewsRepository
+	verifier *stubVerifier
+	health   *health.Registry
+	webhooks *services.WebhookService
+	queue    *services.VerificationQueue
+	accounts *services.AccountService
+}
+
+func newTestAPI(t *testing.T, configure func(cfg *config.Config)) *testAPI {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Defaults()
+	cfg.JWTSecret = testSecret
+	cfg.OpenAIModel = "gpt-3.5-turbo"
+	cfg.LLMPrices = map[string]config.ModelPrice{
+		"gpt-3.5-turbo": {PromptPer1K: 1, CompletionPer1K: 2},
+	}
+	// Tests must not fetch submitted links
+	cfg.Links.Resolve = false
+	if configure != nil {
+		configure(cfg)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	users := repository.NewMemoryUserRepository()
+	orgRepo := repository.NewMemoryOrganizationRepository()
+	news := repository.NewMemoryNewsRepository(orgRepo)
+	usage := repository.NewMemoryUsageRepository(users)
+	verifier := &stubVerifier{result: &services.VerificationResult{
+		Status:      "false",
+		Explanation: "FALSE: no evidence supports this claim",
+		Model:       "gpt-3.5-turbo",
+		Usage:       models.TokenUsage{PromptTokens: 100, CompletionTokens: 50, TotalTokens: 150},
+	}}
+
+	registry := health.NewRegistry()
+	organizations := services.NewOrganizationService(orgRepo, logger)
+	webhookRepo := repository.NewMemoryWebhookRepository(orgRepo)
+	webhooks := services.NewWebhookService(cfg, webhookRepo, organizations, logger)
+	usageService := services.NewUsageService(cfg, usage, users, logger)
+	redaction := services.NewRedactionService(cfg, orgRepo, logger)
+	moderation := services.NewModerationService(cfg, verifier, usageService, redaction, logger)
+	newsService := services.NewNewsService(cfg, news, webhooks, moderation, logger)
+	sources := services.NewSourceService(repository.NewMemorySourceRepository(), logger)
+	translations := repository.NewMemoryTranslationRepository()
+	languages := services.NewLanguageService(cfg, users, translations, newsService, verifier, usageService, redaction, logger)
+	prompts := services.NewPromptService(repository.NewMemoryPromptRepository(), logger)
+	calibrations := services.NewCalibrationService(cfg, repository.NewMemoryCalibrationRepository(news), logger)
+	verifications := services.NewVerificationService(newsService, verifier, usageService, sources, languages, prompts, calibrations, redaction, logger)
+	jobs := repository.NewMemoryVerificationJobRepository(news)
+	queue := services.NewVerificationQueue(cfg, jobs, verifications, logger)
+	accounts := services.NewAccountService(cfg, services.AccountRepositories{
+		Users:         users,
+		News:          news,
+		Usage:         usage,
+		Organizations: orgRepo,
+		Webhooks:      webhookRepo,
+		Eraser:        &repository.MemoryAccountEraser{Users: users, News: news, Usage: usage, Jobs: jobs, Orgs: orgRepo, Webhooks: webhookRepo, Translations: translations},
+		Audit:         repository.NewMemoryAuditRepository(),
+	}, logger)
+
+	router := server.NewRouter(config.NewManager(cfg, nil), server.Services{
+		Auth:          services.NewAuthService(cfg, users, logger),
+		News:          newsService,
+		Usage:         usageService,
+		Verifications: verifications,
+		Streams:       services.NewVerificationStreams(verifications, time.Minute, time.Minute, logger),
+		Batches:       services.NewBatchService(cfg, jobs, queue, newsService, webhooks, logger),
+		Organizations: organizations,
+		Webhooks:      webhooks,
+		Accounts:      accounts,
+		Sources:       sources,
+		Languages:     languages,
+		Prompts:       prompts,
+		Calibration:   calibrations,
+		Health:        registry,
+		LogLevels:     logging.NewLevelController(logger),
+	}, logger)
+
+	return &testAPI{router: router, users: users, news: news, verifier: verifier, health: registry, webhooks: webhooks, queue: queue, accounts: accounts}
+}
+
+// createUser stores a user and returns a signed bearer token for it
+func (a *testAPI) createUser(t *testing.T, role string) (*models.User, string) {
+	t.Helper()
+
+	id := uuid.New()
+	user := &models.User{
+		ID:        id,
+		GoogleID:  "google-" + id.String(),
+		Email:     id.String() + "@example.com",
+		Name:      "Test User",
+		Role:      role,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := a.users.Create(context.Background(), user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   id.String(),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	return user, token
+}
+
+func (a *testAPI) do(t *testing.T, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to marshal body: %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	recorder := httptest.NewRecorder()
+	a.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func decode(t *testing.T, recorder *httptest.ResponseRecorder, target interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(recorder.Body.Bytes(), target); err != nil {
+		t.Fatalf("failed to decode response %q: %v", recorder.Body.String(), err)
+	}
+}
+
+func TestSubmitRequiresAuthentication(t *testing.T) {
+	api := newTestAPI(t, nil)
+
+	recorder := api.do(t, http.MethodPost, "/api/v1/news/submit", "", map[string]string{"content": "claim"})
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", recorder.Code)
+	}
+
+	recorder = api.do(t, http.MethodPost, "/api/v1/news/submit", "not-a-valid-jwt-token", map[string]string{"content": "claim"})
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for invalid token, got %d", recorder.Code)
+	}
+}
+
+func TestSubmitValidatesBody(t *testing.T) {
+	api := newTestAPI(t, nil)
+	_, token := api.createUser(t, models.RoleUser)
+
+	recorder := api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{"link": "https://example.com"})
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", recorder.Code)
+	}
+}
+
+func TestSubmitVerifyAndList(t *testing.T) {
+	api := newTestAPI(t, nil)
+	user, token := api.createUser(t, models.RoleUser)
+
+	recorder := api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{
+		"content": "The moon is made of cheese",
+		"link":    "https://example.com/moon",
+	})
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var submitted models.News
+	decode(t, recorder, &submitted)
+	if submitted.Status != "pending" || submitted.UserID != user.ID {
+		t.Fatalf("unexpected submitted news: %+v", submitted)
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/verify/"+submitted.ID.String(), token, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var verification models.NewsVerification
+	decode(t, recorder, &verification)
+	if verification.Status != "false" || verification.ID != submitted.ID {
+		t.Fatalf("unexpected verification: %+v", verification)
+	}
+
+	stored, err := api.news.GetByID(context.Background(), submitted.ID)
+	if err != nil {
+		t.Fatalf("failed to load stored news: %v", err)
+	}
+	if stored.Status != "false" || stored.Explanation == nil {
+		t.Fatalf("verification not persisted: %+v", stored)
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/user/"+user.ID.String(), token, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", recorder.Code)
+	}
+	var list struct {
+		News  []models.News `json:"news"`
+		Count int           `json:"count"`
+	}
+	decode(t, recorder, &list)
+	if list.Count != 1 || list.News[0].ID != submitted.ID {
+		t.Fatalf("unexpected news list: %+v", list)
+	}
+}
+
+func TestVerifyUnknownNews(t *testing.T) {
+	api := newTestAPI(t, nil)
+	_, token := api.createUser(t, models.RoleUser)
+
+	recorder := api.do(t, http.MethodGet, "/api/v1/news/verify/"+uuid.New().String(), token, nil)
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", recorder.Code)
+	}
+	if api.verifier.calls != 0 {
+		t.Fatal("verifier should not be called for unknown news")
+	}
+}
+
+func TestVerifyOutlastsWriteTimeout(t *testing.T) {
+	api := newTestAPI(t, nil)
+	_, token := api.createUser(t, models.RoleUser)
+	var news models.News
+	decode(t, api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{"content": "Slow claim"}), &news)
+	api.verifier.delay = 200 * time.Millisecond
+
+	server := httptest.NewUnstartedServer(api.router)
+	server.Config.WriteTimeout = 50 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/news/verify/"+news.ID.String(), nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("expected the verification to outlast the write timeout: %v", err)
+	}
+	defer resp.Body.Close()
+	var verification models.NewsVerification
+	if err := json.NewDecoder(resp.Body).Decode(&verification); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected a verdict, got %d: %v", resp.StatusCode, err)
+	}
+}
+
+func TestGetUserNewsDeniesOtherUsers(t *testing.T) {
+	api := newTestAPI(t, nil)
+	owner, _ := api.createUser(t, models.RoleUser)
+	_, otherToken := api.createUser(t, models.RoleUser)
+
+	recorder := api.do(t, http.MethodGet, "/api/v1/news/user/"+owner.ID.String(), otherToken, nil)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", recorder.Code)
+	}
+}
+
+func TestVerifyEnforcesUserBudget(t *testing.T) {
+	api := newTestAPI(t, func(cfg *config.Config) {
+		cfg.Budgets.UserDaily = 0.1
+	})
+	_, token := api.createUser(t, models.RoleUser)
+
+	recorder := api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{"content": "claim"})
+	var submitted models.News
+	decode(t, recorder, &submitted)
+
+	// 100 prompt tokens at $1/1K plus 50 completion tokens at $2/1K costs $0.20
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/verify/"+submitted.ID.String(), token, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected first verification to succeed, got %d", recorder.Code)
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/verify/"+submitted.ID.String(), token, nil)
+	if recorder.Code != http.StatusPaymentRequired {
+		t.Fatalf("expected 402, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var body map[string]interface{}
+	decode(t, recorder, &body)
+	if body["scope"] != "user" || body["period"] != "daily" {
+		t.Fatalf("unexpected budget error: %v", body)
+	}
+	if api.verifier.calls != 1 {
+		t.Fatalf("expected verifier to be called once, got %d", api.verifier.calls)
+	}
+}
+
+func TestUsageReports(t *testing.T) {
+	api := newTestAPI(t, nil)
+	user, token := api.createUser(t, models.RoleUser)
+	_, adminToken := api.createUser(t, models.RoleAdmin)
+
+	recorder := api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{"content": "claim"})
+	var submitted models.News
+	decode(t, recorder, &submitted)
+	api.do(t, http.MethodGet, "/api/v1/news/verify/"+submitted.ID.String(), token, nil)
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/usage/me", token, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", recorder.Code)
+	}
+	var report models.UsageReport
+	decode(t, recorder, &report)
+	if report.Total.Calls != 1 || report.Total.TotalTokens != 150 || report.Total.CostUSD != 0.2 {
+		t.Fatalf("unexpected usage report: %+v", report.Total)
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/admin/usage", token, nil)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-admin, got %d", recorder.Code)
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/admin/usage", adminToken, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200 for admin, got %d", recorder.Code)
+	}
+	decode(t, recorder, &report)
+	if len(report.ByUser) != 1 || report.ByUser[0].Key != user.ID.String() {
+		t.Fatalf("unexpected per-user usage: %+v", report.ByUser)
+	}
+}
+
+func TestMeReturnsCurrentUser(t *testing.T) {
+	api := newTestAPI(t, nil)
+	user, token := api.createUser(t, models.RoleUser)
+
+	recorder := api.do(t, http.MethodGet, "/api/v1/auth/me", token, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", recorder.Code)
+	}
+	var me models.User
+	decode(t, recorder, &me)
+	if me.ID != user.ID || me.Email != user.Email {
+		t.Fatalf("unexpected user: %+v", me)
+	}
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	api := newTestAPI(t, nil)
+	_, token := api.createUser(t, models.RoleUser)
+	_, adminToken := api.createUser(t, models.RoleAdmin)
+
+	api.health.Register(health.Check{Name: "database", Critical: true, Probe: func(ctx context.Context) (map[string]interface{}, error) {
+		return nil, nil
+	}})
+	api.health.Register(health.Check{Name: "llm", Probe: func(ctx context.Context) (map[string]interface{}, error) {
+		return nil, errors.New("quota exceeded")
+	}})
+
+	if recorder := api.do(t, http.MethodGet, "/livez", "", nil); recorder.Code != http.StatusOK {
+		t.Fatalf("expected livez 200, got %d", recorder.Code)
+	}
+	if recorder := api.do(t, http.MethodGet, "/readyz", "", nil); recorder.Code != http.StatusOK {
+		t.Fatalf("expected readyz 200 with only a non-critical failure, got %d", recorder.Code)
+	}
+
+	recorder := api.do(t, http.MethodGet, "/api/v1/services/status", "", nil)
+	if strings.Contains(recorder.Body.String(), "quota exceeded") {
+		t.Fatal("public status must not leak error details")
+	}
+
+	if recorder := api.do(t, http.MethodGet, "/api/v1/admin/status", token, nil); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-admin, got %d", recorder.Code)
+	}
+	recorder = api.do(t, http.MethodGet, "/api/v1/admin/status", adminToken, nil)
+	var report health.Report
+	decode(t, recorder, &report)
+	if report.Status != health.StatusDegraded || report.Checks[1].Error != "quota exceeded" {
+		t.Fatalf("unexpected detailed status: %+v", report)
+	}
+
+	api.health.Register(health.Check{Name: "database", Critical: true, Probe: func(ctx context.Context) (map[string]interface{}, error) {
+		return nil, errors.New("connection refused")
+	}})
+	if recorder := api.do(t, http.MethodGet, "/readyz", "", nil); recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected readyz 503, got %d", recorder.Code)
+	}
+}
+
+func TestQueueHealthProbe(t *testing.T) {
+	api := newTestAPI(t, func(cfg *config.Config) {
+		cfg.Queue.PollInterval = 10 * time.Millisecond
+	})
+	_, token := api.createUser(t, models.RoleUser)
+
+	api.do(t, http.MethodPost, "/api/v1/news/batch", token, []map[string]string{{"content": "claim"}})
+	details, err := api.queue.Probe(context.Background())
+	if err == nil || details["queued"] != 1 || details["workers"] != 0 {
+		t.Fatalf("expected the probe to fail without workers, got %v (%v)", details, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		api.queue.Run(ctx)
+		close(stopped)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		details, err = api.queue.Probe(context.Background())
+		if err == nil && details["queued"] == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the workers to drain the queue, got %v (%v)", details, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	<-stopped
+	if _, err := api.queue.Probe(context.Background()); err == nil {
+		t.Fatal("expected the probe to fail once the workers stopped")
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	api := newTestAPI(t, nil)
+	_, token := api.createUser(t, models.RoleUser)
+
+	recorder := api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{"content": "claim"})
+	var submitted models.News
+	decode(t, recorder, &submitted)
+	api.do(t, http.MethodGet, "/api/v1/news/verify/"+submitted.ID.String(), token, nil)
+
+	recorder = api.do(t, http.MethodGet, "/metrics", "", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", recorder.Code)
+	}
+	body := recorder.Body.String()
+	for _, want := range []string{
+		`factcheck_http_requests_total{method="GET",route="/api/v1/news/verify/:id",status="200"}`,
+		`factcheck_verifications_total{model="gpt-3.5-turbo",prompt_version="builtin",verdict="false"}`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %s", want)
+		}
+	}
+}
+
+func TestRequestIDHeader(t *testing.T) {
+	api := newTestAPI(t, nil)
+
+	recorder := api.do(t, http.MethodGet, "/livez", "", nil)
+	if recorder.Header().Get("X-Request-ID") == "" {
+		t.Fatal("expected a generated X-Request-ID")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/livez", nil)
+	req.Header.Set("X-Request-ID", "client-supplied-id")
+	recorder = httptest.NewRecorder()
+	api.router.ServeHTTP(recorder, req)
+	if got := recorder.Header().Get("X-Request-ID"); got != "client-supplied-id" {
+		t.Fatalf("expected caller's request ID to be echoed, got %q", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/livez", nil)
+	req.Header.Set("X-Request-ID", "bad id\nwith newline")
+	recorder = httptest.NewRecorder()
+	api.router.ServeHTTP(recorder, req)
+	if got := recorder.Header().Get("X-Request-ID"); got == "" || strings.Contains(got, " ") {
+		t.Fatalf("expected invalid request ID to be replaced, got %q", got)
+	}
+}
+
+func TestAdminLogLevelOverride(t *testing.T) {
+	api := newTestAPI(t, nil)
+	_, userToken := api.createUser(t, models.RoleUser)
+	_, adminToken := api.createUser(t, models.RoleAdmin)
+
+	body := map[string]string{"level": "debug", "duration": "5m"}
+	if recorder := api.do(t, http.MethodPut, "/api/v1/admin/log-level", userToken, body); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-admin, got %d", recorder.Code)
+	}
+
+	recorder := api.do(t, http.MethodPut, "/api/v1/admin/log-level", adminToken, body)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var status logging.LevelStatus
+	decode(t, recorder, &status)
+	if status.Level != "debug" || status.ConfiguredAs != "info" || status.OverrideUntil == nil {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	invalid := map[string]string{"level": "debug", "duration": "48h"}
+	if recorder := api.do(t, http.MethodPut, "/api/v1/admin/log-level", adminToken, invalid); recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for too long override, got %d", recorder.Code)
+	}
+
+	recorder = api.do(t, http.MethodDelete, "/api/v1/admin/log-level", adminToken, nil)
+	var reset logging.LevelStatus
+	decode(t, recorder, &reset)
+	if reset.Level != "info" || reset.OverrideUntil != nil {
+		t.Fatalf("expected override to be removed, got %+v", reset)
+	}
+}
+
+type sseEvent struct {
+	id, event, data string
+}
+
+func parseSSE(t *testing.T, body string) []sseEvent {
+	t.Helper()
+
+	var events []sseEvent
+	for _, block := range strings.Split(strings.TrimSpace(body), "\n\n") {
+		var event sseEvent
+		for _, line := range strings.Split(block, "\n") {
+			field, value, _ := strings.Cut(line, ": ")
+			switch field {
+			case "id":
+				event.id = value
+			case "event":
+				event.event = value
+			case "data":
+				event.data = value
+			}
+		}
+		if event.event != "" {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func TestStreamVerifyWithResume(t *testing.T) {
+	api := newTestAPI(t, nil)
+	api.verifier.deltas = []string{"FALSE: ", "no evidence"}
+	_, token := api.createUser(t, models.RoleUser)
+
+	recorder := api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{"content": "claim"})
+	var submitted models.News
+	decode(t, recorder, &submitted)
+	path := "/api/v1/news/verify/" + submitted.ID.String() + "/stream"
+
+	recorder = api.do(t, http.MethodGet, path, token, nil)
+	if recorder.Code != http.StatusOK || !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("expected event stream, got %d %s", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+
+	events := parseSSE(t, recorder.Body.String())
+	var types []string
+	for _, event := range events {
+		types = append(types, event.event)
+	}
+	want := "stage,stage,stage,delta,delta,stage,verdict"
+	if got := strings.Join(types, ","); got != want {
+		t.Fatalf("expected events %s, got %s", want, got)
+	}
+
+	var verdict models.NewsVerification
+	if err := json.Unmarshal([]byte(events[len(events)-1].data), &verdict); err != nil {
+		t.Fatalf("invalid verdict payload: %v", err)
+	}
+	if verdict.Status != "false" || verdict.ID != submitted.ID {
+		t.Fatalf("unexpected verdict %+v", verdict)
+	}
+
+	// Reconnecting resumes after the last received event without re-running
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Last-Event-ID", events[3].id)
+	recorder = httptest.NewRecorder()
+	api.router.ServeHTTP(recorder, req)
+
+	resumed := parseSSE(t, recorder.Body.String())
+	if len(resumed) != 3 || resumed[0].id != events[4].id || resumed[2].event != "verdict" {
+		t.Fatalf("expected the last 3 events after resume, got %+v", resumed)
+	}
+	if api.verifier.calls != 1 {
+		t.Fatalf("expected one verification run, got %d", api.verifier.calls)
+	}
+
+	// Another user neither joins nor resumes that run; they get their own
+	_, otherToken := api.createUser(t, models.RoleUser)
+	req = httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", "Bearer "+otherToken)
+	req.Header.Set("Last-Event-ID", events[3].id)
+	recorder = httptest.NewRecorder()
+	api.router.ServeHTTP(recorder, req)
+
+	other := parseSSE(t, recorder.Body.String())
+	if len(other) != len(events) || other[0].id == events[0].id {
+		t.Fatalf("expected a separate run for another user, got %+v", other)
+	}
+	if api.verifier.calls != 2 {
+		t.Fatalf("expected a second verification run, got %d", api.verifier.calls)
+	}
+}
+
+func TestStreamVerifyReportsErrors(t *testing.T) {
+	api := newTestAPI(t, func(cfg *config.Config) {
+		cfg.Budgets.UserDaily = 0.0001
+	})
+	_, token := api.createUser(t, models.RoleUser)
+
+	recorder := api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{"content": "claim"})
+	var submitted models.News
+	decode(t, recorder, &submitted)
+
+	if recorder := api.do(t, http.MethodGet, "/api/v1/news/verify/"+uuid.NewString()+"/stream", token, nil); recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown news, got %d", recorder.Code)
+	}
+	for _, path := range []string{"/api/v1/news/verify/not-a-uuid", "/api/v1/news/verify/not-a-uuid/stream"} {
+		if recorder := api.do(t, http.MethodGet, path, token, nil); recorder.Code != http.StatusNotFound {
+			t.Fatalf("%s: expected 404 for a malformed ID, got %d", path, recorder.Code)
+		}
+	}
+
+	// The first run uses up the budget, the second is refused
+	api.do(t, http.MethodGet, "/api/v1/news/verify/"+submitted.ID.String(), token, nil)
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/verify/"+submitted.ID.String()+"/stream", token, nil)
+	events := parseSSE(t, recorder.Body.String())
+	last := events[len(events)-1]
+	if last.event != "error" || !strings.Contains(last.data, `"code":"budget_exceeded"`) {
+		t.Fatalf("expected budget error event, got %+v", events)
+	}
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func TestWebhookDeliveryAndRedeliver(t *testing.T) {
+	api := newTestAPI(t, func(cfg *config.Config) {
+		cfg.Webhooks.AllowPrivateTargets = true
+		cfg.Webhooks.RetryBaseDelay = time.Hour
+		cfg.Webhooks.RetryMaxDelay = time.Hour
+	})
+	_, ownerToken := api.createUser(t, models.RoleUser)
+	member, memberToken := api.createUser(t, models.RoleUser)
+
+	received := make(chan receivedWebhook, 10)
+	status := http.StatusOK
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- receivedWebhook{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	defer receiver.Close()
+
+	// An organization endpoint receives events of its members
+	recorder := api.do(t, http.MethodPost, "/api/v1/orgs", ownerToken, map[string]string{"name": "Newsroom"})
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var org models.Organization
+	decode(t, recorder, &org)
+
+	recorder = api.do(t, http.MethodPost, "/api/v1/orgs/"+org.ID.String()+"/members", memberToken, map[string]string{"user_id": member.ID.String()})
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for non-member, got %d", recorder.Code)
+	}
+	recorder = api.do(t, http.MethodPost, "/api/v1/orgs/"+org.ID.String()+"/members", ownerToken, map[string]string{"user_id": member.ID.String()})
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = api.do(t, http.MethodPost, "/api/v1/webhooks", ownerToken, map[string]interface{}{
+		"url":             receiver.URL,
+		"events":          []string{models.EventNewsVerified, models.EventVerdictChanged},
+		"organization_id": org.ID,
+	})
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var endpoint models.WebhookEndpointCreated
+	decode(t, recorder, &endpoint)
+	if !strings.HasPrefix(endpoint.Secret, "whsec_") {
+		t.Fatalf("expected signing secret in response, got %q", endpoint.Secret)
+	}
+
+	// news.submitted is not subscribed; news.verified is
+	recorder = api.do(t, http.MethodPost, "/api/v1/news/submit", memberToken, map[string]string{"content": "claim"})
+	var news models.News
+	decode(t, recorder, &news)
+	api.do(t, http.MethodGet, "/api/v1/news/verify/"+news.ID.String(), memberToken, nil)
+
+	if _, err := api.webhooks.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("dispatch failed: %v", err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("expected exactly one delivery, got %d", len(received))
+	}
+	webhook := <-received
+
+	if got := webhook.header.Get(services.WebhookEventHeader); got != models.EventNewsVerified {
+		t.Fatalf("expected %s event, got %q", models.EventNewsVerified, got)
+	}
+	timestamp, err := strconv.ParseInt(webhook.header.Get(services.WebhookTimestampHeader), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid timestamp header: %v", err)
+	}
+	if got, want := webhook.header.Get(services.WebhookSignatureHeader), services.SignWebhook(endpoint.Secret, timestamp, webhook.body); got != want {
+		t.Fatalf("signature mismatch: got %q, want %q", got, want)
+	}
+	var event models.WebhookEvent
+	if err := json.Unmarshal(webhook.body, &event); err != nil || event.Type != models.EventNewsVerified {
+		t.Fatalf("unexpected event body %s: %v", webhook.body, err)
+	}
+
+	// A changed verdict fires verdict.changed; a failing endpoint is retried later
+	status = http.StatusInternalServerError
+	api.verifier.result.Status = "true"
+	api.do(t, http.MethodGet, "/api/v1/news/verify/"+news.ID.String(), memberToken, nil)
+	if _, err := api.webhooks.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("dispatch failed: %v", err)
+	}
+	if len(received) != 2 {
+		t.Fatalf("expected news.verified and verdict.changed deliveries, got %d", len(received))
+	}
+
+	path := "/api/v1/webhooks/" + endpoint.ID.String() + "/deliveries"
+	recorder = api.do(t, http.MethodGet, path, memberToken, nil)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-owner member, got %d", recorder.Code)
+	}
+	recorder = api.do(t, http.MethodGet, path, ownerToken, nil)
+	var deliveries []models.WebhookDelivery
+	decode(t, recorder, &deliveries)
+	if len(deliveries) != 3 {
+		t.Fatalf("expected 3 deliveries, got %d", len(deliveries))
+	}
+	var failed *models.WebhookDelivery
+	for i := range deliveries {
+		if deliveries[i].Event == models.EventVerdictChanged {
+			failed = &deliveries[i]
+		}
+	}
+	if failed == nil || failed.Status != models.DeliveryPending || failed.Attempts != 1 ||
+		failed.LastStatusCode == nil || *failed.LastStatusCode != http.StatusInternalServerError || failed.NextAttemptAt == nil {
+		t.Fatalf("expected verdict.changed delivery scheduled for retry, got %+v", failed)
+	}
+	<-received
+	<-received
+
+	// Manual redelivery sends the same event right away
+	status = http.StatusNoContent
+	recorder = api.do(t, http.MethodPost, path+"/"+failed.ID.String()+"/redeliver", ownerToken, nil)
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var redelivery models.WebhookDelivery
+	decode(t, recorder, &redelivery)
+	if redelivery.RedeliveryOf == nil || *redelivery.RedeliveryOf != failed.ID || redelivery.EventID != failed.EventID {
+		t.Fatalf("unexpected redelivery: %+v", redelivery)
+	}
+	if _, err := api.webhooks.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("dispatch failed: %v", err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("expected only the redelivery to be sent, got %d", len(received))
+	}
+	webhook = <-received
+	if got := webhook.header.Get(services.WebhookDeliveryHeader); got != redelivery.ID.String() {
+		t.Fatalf("expected delivery %s, got %s", redelivery.ID, got)
+	}
+}
+
+func TestWebhookRejectsPrivateTargets(t *testing.T) {
+	api := newTestAPI(t, nil)
+	_, token := api.createUser(t, models.RoleUser)
+
+	for _, url := range []string{"http://127.0.0.1:8080/hook", "http://localhost/hook", "ftp://example.com/hook", "http://169.254.169.254/latest"} {
+		recorder := api.do(t, http.MethodPost, "/api/v1/webhooks", token, map[string]interface{}{
+			"url":    url,
+			"events": []string{models.EventNewsSubmitted},
+		})
+		if recorder.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", url, recorder.Code)
+		}
+	}
+
+	recorder := api.do(t, http.MethodPost, "/api/v1/webhooks", token, map[string]interface{}{
+		"url":    "https://example.com/hook",
+		"events": []string{"news.deleted"},
+	})
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown event, got %d", recorder.Code)
+	}
+}
+
+func TestBatchSubmissionAndProgress(t *testing.T) {
+	api := newTestAPI(t, nil)
+	_, token := api.createUser(t, models.RoleUser)
+	_, otherToken := api.createUser(t, models.RoleUser)
+
+	recorder := api.do(t, http.MethodPost, "/api/v1/news/batch", token, []map[string]string{
+		{"content": "The moon is made of cheese", "link": "https://example.com/moon"},
+		{"content": "   "},
+		{"content": "Water boils at 50C", "link": "ftp://example.com/water"},
+	})
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var result models.BatchSubmissionResult
+	decode(t, recorder, &result)
+	if result.Accepted != 1 || result.Rejected != 2 || result.BatchID == nil {
+		t.Fatalf("unexpected batch result: %+v", result)
+	}
+	if result.Errors[0].Row != 2 || result.Errors[1].Row != 3 {
+		t.Fatalf("expected rows 2 and 3 rejected, got %+v", result.Errors)
+	}
+
+	recorder = api.do(t, http.MethodPost, "/api/v1/news/batch", token, []map[string]string{{"link": "https://example.com"}})
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 when no row is valid, got %d", recorder.Code)
+	}
+
+	// CSV upload as a multipart file
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, err := writer.CreateFormFile("file", "claims.csv")
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	io.WriteString(part, "content,link,publish\n\"Claim one, with a comma\",https://example.com/1,true\nClaim two,,\nClaim three,,maybe\n")
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/news/batch", &form)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	recorder = httptest.NewRecorder()
+	api.router.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	decode(t, recorder, &result)
+	if result.Accepted != 2 || result.Rejected != 1 || result.Errors[0].Row != 3 {
+		t.Fatalf("unexpected CSV batch result: %+v", result)
+	}
+	if published, _ := api.news.GetByID(context.Background(), result.Items[0].NewsID); published == nil || !published.Published {
+		t.Fatal("expected the first CSV row to be published")
+	}
+	csvBatch := result.BatchID.String()
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/batch/"+csvBatch, token, nil)
+	var progress models.BatchProgress
+	decode(t, recorder, &progress)
+	if progress.Total != 2 || progress.Queued != 2 || progress.Completed {
+		t.Fatalf("expected 2 queued jobs, got %+v", progress)
+	}
+
+	for {
+		processed, err := api.queue.ProcessNext(context.Background())
+		if err != nil {
+			t.Fatalf("failed to process job: %v", err)
+		}
+		if !processed {
+			break
+		}
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/batch/"+csvBatch, token, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", recorder.Code)
+	}
+	progress = models.BatchProgress{}
+	decode(t, recorder, &progress)
+	if !progress.Completed || progress.Succeeded != 2 || progress.Verdicts["false"] != 2 {
+		t.Fatalf("expected completed batch with 2 false verdicts, got %+v", progress)
+	}
+	if api.verifier.calls != 3 {
+		t.Fatalf("expected 3 verifications across both batches, got %d", api.verifier.calls)
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/batch/"+csvBatch, otherToken, nil)
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for another user's batch, got %d", recorder.Code)
+	}
+}
+
+func TestBatchRetriesFailedVerifications(t *testing.T) {
+	api := newTestAPI(t, func(cfg *config.Config) {
+		cfg.Queue.MaxAttempts = 2
+	})
+	_, token := api.createUser(t, models.RoleUser)
+	api.verifier.err = &services.VerifierError{Err: errors.New("provider unavailable")}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/news/batch", strings.NewReader("{\"content\":\"claim\"}\n"))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	api.router.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var result models.BatchSubmissionResult
+	decode(t, recorder, &result)
+
+	for i := 0; i < 3; i++ {
+		api.queue.ProcessNext(context.Background())
+	}
+	if api.verifier.calls != 2 {
+		t.Fatalf("expected 2 attempts, got %d", api.verifier.calls)
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/batch/"+result.BatchID.String(), token, nil)
+	var progress models.BatchProgress
+	decode(t, recorder, &progress)
+	if !progress.Completed || progress.Failed != 1 || progress.Jobs[0].Error == nil {
+		t.Fatalf("expected a failed job with an error, got %+v", progress)
+	}
+}
+
+func TestAccountExportAndDeletion(t *testing.T) {
+	api := newTestAPI(t, nil)
+	user, token := api.createUser(t, models.RoleUser)
+	colleague, colleagueToken := api.createUser(t, models.RoleUser)
+	_, adminToken := api.createUser(t, models.RoleAdmin)
+
+	recorder := api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{"content": "The moon is made of cheese"})
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", recorder.Code)
+	}
+	recorder = api.do(t, http.MethodPut, "/api/v1/me/locale", token, map[string]string{"locale": "pt-BR"})
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", recorder.Code)
+	}
+	var org models.Organization
+	decode(t, api.do(t, http.MethodPost, "/api/v1/orgs", token, map[string]string{"name": "Newsroom"}), &org)
+	recorder = api.do(t, http.MethodPost, "/api/v1/orgs/"+org.ID.String()+"/members", token, map[string]string{"user_id": colleague.ID.String()})
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	policyPath := "/api/v1/orgs/" + org.ID.String() + "/policy"
+	if recorder = api.do(t, http.MethodPut, policyPath, colleagueToken, map[string]bool{"redact_pii": true}); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a member, got %d", recorder.Code)
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/me/export", token, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if !strings.HasPrefix(recorder.Header().Get("Content-Disposition"), "attachment") {
+		t.Fatalf("expected attachment, got %q", recorder.Header().Get("Content-Disposition"))
+	}
+	var export models.AccountExport
+	decode(t, recorder, &export)
+	if export.Profile == nil || export.Profile.ID != user.ID || len(export.News) != 1 {
+		t.Fatalf("unexpected export: %+v", export)
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/me/export?format=zip", token, nil)
+	archive, err := zip.NewReader(bytes.NewReader(recorder.Body.Bytes()), int64(recorder.Body.Len()))
+	if err != nil {
+		t.Fatalf("failed to read export archive: %v", err)
+	}
+	if len(archive.File) != 5 || archive.File[0].Name != "profile.json" {
+		t.Fatalf("unexpected archive contents: %d files", len(archive.File))
+	}
+
+	// Deletion can be cancelled during the grace period
+	recorder = api.do(t, http.MethodDelete, "/api/v1/me", token, nil)
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	recorder = api.do(t, http.MethodPost, "/api/v1/me/deletion/cancel", token, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", recorder.Code)
+	}
+	recorder = api.do(t, http.MethodPost, "/api/v1/me/deletion/cancel", token, nil)
+	if recorder.Code != http.StatusConflict {
+		t.Fatalf("expected 409 with nothing pending, got %d", recorder.Code)
+	}
+
+	recorder = api.do(t, http.MethodDelete, "/api/v1/me", token, nil)
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", recorder.Code)
+	}
+	if erased, err := api.accounts.ProcessDueDeletions(context.Background()); err != nil || erased != 0 {
+		t.Fatalf("expected nothing due yet, got %d, %v", erased, err)
+	}
+
+	// Once the grace period is over the account is erased
+	past := time.Now().Add(-time.Minute)
+	if err := api.users.ScheduleDeletion(context.Background(), user.ID, &past); err != nil {
+		t.Fatalf("failed to backdate deletion: %v", err)
+	}
+	if erased, err := api.accounts.ProcessDueDeletions(context.Background()); err != nil || erased != 1 {
+		t.Fatalf("expected one erased account, got %d, %v", erased, err)
+	}
+
+	stored, err := api.users.GetByID(context.Background(), user.ID)
+	if err != nil || stored.DeletedAt == nil || stored.Email == user.Email || stored.Locale != nil {
+		t.Fatalf("expected anonymized user, got %+v, %v", stored, err)
+	}
+	// The organization passes to the remaining member
+	if recorder = api.do(t, http.MethodPut, policyPath, colleagueToken, map[string]bool{"redact_pii": true}); recorder.Code != http.StatusOK {
+		t.Fatalf("expected the remaining member to own the organization, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if news, _ := api.news.ListByUser(context.Background(), user.ID); len(news) != 0 {
+		t.Fatalf("expected news to be deleted, got %d", len(news))
+	}
+	recorder = api.do(t, http.MethodGet, "/api/v1/me/export", token, nil)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for erased account, got %d", recorder.Code)
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/admin/audit?subject_id="+user.ID.String()+"&action="+models.AuditAccountDeleted, adminToken, nil)
+	var audit struct {
+		Entries []models.AuditEntry `json:"entries"`
+	}
+	decode(t, recorder, &audit)
+	if len(audit.Entries) != 1 || audit.Entries[0].ActorID != nil {
+		t.Fatalf("expected one system deletion entry, got %+v", audit.Entries)
+	}
+}
+
+func TestClaimReview(t *testing.T) {
+	api := newTestAPI(t, func(cfg *config.Config) {
+		cfg.Publisher = config.PublisherConfig{Name: "Example Checks", URL: "https://checks.example.org/"}
+	})
+	_, token := api.createUser(t, models.RoleUser)
+
+	recorder := api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]interface{}{
+		"content": "The moon is made of cheese",
+		"link":    "https://news.example.com/moon",
+		"publish": true,
+	})
+	var news models.News
+	decode(t, recorder, &news)
+	path := "/api/v1/news/" + news.ID.String() + "/claim-review"
+
+	recorder = api.do(t, http.MethodGet, path, "", nil)
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 before verification, got %d", recorder.Code)
+	}
+
+	api.do(t, http.MethodGet, "/api/v1/news/verify/"+news.ID.String(), token, nil)
+
+	// Verdicts that are unpublished or flagged for review stay private
+	var private models.News
+	decode(t, api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{"content": "Cats can fly"}), &private)
+	api.do(t, http.MethodGet, "/api/v1/news/verify/"+private.ID.String(), token, nil)
+	if recorder = api.do(t, http.MethodGet, "/api/v1/news/"+private.ID.String()+"/claim-review", "", nil); recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unpublished news, got %d", recorder.Code)
+	}
+	var flagged models.News
+	decode(t, api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]interface{}{"content": "Vaccines contain chips", "publish": true}), &flagged)
+	if err := api.news.UpdateStatus(context.Background(), flagged.ID, models.Verdict{Status: "false", Explanation: "Models disagreed", NeedsReview: true}); err != nil {
+		t.Fatalf("failed to flag verdict: %v", err)
+	}
+	if recorder = api.do(t, http.MethodGet, "/api/v1/news/"+flagged.ID.String()+"/claim-review", "", nil); recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a verdict awaiting review, got %d", recorder.Code)
+	}
+
+	recorder = api.do(t, http.MethodGet, path, "", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/ld+json" {
+		t.Fatalf("expected JSON-LD, got %q", contentType)
+	}
+	var review models.ClaimReview
+	decode(t, recorder, &review)
+	if review.Type != "ClaimReview" || review.ClaimReviewed != "The moon is made of cheese" {
+		t.Fatalf("unexpected claim review: %+v", review)
+	}
+	if review.ReviewRating.RatingValue != 1 || review.ReviewRating.AlternateName != "False" {
+		t.Fatalf("expected false verdict rated 1, got %+v", review.ReviewRating)
+	}
+	if review.Author.Name != "Example Checks" || review.URL != "https://checks.example.org"+path {
+		t.Fatalf("unexpected publisher: %+v, %q", review.Author, review.URL)
+	}
+	if review.ItemReviewed.Author == nil || review.ItemReviewed.Author.Name != "news.example.com" || review.ItemReviewed.URL != "https://news.example.com/moon" {
+		t.Fatalf("unexpected reviewed item: %+v", review.ItemReviewed)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Accept", "text/html")
+	recorder = httptest.NewRecorder()
+	api.router.ServeHTTP(recorder, req)
+	if !strings.Contains(recorder.Body.String(), `<script type="application/ld+json">{"@context":"https://schema.org"`) {
+		t.Fatalf("expected embedded JSON-LD, got %s", recorder.Body.String())
+	}
+}
+
+func TestVerdictFeeds(t *testing.T) {
+	api := newTestAPI(t, nil)
+	_, token := api.createUser(t, models.RoleUser)
+	_, otherToken := api.createUser(t, models.RoleUser)
+
+	recorder := api.do(t, http.MethodPost, "/api/v1/orgs", token, map[string]string{"name": "Newsroom"})
+	var org models.Organization
+	decode(t, recorder, &org)
+
+	submit := func(token, content, topic string, publish bool) models.News {
+		t.Helper()
+		recorder := api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]interface{}{"content": content, "topic": topic, "publish": publish})
+		var news models.News
+		decode(t, recorder, &news)
+		return news
+	}
+	verified := submit(token, "The moon is made of cheese", " Science ", true)
+	other := submit(otherToken, "Cats can fly", "animals", true)
+	submit(token, "Still pending", "science", true)
+	private := submit(token, "My landlord is a spy", "science", false)
+	flagged := submit(otherToken, "Vaccines contain chips", "science", true)
+	for _, news := range []models.News{verified, private} {
+		api.do(t, http.MethodGet, "/api/v1/news/verify/"+news.ID.String(), token, nil)
+	}
+	api.do(t, http.MethodGet, "/api/v1/news/verify/"+other.ID.String(), otherToken, nil)
+	if err := api.news.UpdateStatus(context.Background(), flagged.ID, models.Verdict{Status: "false", Explanation: "Models disagreed", NeedsReview: true}); err != nil {
+		t.Fatalf("failed to flag verdict: %v", err)
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/feeds/rss", "", nil)
+	if recorder.Code != http.StatusOK || !strings.HasPrefix(recorder.Header().Get("Content-Type"), "application/rss+xml") {
+		t.Fatalf("expected RSS feed, got %d %q", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+	body := recorder.Body.String()
+	if strings.Count(body, "<item>") != 2 || strings.Contains(body, "Still pending") ||
+		strings.Contains(body, "landlord") || strings.Contains(body, "Vaccines") {
+		t.Fatalf("expected only the two published, reviewed verdicts, got %s", body)
+	}
+
+	// Only the owner can publish their news
+	publishPath := "/api/v1/news/" + private.ID.String() + "/publish"
+	if recorder = api.do(t, http.MethodPut, publishPath, otherToken, map[string]bool{"published": true}); recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for another user's news, got %d", recorder.Code)
+	}
+	if recorder = api.do(t, http.MethodPut, publishPath, token, map[string]bool{"published": true}); recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if body = api.do(t, http.MethodGet, "/api/v1/feeds/rss", "", nil).Body.String(); strings.Count(body, "<item>") != 3 {
+		t.Fatalf("expected the published item in the feed, got %s", body)
+	}
+	api.do(t, http.MethodPut, publishPath, token, map[string]bool{"published": false})
+	if !strings.Contains(body, `<guid isPermaLink="false">urn:uuid:`+verified.ID.String()+`</guid>`) {
+		t.Fatalf("expected a stable GUID, got %s", body)
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/feeds/atom?topic=science&organization_id="+org.ID.String(), "", nil)
+	body = recorder.Body.String()
+	if strings.Count(body, "<entry>") != 1 || !strings.Contains(body, "<id>urn:uuid:"+verified.ID.String()+"</id>") {
+		t.Fatalf("expected only the organization's science verdict, got %s", body)
+	}
+	recorder = api.do(t, http.MethodGet, "/api/v1/feeds/atom?verdict=true", "", nil)
+	if strings.Contains(recorder.Body.String(), "<entry>") {
+		t.Fatalf("expected no true verdicts, got %s", recorder.Body.String())
+	}
+	recorder = api.do(t, http.MethodGet, "/api/v1/feeds/rss?verdict=maybe", "", nil)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown verdict, got %d", recorder.Code)
+	}
+
+	// Conditional requests
+	recorder = api.do(t, http.MethodGet, "/api/v1/feeds/rss", "", nil)
+	etag := recorder.Header().Get("ETag")
+	lastModified := recorder.Header().Get("Last-Modified")
+	for header, value := range map[string]string{"If-None-Match": etag, "If-Modified-Since": lastModified} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/feeds/rss", nil)
+		req.Header.Set(header, value)
+		recorder = httptest.NewRecorder()
+		api.router.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusNotModified {
+			t.Fatalf("expected 304 for %s, got %d", header, recorder.Code)
+		}
+	}
+
+	// A new verdict changes the validators
+	time.Sleep(10 * time.Millisecond)
+	api.do(t, http.MethodGet, "/api/v1/news/verify/"+verified.ID.String(), token, nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/feeds/rss", nil)
+	req.Header.Set("If-None-Match", etag)
+	recorder = httptest.NewRecorder()
+	api.router.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200 after a new verdict, got %d", recorder.Code)
+	}
+}
+
+func TestSourceRegistry(t *testing.T) {
+	api := newTestAPI(t, nil)
+	_, token := api.createUser(t, models.RoleUser)
+	_, adminToken := api.createUser(t, models.RoleAdmin)
+
+	csvBody := "domain,name,credibility,ownership,bias_notes,satire\n" +
+		"https://www.TheOnion.com/,The Onion,low,G/O Media,,true\n" +
+		"apnews.com,Associated Press,high,Cooperative,,false\n" +
+		"not a domain,Broken,high,,,\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/sources/import", strings.NewReader(csvBody))
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	recorder := httptest.NewRecorder()
+	api.router.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var imported models.SourceImportResult
+	decode(t, recorder, &imported)
+	if imported.Imported != 2 || imported.Rejected != 1 || imported.Errors[0].Row != 3 {
+		t.Fatalf("unexpected import result: %+v", imported)
+	}
+
+	recorder = api.do(t, http.MethodPut, "/api/v1/admin/sources/apnews.com", token, map[string]interface{}{"name": "AP", "credibility": "high"})
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-admin, got %d", recorder.Code)
+	}
+	recorder = api.do(t, http.MethodPut, "/api/v1/admin/sources/apnews.com", adminToken, map[string]interface{}{"name": "AP", "credibility": "excellent"})
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown credibility, got %d", recorder.Code)
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/sources/WWW.theonion.com", token, nil)
+	var source models.Source
+	decode(t, recorder, &source)
+	if source.Domain != "theonion.com" || !source.Satire || source.Credibility != models.CredibilityLow {
+		t.Fatalf("unexpected source: %+v", source)
+	}
+
+	// Links on a subdomain pick up the registered domain's profile
+	recorder = api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{
+		"content": "Area man wins argument",
+		"link":    "https://local.theonion.com/area-man",
+	})
+	var news models.News
+	decode(t, recorder, &news)
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/verify/"+news.ID.String(), token, nil)
+	var verification models.NewsVerification
+	decode(t, recorder, &verification)
+	if verification.Source == nil || verification.Source.Domain != "theonion.com" {
+		t.Fatalf("expected source profile in the response, got %+v", verification)
+	}
+	if api.verifier.last.Source == nil || !api.verifier.last.Source.Satire {
+		t.Fatalf("expected source profile in the verification request, got %+v", api.verifier.last)
+	}
+
+	// The profile is that of the host that served the page, not of the
+	// canonical link it declares
+	spoofed := "https://apnews.com/article/area-man"
+	served := "local.theonion.com"
+	hoax := &models.News{ID: uuid.New(), UserID: news.UserID, Content: "Area man wins argument", CanonicalLink: &spoofed,
+		FetchedHost: &served, Status: "pending", Moderation: models.ModerationAllowed, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := api.news.Create(context.Background(), hoax); err != nil {
+		t.Fatalf("failed to create news: %v", err)
+	}
+	decode(t, api.do(t, http.MethodGet, "/api/v1/news/verify/"+hoax.ID.String(), token, nil), &verification)
+	if verification.Source == nil || verification.Source.Domain != "theonion.com" {
+		t.Fatalf("expected the serving site's profile, got %+v", verification.Source)
+	}
+
+	recorder = api.do(t, http.MethodDelete, "/api/v1/admin/sources/theonion.com", adminToken, nil)
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", recorder.Code)
+	}
+	recorder = api.do(t, http.MethodGet, "/api/v1/sources/theonion.com", token, nil)
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 after delete, got %d", recorder.Code)
+	}
+}
+
+func TestSubmitCanonicalizesLinks(t *testing.T) {
+	mux := http.NewServeMux()
+	site := httptest.NewServer(mux)
+	defer site.Close()
+	mux.HandleFunc("/s/abc", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/politics/story/amp?utm_source=twitter", http.StatusFound)
+	})
+	mux.HandleFunc("/politics/story/amp", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		io.WriteString(w, `<head><link rel="canonical" href="/politics/story"></head>`)
+	})
+
+	api := newTestAPI(t, func(cfg *config.Config) {
+		cfg.Links.Resolve = true
+		cfg.Links.AllowPrivateTargets = true
+	})
+	_, token := api.createUser(t, models.RoleUser)
+
+	recorder := api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{
+		"content": "Shortened claim",
+		"link":    site.URL + "/s/abc",
+	})
+	var news models.News
+	decode(t, recorder, &news)
+	if *news.Link != site.URL+"/s/abc" || news.CanonicalLink == nil || *news.CanonicalLink != site.URL+"/politics/story" {
+		t.Fatalf("expected raw and canonical link, got %v and %v", *news.Link, news.CanonicalLink)
+	}
+	if news.FetchedHost == nil || *news.FetchedHost != "127.0.0.1" {
+		t.Fatalf("expected the fetched host, got %v", news.FetchedHost)
+	}
+
+	// Unreachable links still get tracking parameters removed
+	recorder = api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{
+		"content": "Unreachable claim",
+		"link":    "http://127.0.0.1:1/story?utm_medium=email&id=4#top",
+	})
+	var unreachable models.News
+	decode(t, recorder, &unreachable)
+	if unreachable.CanonicalLink == nil || *unreachable.CanonicalLink != "http://127.0.0.1:1/story?id=4" || unreachable.FetchedHost != nil {
+		t.Fatalf("expected cleaned link without a fetched host, got %v, %v", unreachable.CanonicalLink, unreachable.FetchedHost)
+	}
+}
+
+func TestMultilingualVerification(t *testing.T) {
+	api := newTestAPI(t, func(cfg *config.Config) {
+		cfg.Language.Mode = config.LanguageModeTranslate
+	})
+	_, token := api.createUser(t, models.RoleUser)
+	api.verifier.result.Explanation = "FALSO: não há nenhuma evidência de que o preço da gasolina vai cair"
+
+	content := "El gobierno anunció que los precios de la gasolina bajarán en enero"
+	recorder := api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{"content": content})
+	var news models.News
+	decode(t, recorder, &news)
+	if news.Language == nil || *news.Language != "es" {
+		t.Fatalf("expected detected language es, got %v", news.Language)
+	}
+
+	recorder = api.do(t, http.MethodPut, "/api/v1/me/locale", token, map[string]string{"locale": "english"})
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid locale, got %d", recorder.Code)
+	}
+	recorder = api.do(t, http.MethodPut, "/api/v1/me/locale", token, map[string]string{"locale": "PT_br"})
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"pt-BR"`) {
+		t.Fatalf("expected normalized locale, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/verify/"+news.ID.String(), token, nil)
+	var verification models.NewsVerification
+	decode(t, recorder, &verification)
+	if verification.Language != "es" || verification.Locale != "pt-BR" {
+		t.Fatalf("expected language es and locale pt-BR, got %q and %q", verification.Language, verification.Locale)
+	}
+	if last := api.verifier.last; last.Translation != "[en] "+content || last.Locale != "pt-BR" {
+		t.Fatalf("expected English translation and pt-BR locale in request, got %+v", last)
+	}
+
+	// The English translation is stored and reused
+	api.do(t, http.MethodGet, "/api/v1/news/verify/"+news.ID.String(), token, nil)
+	if api.verifier.translated != 1 {
+		t.Fatalf("expected 1 translation call, got %d", api.verifier.translated)
+	}
+
+	// The explanation is already in Portuguese, only the content is translated
+	var translation models.NewsTranslation
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/"+news.ID.String()+"/translation?locale=pt-BR", token, nil)
+	decode(t, recorder, &translation)
+	if translation.Content != "[pt-BR] "+content || translation.Explanation == nil || *translation.Explanation != api.verifier.result.Explanation {
+		t.Fatalf("unexpected pt-BR translation: %+v", translation)
+	}
+
+	// The stored English content is reused, the newer explanation translated
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/"+news.ID.String()+"/translation?locale=en", token, nil)
+	decode(t, recorder, &translation)
+	if translation.Content != "[en] "+content || translation.Explanation == nil || *translation.Explanation != "[en] "+api.verifier.result.Explanation {
+		t.Fatalf("unexpected en translation: %+v", translation)
+	}
+	calls := api.verifier.translated
+	api.do(t, http.MethodGet, "/api/v1/news/"+news.ID.String()+"/translation?locale=en", token, nil)
+	if api.verifier.translated != calls {
+		t.Fatalf("expected stored translation to be reused, got %d calls", api.verifier.translated-calls)
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/"+news.ID.String()+"/translation?locale=e", token, nil)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid locale, got %d", recorder.Code)
+	}
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/"+uuid.NewString()+"/translation?locale=en", token, nil)
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown news, got %d", recorder.Code)
+	}
+}
+
+func TestPromptVersions(t *testing.T) {
+	api := newTestAPI(t, nil)
+	_, adminToken := api.createUser(t, models.RoleAdmin)
+	_, userToken := api.createUser(t, models.RoleUser)
+
+	publish := map[string]interface{}{
+		"description": "Ask for sources",
+		"system":      "You are a careful fact-checker.",
+		"template":    "Check this claim: {{.Content}}{{with .Source}} from {{.Domain}}{{end}}\nCite your sources.",
+	}
+	recorder := api.do(t, http.MethodPost, "/api/v1/admin/prompts", userToken, publish)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-admin, got %d", recorder.Code)
+	}
+	recorder = api.do(t, http.MethodPost, "/api/v1/admin/prompts", adminToken, map[string]interface{}{
+		"system":   "You are a fact-checker.",
+		"template": "Check {{.Claim}}",
+	})
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for template with unknown field, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	// New versions start out of rotation
+	recorder = api.do(t, http.MethodPost, "/api/v1/admin/prompts", adminToken, publish)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var prompt models.PromptVersion
+	decode(t, recorder, &prompt)
+	if prompt.Version != 1 || prompt.Weight != 0 || prompt.CreatedBy == nil {
+		t.Fatalf("unexpected prompt version %+v", prompt)
+	}
+
+	recorder = api.do(t, http.MethodPost, "/api/v1/news/submit", userToken, map[string]string{"content": "claim"})
+	var news models.News
+	decode(t, recorder, &news)
+	var verification models.NewsVerification
+	decode(t, api.do(t, http.MethodGet, "/api/v1/news/verify/"+news.ID.String(), userToken, nil), &verification)
+	if verification.PromptVersion != nil || api.verifier.last.Prompt.Version != nil {
+		t.Fatalf("expected the built-in prompt, got version %v", verification.PromptVersion)
+	}
+
+	recorder = api.do(t, http.MethodPut, "/api/v1/admin/prompts/1/weight", adminToken, map[string]int{"weight": 100})
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	decode(t, api.do(t, http.MethodGet, "/api/v1/news/verify/"+news.ID.String(), userToken, nil), &verification)
+	if verification.PromptVersion == nil || *verification.PromptVersion != 1 {
+		t.Fatalf("expected prompt version 1, got %v", verification.PromptVersion)
+	}
+	last := api.verifier.last.Prompt
+	if rendered, err := last.Render(api.verifier.last); err != nil || last.System != "You are a careful fact-checker." || rendered != "Check this claim: claim\nCite your sources." {
+		t.Fatalf("unexpected prompt %q (%v)", rendered, err)
+	}
+	stored, err := api.news.GetByID(context.Background(), news.ID)
+	if err != nil || stored.PromptVersion == nil || *stored.PromptVersion != 1 {
+		t.Fatalf("expected prompt version stored on the news item, got %+v (%v)", stored, err)
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/admin/prompts", adminToken, nil)
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"count":1`) {
+		t.Fatalf("unexpected prompt list %d: %s", recorder.Code, recorder.Body.String())
+	}
+	recorder = api.do(t, http.MethodGet, "/api/v1/admin/prompts/7", adminToken, nil)
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown version, got %d", recorder.Code)
+	}
+	recorder = api.do(t, http.MethodPut, "/api/v1/admin/prompts/1/weight", adminToken, map[string]int{"weight": -1})
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for negative weight, got %d", recorder.Code)
+	}
+}
+
+func TestEnsembleDisagreementReview(t *testing.T) {
+	api := newTestAPI(t, func(cfg *config.Config) {
+		cfg.LLMPrices["gpt-4o"] = config.ModelPrice{PromptPer1K: 10, CompletionPer1K: 10}
+	})
+	_, adminToken := api.createUser(t, models.RoleAdmin)
+	_, userToken := api.createUser(t, models.RoleUser)
+
+	usage := models.TokenUsage{PromptTokens: 100, CompletionTokens: 50, TotalTokens: 150}
+	api.verifier.result = &services.VerificationResult{
+		Status:      "false",
+		Explanation: "FALSE: no evidence supports this claim",
+		Model:       "gpt-3.5-turbo+gpt-4o",
+		Votes: []models.ModelVerdict{
+			{Model: "gpt-3.5-turbo", Status: "false", Confidence: 0.7, Explanation: "FALSE: no evidence"},
+			{Model: "gpt-4o", Status: "true", Confidence: 0.6, Explanation: "TRUE: confirmed"},
+		},
+		Members: []*services.VerificationResult{
+			{Status: "false", Model: "gpt-3.5-turbo", Usage: usage},
+			{Status: "true", Model: "gpt-4o", Usage: usage},
+		},
+		Disagreement: true,
+		NeedsReview:  true,
+	}
+
+	recorder := api.do(t, http.MethodPost, "/api/v1/news/submit", userToken, map[string]string{"content": "claim"})
+	var news models.News
+	decode(t, recorder, &news)
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/verify/"+news.ID.String(), userToken, nil)
+	var verification models.NewsVerification
+	decode(t, recorder, &verification)
+	if !verification.NeedsReview || len(verification.ModelVerdicts) != 2 || verification.ModelVerdicts[1].Status != "true" {
+		t.Fatalf("expected flagged verdict with both model verdicts, got %+v", verification)
+	}
+
+	// Each member's tokens are billed at its own model's price
+	var report models.UsageReport
+	decode(t, api.do(t, http.MethodGet, "/api/v1/usage/me", userToken, nil), &report)
+	if len(report.ByModel) != 2 || report.Total.Calls != 2 {
+		t.Fatalf("expected usage per ensemble member, got %+v", report)
+	}
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/admin/reviews", userToken, nil)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-admin, got %d", recorder.Code)
+	}
+	recorder = api.do(t, http.MethodGet, "/api/v1/admin/reviews", adminToken, nil)
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"count":1`) || !strings.Contains(recorder.Body.String(), news.ID.String()) {
+		t.Fatalf("expected the flagged news in the review queue, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = api.do(t, http.MethodPost, "/api/v1/admin/reviews/"+news.ID.String(), adminToken, map[string]string{"status": "maybe"})
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid status, got %d", recorder.Code)
+	}
+	recorder = api.do(t, http.MethodPost, "/api/v1/admin/reviews/"+news.ID.String(), adminToken, map[string]string{"status": "true", "explanation": "Confirmed by the city council minutes."})
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	stored, err := api.news.GetByID(context.Background(), news.ID)
+	if err != nil || stored.Status != "true" || stored.NeedsReview || stored.ReviewedBy == nil || len(stored.ModelVerdicts) != 2 {
+		t.Fatalf("expected resolved verdict keeping model verdicts, got %+v (%v)", stored, err)
+	}
+
+	recorder = api.do(t, http.MethodPost, "/api/v1/admin/reviews/"+news.ID.String(), adminToken, map[string]string{"status": "false"})
+	if recorder.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for an already resolved review, got %d", recorder.Code)
+	}
+	recorder = api.do(t, http.MethodGet, "/api/v1/admin/reviews", adminToken, nil)
+	if !strings.Contains(recorder.Body.String(), `"count":0`) {
+		t.Fatalf("expected an empty review queue, got %s", recorder.Body.String())
+	}
+}
+
+func TestConfidenceCalibrationAbstains(t *testing.T) {
+	api := newTestAPI(t, func(cfg *config.Config) {
+		cfg.Abstention.Threshold = 0.6
+		cfg.Abstention.Topics = map[string]float64{"health": 0.9}
+		cfg.Calibration.MinSamples = 4
+	})
+	_, adminToken := api.createUser(t, models.RoleAdmin)
+	_, userToken := api.createUser(t, models.RoleUser)
+	api.verifier.result.Confidence = 0.7
+
+	verify := func(topic string) models.NewsVerification {
+		t.Helper()
+		var news models.News
+		decode(t, api.do(t, http.MethodPost, "/api/v1/news/submit", userToken, map[string]string{"content": "claim", "topic": topic}), &news)
+		var verification models.NewsVerification
+		decode(t, api.do(t, http.MethodGet, "/api/v1/news/verify/"+news.ID.String(), userToken, nil), &verification)
+		return verification
+	}
+
+	// Until a calibration is fitted the stated confidence is used
+	verification := verify("politics")
+	if verification.Status != "false" || verification.Confidence != 0.7 || verification.NeedsReview {
+		t.Fatalf("expected a confident false verdict, got %+v", verification)
+	}
+	if recorder := api.do(t, http.MethodGet, "/api/v1/admin/calibration", adminToken, nil); recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 before any calibration, got %d", recorder.Code)
+	}
+	if recorder := api.do(t, http.MethodPost, "/api/v1/admin/calibration/fit", adminToken, nil); recorder.Code != http.StatusConflict {
+		t.Fatalf("expected 409 without reviewed verdicts, got %d", recorder.Code)
+	}
+
+	// The health threshold is stricter, so the same confidence abstains;
+	// reviewers agree with the model half of the time
+	for i, status := range []string{"false", "true", "false", "true"} {
+		verification := verify("Health")
+		if verification.Status != "unverifiable" || verification.ModelStatus != "false" || !verification.NeedsReview {
+			t.Fatalf("expected an unverifiable verdict, got %+v", verification)
+		}
+		recorder := api.do(t, http.MethodPost, "/api/v1/admin/reviews/"+verification.ID.String(), adminToken, map[string]string{"status": status})
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("review %d: expected 200, got %d: %s", i, recorder.Code, recorder.Body.String())
+		}
+	}
+
+	recorder := api.do(t, http.MethodPost, "/api/v1/admin/calibration/fit", adminToken, nil)
+	var calibration models.Calibration
+	decode(t, recorder, &calibration)
+	if recorder.Code != http.StatusCreated || calibration.Samples != 4 || calibration.Method != "platt" {
+		t.Fatalf("expected a calibration fitted on 4 reviews, got %d: %+v", recorder.Code, calibration)
+	}
+	if recorder := api.do(t, http.MethodGet, "/api/v1/admin/calibration", adminToken, nil); recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), calibration.ID.String()) {
+		t.Fatalf("expected the fitted calibration, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	// A stated 70% is now known to be a coin flip, below the default threshold
+	verification = verify("politics")
+	if verification.Status != "unverifiable" || verification.RawConfidence != 0.7 || verification.Confidence >= 0.6 {
+		t.Fatalf("expected a calibrated unverifiable verdict, got %+v", verification)
+	}
+}
+
+func TestPIIRedaction(t *testing.T) {
+	api := newTestAPI(t, nil)
+	_, userToken := api.createUser(t, models.RoleUser)
+	_, ownerToken := api.createUser(t, models.RoleUser)
+
+	content := "Jane Roe (jane.roe@example.com, 555-123-4567) says the council vote was rigged"
+	api.verifier.result.Explanation = "FALSE: the vote count was certified; [EMAIL_1] is not a council address"
+
+	verify := func(token string) models.NewsVerification {
+		t.Helper()
+		var news models.News
+		decode(t, api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{"content": content}), &news)
+		var verification models.NewsVerification
+		decode(t, api.do(t, http.MethodGet, "/api/v1/news/verify/"+news.ID.String(), token, nil), &verification)
+		return verification
+	}
+
+	// Redaction is on by default and the answer gets the values back
+	verification := verify(userToken)
+	if api.verifier.last.Content != "Jane Roe ([EMAIL_1], [PHONE_1]) says the council vote was rigged" || !api.verifier.last.Redacted {
+		t.Fatalf("expected redacted content, got %q", api.verifier.last.Content)
+	}
+	if !strings.Contains(verification.Explanation, "jane.roe@example.com is not a council address") {
+		t.Fatalf("expected the email restored in the explanation, got %q", verification.Explanation)
+	}
+
+	// Personal data and credentials in the link are redacted as well
+	var linked models.News
+	decode(t, api.do(t, http.MethodPost, "/api/v1/news/submit", userToken, map[string]string{
+		"content": "The council vote was rigged",
+		"link":    "https://news.example.com/share?token=abc123&email=jane.roe%40example.com",
+	}), &linked)
+	api.do(t, http.MethodGet, "/api/v1/news/verify/"+linked.ID.String(), userToken, nil)
+	if want := "https://news.example.com/share?email=[EMAIL_1]&token=[REDACTED]"; api.verifier.last.Link != want {
+		t.Fatalf("expected redacted link %q, got %q", want, api.verifier.last.Link)
+	}
+
+	var org models.Organization
+	decode(t, api.do(t, http.MethodPost, "/api/v1/orgs", ownerToken, map[string]string{"name": "Newsroom"}), &org)
+	recorder := api.do(t, http.MethodPut, "/api/v1/orgs/"+org.ID.String()+"/policy", userToken, map[string]bool{"redact_pii": false})
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a non-member, got %d", recorder.Code)
+	}
+	recorder = api.do(t, http.MethodPut, "/api/v1/orgs/"+org.ID.String()+"/policy", ownerToken, map[string]bool{"redact_pii": false})
+	decode(t, recorder, &org)
+	if recorder.Code != http.StatusOK || org.Policy.RedactPII == nil || *org.Policy.RedactPII {
+		t.Fatalf("expected redaction turned off, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	// The organization opted out, so its members' content is sent as is
+	verify(ownerToken)
+	if api.verifier.last.Content != content || api.verifier.last.Redacted {
+		t.Fatalf("expected unredacted content, got %q", api.verifier.last.Content)
+	}
+}
+
+func TestContentModeration(t *testing.T) {
+	api := newTestAPI(t, func(cfg *config.Config) {
+		cfg.Moderation.RejectKeywords = []string{"kill yourself"}
+		cfg.Moderation.Rules = []config.ModerationRule{
+			{Category: "spam", Action: "quarantine", Pattern: `(?i)cheap (pills|watches)`},
+		}
+		cfg.Moderation.LLM = true
+	})
+	_, userToken := api.createUser(t, models.RoleUser)
+	_, adminToken := api.createUser(t, models.RoleAdmin)
+
+	submit := func(content string) *httptest.ResponseRecorder {
+		return api.do(t, http.MethodPost, "/api/v1/news/submit", userToken, map[string]string{"content": content})
+	}
+
+	// Rejected by a rule before the LLM is asked or anything is stored
+	recorder := submit("The senator should kill yourself, says a post")
+	if recorder.Code != http.StatusUnprocessableEntity || !strings.Contains(recorder.Body.String(), `"category":"blocked"`) {
+		t.Fatalf("expected a rejection, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if api.verifier.moderated != 0 {
+		t.Fatal("expected the LLM to be skipped after a rule rejected the submission")
+	}
+
+	// Allowed by the rules and the LLM
+	var allowed models.News
+	recorder = submit("The city council approved the new budget")
+	decode(t, recorder, &allowed)
+	if recorder.Code != http.StatusCreated || allowed.Moderation != models.ModerationAllowed || api.verifier.moderated != 1 {
+		t.Fatalf("expected the submission allowed, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	// The LLM can quarantine what the rules let through
+	api.verifier.moderation = &services.ModerationResult{Action: "quarantine", Category: "harassment", Reason: "targets a private person"}
+	var held models.News
+	recorder = submit("My neighbour is a thief")
+	decode(t, recorder, &held)
+	if recorder.Code != http.StatusAccepted || held.Moderation != models.ModerationQuarantined ||
+		held.ModerationCategory == nil || *held.ModerationCategory != "harassment" {
+		t.Fatalf("expected the submission quarantined, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	api.verifier.moderation = nil
+
+	recorder = api.do(t, http.MethodGet, "/api/v1/news/verify/"+held.ID.String(), userToken, nil)
+	if recorder.Code != http.StatusConflict || api.verifier.calls != 0 {
+		t.Fatalf("expected quarantined news not to be verified, got %d", recorder.Code)
+	}
+
+	// Batch rows go through the same rules on upload; the LLM classifies the
+	// rows they allow in the queue, before verifying them
+	var batch models.BatchSubmissionResult
+	decode(t, api.do(t, http.MethodPost, "/api/v1/news/batch", userToken, []map[string]string{
+		{"content": "Cheap watches cure the flu"},
+		{"content": "Kill yourself now"},
+		{"content": "Inflation fell last month"},
+		{"content": "My colleague steals from the till"},
+	}), &batch)
+	if batch.Accepted != 3 || batch.Quarantined != 1 || batch.Rejected != 1 || batch.Items[0].JobID != nil || batch.Items[1].JobID == nil ||
+		api.verifier.moderated != 2 {
+		t.Fatalf("unexpected batch result after %d LLM calls: %+v", api.verifier.moderated, batch)
+	}
+	pending, err := api.news.GetByID(context.Background(), batch.Items[1].NewsID)
+	if err != nil || pending.Moderation != models.ModerationPending {
+		t.Fatalf("expected the row pending until the LLM classifies it, got %+v", pending)
+	}
+
+	// Jobs of a batch run in no particular order; the LLM allows the first
+	// row it sees and quarantines the second
+	if processed, err := api.queue.ProcessNext(context.Background()); !processed || err != nil {
+		t.Fatalf("expected a job processed, got %v, %v", processed, err)
+	}
+	api.verifier.moderation = &services.ModerationResult{Action: "quarantine", Category: "harassment", Reason: "targets a private person"}
+	if processed, err := api.queue.ProcessNext(context.Background()); !processed || err != nil {
+		t.Fatalf("expected a job processed, got %v, %v", processed, err)
+	}
+	api.verifier.moderation = nil
+	screened, _ := api.news.GetByID(context.Background(), batch.Items[1].NewsID)
+	flagged, _ := api.news.GetByID(context.Background(), batch.Items[2].NewsID)
+	if screened.Moderation == models.ModerationQuarantined {
+		screened, flagged = flagged, screened
+	}
+	if screened.Moderation != models.ModerationAllowed || screened.Status == "pending" ||
+		flagged.Moderation != models.ModerationQuarantined || flagged.Status != "pending" || api.verifier.moderated != 4 || api.verifier.calls != 1 {
+		t.Fatalf("expected one row verified and one quarantined, got %+v and %+v", screened, flagged)
+	}
+
+	// Moderators see the queue oldest first and decide
+	recorder = api.do(t, http.MethodGet, "/api/v1/admin/moderation", userToken, nil)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-admins, got %d", recorder.Code)
+	}
+	var queue struct {
+		News  []models.News `json:"news"`
+		Count int           `json:"count"`
+	}
+	decode(t, api.do(t, http.MethodGet, "/api/v1/admin/moderation", adminToken, nil), &queue)
+	// Rows of a batch share a creation time and come in no particular order
+	if queue.Count != 3 || queue.News[0].ID != held.ID ||
+		(queue.News[1].ID != batch.Items[0].NewsID && queue.News[2].ID != batch.Items[0].NewsID) {
+		t.Fatalf("unexpected moderation queue: %+v", queue)
+	}
+
+	var resolved models.News
+	recorder = api.do(t, http.MethodPost, "/api/v1/admin/moderation/"+held.ID.String(), adminToken, map[string]string{"action": "allow"})
+	decode(t, recorder, &resolved)
+	if recorder.Code != http.StatusOK || resolved.Moderation != models.ModerationAllowed || resolved.ModeratedBy == nil {
+		t.Fatalf("expected the submission allowed, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	recorder = api.do(t, http.MethodPost, "/api/v1/admin/moderation/"+held.ID.String(), adminToken, map[string]string{"action": "reject"})
+	if recorder.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for news no longer quarantined, got %d", recorder.Code)
+	}
+	recorder = api.do(t, http.MethodPost, "/api/v1/admin/moderation/"+batch.Items[0].NewsID.String(), adminToken,
+		map[string]string{"action": "reject", "reason": "advertising"})
+	decode(t, recorder, &resolved)
+	if recorder.Code != http.StatusOK || resolved.Moderation != models.ModerationRejected || *resolved.ModerationReason != "advertising" {
+		t.Fatalf("expected the submission rejected, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	// Allowed news can be verified; rejected news cannot
+	if recorder := api.do(t, http.MethodGet, "/api/v1/news/verify/"+held.ID.String(), userToken, nil); recorder.Code != http.StatusOK {
+		t.Fatalf("expected allowed news verified, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if recorder := api.do(t, http.MethodGet, "/api/v1/news/verify/"+resolved.ID.String(), userToken, nil); recorder.Code != http.StatusConflict {
+		t.Fatalf("expected rejected news not verified, got %d", recorder.Code)
+	}
+	decode(t, api.do(t, http.MethodGet, "/api/v1/admin/moderation", adminToken, nil), &queue)
+	if queue.Count != 1 || queue.News[0].ID != flagged.ID {
+		t.Fatalf("expected only the flagged batch row left, got %+v", queue)
+	}
+}
+
+func TestModerationEnforcesUserBudget(t *testing.T) {
+	api := newTestAPI(t, func(cfg *config.Config) {
+		cfg.Budgets.UserDaily = 0.1
+		cfg.Moderation.LLM = true
+	})
+	_, token := api.createUser(t, models.RoleUser)
+
+	var submitted models.News
+	decode(t, api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{"content": "claim"}), &submitted)
+	if submitted.Moderation != models.ModerationAllowed || api.verifier.moderated != 1 {
+		t.Fatalf("expected the submission classified and allowed, got %+v", submitted)
+	}
+	// The verification costs $0.20 and uses up the daily budget
+	if recorder := api.do(t, http.MethodGet, "/api/v1/news/verify/"+submitted.ID.String(), token, nil); recorder.Code != http.StatusOK {
+		t.Fatalf("expected the verification to succeed, got %d", recorder.Code)
+	}
+
+	// Over budget the LLM is not asked and, although on_error is allow, the
+	// submission is held for a moderator
+	recorder := api.do(t, http.MethodPost, "/api/v1/news/submit", token, map[string]string{"content": "another claim"})
+	var held models.News
+	decode(t, recorder, &held)
+	if recorder.Code != http.StatusAccepted || api.verifier.moderated != 1 ||
+		held.ModerationCategory == nil || *held.ModerationCategory != "budget_exceeded" {
+		t.Fatalf("expected the submission held without an LLM call, got %d after %d calls: %+v", recorder.Code, api.verifier.moderated, held)
+	}
+
+	// Batch rows are held the same way when the queue classifies them
+	var batch models.BatchSubmissionResult
+	decode(t, api.do(t, http.MethodPost, "/api/v1/news/batch", token, []map[string]string{
+		{"content": "First claim"},
+		{"content": ""},
+		{"content": "Third claim"},
+	}), &batch)
+	if batch.Accepted != 2 || batch.Rejected != 1 || batch.Items[1].Row != 3 {
+		t.Fatalf("unexpected batch result: %+v", batch)
+	}
+	for processed := true; processed; {
+		processed, _ = api.queue.ProcessNext(context.Background())
+	}
+	for _, item := range batch.Items {
+		news, err := api.news.GetByID(context.Background(), item.NewsID)
+		if err != nil || news.Moderation != models.ModerationQuarantined || *news.ModerationCategory != "budget_exceeded" {
+			t.Fatalf("expected the row held for a moderator, got %+v", news)
+		}
+	}
+	if api.verifier.moderated != 1 || api.verifier.calls != 1 {
+		t.Fatalf("expected no LLM calls over budget, got %d moderations and %d verifications", api.verifier.moderated, api.verifier.calls)
+	}
 }
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"fact-check/internal/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	registry *health.Registry
+}
+
+func NewHealthHandler(registry *health.Registry) *HealthHandler {
+	return &HealthHandler{registry: registry}
+}
+
+// Livez reports that the process is running; it never checks dependencies
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "healthy"})
+}
+
+// Readyz reports whether all critical dependencies are reachable
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.registry.RunCritical(c.Request.Context())
+
+	checks := gin.H{}
+	for _, result := range report.Checks {
+		checks[result.Name] = result.Status
+	}
+
+	code := http.StatusOK
+	if !report.Ready() {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{"status": report.Status, "checks": checks})
+}
+
+// Status returns a public summary of every dependency without error details
+func (h *HealthHandler) Status(c *gin.Context) {
+	report := h.registry.Run(c.Request.Context())
+
+	services := gin.H{}
+	for _, result := range report.Checks {
+		services[result.Name] = gin.H{
+			"status":    result.Status,
+			"available": result.Status == health.StatusUp,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": report.Status, "services": services})
+}
+
+// DetailedStatus returns the full health report including errors and details
+func (h *HealthHandler) DetailedStatus(c *gin.Context) {
+	report := h.registry.Run(c.Request.Context())
+
+	code := http.StatusOK
+	if !report.Ready() {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, report)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"fact-check/internal/models"
+	"fact-check/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type LanguageHandler struct {
+	languages *services.LanguageService
+	logger    *logrus.Logger
+}
+
+func NewLanguageHandler(languages *services.LanguageService, logger *logrus.Logger) *LanguageHandler {
+	return &LanguageHandler{
+		languages: languages,
+		logger:    logger,
+	}
+}
+
+// SetLocale sets the locale the user's explanations are written in
+func (h *LanguageHandler) SetLocale(c *gin.Context) {
+	var req models.LocaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	locale, err := h.languages.SetLocale(c.Request.Context(), c.GetString("user_id"), req.Locale)
+	if err != nil {
+		h.respondError(c, "Failed to set locale", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"locale": locale})
+}
+
+// Translation returns a news item's content and explanation in the locale
+// given by the locale query parameter
+func (h *LanguageHandler) Translation(c *gin.Context) {
+	newsID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if c.Query("locale") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "locale is required"})
+		return
+	}
+
+	translation, err := h.languages.Translation(c.Request.Context(), c.GetString("user_id"), newsID.String(), c.Query("locale"))
+	if err != nil {
+		h.respondError(c, "Failed to translate news", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
+func (h *LanguageHandler) respondError(c *gin.Context, message string, err error) {
+	var budgetErr *services.BudgetExceededError
+	var verifierErr *services.VerifierError
+	switch {
+	case errors.Is(err, services.ErrInvalidLocale):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "locale must look like en or pt-BR"})
+	case errors.Is(err, services.ErrNewsNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "News not found"})
+	case errors.Is(err, services.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.As(err, &budgetErr):
+		c.JSON(http.StatusPaymentRequired, gin.H{
+			"error":     budgetErr.Error(),
+			"scope":     budgetErr.Scope,
+			"period":    budgetErr.Period,
+			"limit_usd": budgetErr.LimitUSD,
+			"spent_usd": budgetErr.SpentUSD,
+		})
+	case errors.As(err, &verifierErr):
+		h.logger.WithContext(c.Request.Context()).Errorf("%s: %v", message, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "The translation service is currently unavailable. Please try again later."})
+	default:
+		h.logger.WithContext(c.Request.Context()).Errorf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
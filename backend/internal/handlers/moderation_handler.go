@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"fact-check/internal/models"
+	"fact-check/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultModerationLimit = 100
+
+type ModerationHandler struct {
+	news   *services.NewsService
+	logger *logrus.Logger
+}
+
+func NewModerationHandler(news *services.NewsService, logger *logrus.Logger) *ModerationHandler {
+	return &ModerationHandler{
+		news:   news,
+		logger: logger,
+	}
+}
+
+// List returns quarantined submissions, oldest first
+func (h *ModerationHandler) List(c *gin.Context) {
+	limit := defaultModerationLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 1000 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+			return
+		}
+		limit = parsed
+	}
+
+	news, err := h.news.ListQuarantined(c.Request.Context(), limit)
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to list quarantined news: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list quarantined news"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"news": news, "count": len(news)})
+}
+
+// Resolve allows or rejects a quarantined submission
+func (h *ModerationHandler) Resolve(c *gin.Context) {
+	var req models.ModerationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	news, err := h.news.ResolveModeration(c.Request.Context(), c.GetString("user_id"), c.Param("id"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNewsNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "News not found"})
+		case errors.Is(err, services.ErrNotQuarantined):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			h.logger.WithContext(c.Request.Context()).Errorf("Failed to resolve moderation: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve moderation"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, news)
+}
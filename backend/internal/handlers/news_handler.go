@@ -1,7 +1,13 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
 	"net/http"
+	"time"
 
 	"fact-check/internal/models"
 	"fact-check/internal/services"
@@ -10,16 +16,23 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// ldJSONContentType is the media type of JSON-LD documents
+const ldJSONContentType = "application/ld+json"
+
 type NewsHandler struct {
 	newsService   *services.NewsService
-	openAIService *services.OpenAIService
+	verifications *services.VerificationService
+	streams       *services.VerificationStreams
+	keepalive     time.Duration
 	logger        *logrus.Logger
 }
 
-func NewNewsHandler(newsService *services.NewsService, openAIService *services.OpenAIService, logger *logrus.Logger) *NewsHandler {
+func NewNewsHandler(newsService *services.NewsService, verifications *services.VerificationService, streams *services.VerificationStreams, keepalive time.Duration, logger *logrus.Logger) *NewsHandler {
 	return &NewsHandler{
 		newsService:   newsService,
-		openAIService: openAIService,
+		verifications: verifications,
+		streams:       streams,
+		keepalive:     keepalive,
 		logger:        logger,
 	}
 }
@@ -40,13 +53,27 @@ func (h *NewsHandler) Submit(c *gin.Context) {
 	}
 
 	// Submit news
-	news, err := h.newsService.SubmitNews(userID.(string), &submission)
+	news, err := h.newsService.SubmitNews(c.Request.Context(), userID.(string), &submission)
 	if err != nil {
-		h.logger.Errorf("Failed to submit news: %v", err)
+		var rejectedErr *services.SubmissionRejectedError
+		if errors.As(err, &rejectedErr) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":    "Submission rejected by moderation",
+				"category": rejectedErr.Category,
+				"reason":   rejectedErr.Reason,
+			})
+			return
+		}
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to submit news: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit news"})
 		return
 	}
 
+	// Quarantined news is stored but waits for a moderator
+	if news.Moderation == models.ModerationQuarantined {
+		c.JSON(http.StatusAccepted, news)
+		return
+	}
 	c.JSON(http.StatusCreated, news)
 }
 
@@ -58,54 +85,172 @@ func (h *NewsHandler) Verify(c *gin.Context) {
 		return
 	}
 
-	// Get news from database
-	news, err := h.newsService.GetNewsByID(newsID)
+	// As for StreamVerify, the server write timeout is shorter than a
+	// verification, which is bounded by the LLM verify timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	verification, err := h.verifications.Verify(c.Request.Context(), c.GetString("user_id"), newsID, nil)
 	if err != nil {
-		h.logger.Errorf("Failed to get news: %v", err)
-		c.JSON(http.StatusNotFound, gin.H{"error": "News not found"})
+		var budgetErr *services.BudgetExceededError
+		var verifierErr *services.VerifierError
+		switch {
+		case errors.Is(err, services.ErrNewsNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "News not found"})
+		case errors.Is(err, services.ErrHeldByModeration):
+			c.JSON(http.StatusConflict, gin.H{"error": "News is held by moderation"})
+		case errors.As(err, &budgetErr):
+			c.JSON(http.StatusPaymentRequired, gin.H{
+				"error":     budgetErr.Error(),
+				"scope":     budgetErr.Scope,
+				"period":    budgetErr.Period,
+				"limit_usd": budgetErr.LimitUSD,
+				"spent_usd": budgetErr.SpentUSD,
+			})
+		case errors.As(err, &verifierErr):
+			h.logger.WithContext(c.Request.Context()).Errorf("Failed to verify news with OpenAI: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   verifierErr.Error(),
+				"details": "The fact-checking service is currently unavailable. Please try again later or contact support if the issue persists.",
+			})
+		default:
+			h.logger.WithContext(c.Request.Context()).Errorf("Failed to verify news: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify news"})
+		}
 		return
 	}
 
-	// Verify news using OpenAI
-	var link, photoURL string
-	if news.Link != nil {
-		link = *news.Link
+	c.JSON(http.StatusOK, verification)
+}
+
+// StreamVerify verifies news and streams progress as Server-Sent Events:
+// stage, delta (model output as it is generated), then verdict or error.
+// Clients reconnecting with Last-Event-ID resume after that event.
+func (h *NewsHandler) StreamVerify(c *gin.Context) {
+	newsID := c.Param("id")
+
+	// Unknown news is reported as a plain 404 before switching to SSE
+	if _, err := h.newsService.GetNewsByID(c.Request.Context(), newsID); err != nil {
+		if errors.Is(err, services.ErrNewsNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "News not found"})
+			return
+		}
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to get news: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get news"})
+		return
+	}
+
+	// The server write timeout is shorter than a verification; the run
+	// itself is bounded by the LLM verify timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	events := h.streams.Subscribe(c.Request.Context(), c.GetString("user_id"), newsID, c.GetHeader("Last-Event-ID"))
+	keepalive := time.NewTicker(h.keepalive)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeSSE(c.Writer, event); err != nil {
+				h.logger.WithContext(c.Request.Context()).Warnf("Failed to write verification event: %v", err)
+				return
+			}
+		case <-keepalive.C:
+			if _, err := io.WriteString(c.Writer, ": keepalive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
+
+func writeSSE(w gin.ResponseWriter, event services.StreamEvent) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+		return err
 	}
-	if news.PhotoURL != nil {
-		photoURL = *news.PhotoURL
+	w.Flush()
+	return nil
+}
+
+// ClaimReview publishes the verdict for a news item as schema.org
+// ClaimReview JSON-LD, or as an HTML page embedding it when the client
+// prefers text/html
+func (h *NewsHandler) ClaimReview(c *gin.Context) {
+	newsID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
 	}
-	status, explanation, err := h.openAIService.VerifyNews(news.Content, link, photoURL)
+
+	review, err := h.newsService.ClaimReview(c.Request.Context(), newsID.String())
 	if err != nil {
-		h.logger.Errorf("Failed to verify news with OpenAI: %v", err)
-		
-		// Provide more specific error messages based on the error type
-		errorMessage := "Failed to verify news"
-		if err.Error() != "" {
-			errorMessage = err.Error()
+		switch {
+		case errors.Is(err, services.ErrNewsNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "News not found"})
+		case errors.Is(err, services.ErrNewsNotVerified):
+			c.JSON(http.StatusNotFound, gin.H{"error": "No published verdict for this news"})
+		default:
+			h.logger.WithContext(c.Request.Context()).Errorf("Failed to build claim review: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build claim review"})
 		}
-		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": errorMessage,
-			"details": "The fact-checking service is currently unavailable. Please try again later or contact support if the issue persists.",
-		})
 		return
 	}
 
-	// Update news status in database
-	err = h.newsService.UpdateNewsStatus(newsID, status, explanation)
+	// json.Marshal escapes <, > and &, so the document is safe inside a script tag
+	document, err := json.Marshal(review)
 	if err != nil {
-		h.logger.Errorf("Failed to update news status: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update news status"})
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to encode claim review: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build claim review"})
 		return
 	}
 
-	verification := models.NewsVerification{
-		ID:          news.ID,
-		Status:      status,
-		Explanation: explanation,
+	c.Header("Vary", "Accept")
+	switch c.NegotiateFormat(ldJSONContentType, gin.MIMEJSON, gin.MIMEHTML) {
+	case gin.MIMEHTML:
+		page := fmt.Sprintf("<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>Fact check: %s</title>\n<script type=\"application/ld+json\">%s</script>\n</head><body><h1>%s</h1><p>%s</p><p>%s</p></body></html>\n",
+			html.EscapeString(review.ReviewRating.AlternateName), document,
+			html.EscapeString(review.ClaimReviewed), html.EscapeString(review.ReviewRating.AlternateName), html.EscapeString(review.ReviewBody))
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+	case gin.MIMEJSON:
+		c.Data(http.StatusOK, "application/json; charset=utf-8", document)
+	default:
+		c.Data(http.StatusOK, ldJSONContentType, document)
 	}
+}
 
-	c.JSON(http.StatusOK, verification)
+// Publish opts the requester's news in to or out of the public feeds
+func (h *NewsHandler) Publish(c *gin.Context) {
+	var req models.PublishRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	news, err := h.newsService.SetPublished(c.Request.Context(), c.GetString("user_id"), c.Param("id"), *req.Published)
+	if err != nil {
+		if errors.Is(err, services.ErrNewsNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "News not found"})
+			return
+		}
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to publish news: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish news"})
+		return
+	}
+	c.JSON(http.StatusOK, news)
 }
 
 // GetUserNews retrieves all news submissions for a user
@@ -124,9 +269,9 @@ func (h *NewsHandler) GetUserNews(c *gin.Context) {
 	}
 
 	// Get user's news
-	newsList, err := h.newsService.GetUserNews(userID)
+	newsList, err := h.newsService.GetUserNews(c.Request.Context(), userID)
 	if err != nil {
-		h.logger.Errorf("Failed to get user news: %v", err)
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to get user news: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve news"})
 		return
 	}
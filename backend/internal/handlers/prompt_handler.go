@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"fact-check/internal/models"
+	"fact-check/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type PromptHandler struct {
+	prompts *services.PromptService
+	logger  *logrus.Logger
+}
+
+func NewPromptHandler(prompts *services.PromptService, logger *logrus.Logger) *PromptHandler {
+	return &PromptHandler{
+		prompts: prompts,
+		logger:  logger,
+	}
+}
+
+// List returns all published prompt versions, newest first
+func (h *PromptHandler) List(c *gin.Context) {
+	prompts, err := h.prompts.List(c.Request.Context())
+	if err != nil {
+		h.respondError(c, "Failed to list prompt versions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"prompts": prompts, "count": len(prompts)})
+}
+
+// Get returns one prompt version
+func (h *PromptHandler) Get(c *gin.Context) {
+	version, ok := parseVersionParam(c)
+	if !ok {
+		return
+	}
+
+	prompt, err := h.prompts.Get(c.Request.Context(), version)
+	if err != nil {
+		h.respondError(c, "Failed to get prompt version", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prompt)
+}
+
+// Publish stores a new prompt version
+func (h *PromptHandler) Publish(c *gin.Context) {
+	var req models.PromptVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	prompt, err := h.prompts.Publish(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		h.respondError(c, "Failed to publish prompt version", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, prompt)
+}
+
+// SetWeight changes the A/B weight of a prompt version
+func (h *PromptHandler) SetWeight(c *gin.Context) {
+	version, ok := parseVersionParam(c)
+	if !ok {
+		return
+	}
+	var req models.PromptWeightRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	prompt, err := h.prompts.SetWeight(c.Request.Context(), version, *req.Weight)
+	if err != nil {
+		h.respondError(c, "Failed to set prompt weight", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prompt)
+}
+
+func (h *PromptHandler) respondError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidPrompt):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrPromptNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Prompt version not found"})
+	default:
+		h.logger.WithContext(c.Request.Context()).Errorf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+func parseVersionParam(c *gin.Context) (int, bool) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid prompt version"})
+		return 0, false
+	}
+	return version, true
+}
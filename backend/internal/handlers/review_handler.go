@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"fact-check/internal/models"
+	"fact-check/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultReviewLimit = 100
+
+type ReviewHandler struct {
+	news   *services.NewsService
+	logger *logrus.Logger
+}
+
+func NewReviewHandler(news *services.NewsService, logger *logrus.Logger) *ReviewHandler {
+	return &ReviewHandler{
+		news:   news,
+		logger: logger,
+	}
+}
+
+// List returns verdicts flagged for human review, most recent first
+func (h *ReviewHandler) List(c *gin.Context) {
+	limit := defaultReviewLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 1000 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+			return
+		}
+		limit = parsed
+	}
+
+	news, err := h.news.ListReviews(c.Request.Context(), limit)
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to list reviews: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list reviews"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"news": news, "count": len(news)})
+}
+
+// Resolve replaces a flagged verdict with the reviewer's one
+func (h *ReviewHandler) Resolve(c *gin.Context) {
+	var req models.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	news, err := h.news.ResolveReview(c.Request.Context(), c.GetString("user_id"), c.Param("id"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNewsNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "News not found"})
+		case errors.Is(err, services.ErrNotFlagged):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			h.logger.WithContext(c.Request.Context()).Errorf("Failed to resolve review: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve review"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, news)
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"fact-check/internal/models"
+	"fact-check/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type SourceHandler struct {
+	sources *services.SourceService
+	logger  *logrus.Logger
+}
+
+func NewSourceHandler(sources *services.SourceService, logger *logrus.Logger) *SourceHandler {
+	return &SourceHandler{
+		sources: sources,
+		logger:  logger,
+	}
+}
+
+// List returns the whole source registry
+func (h *SourceHandler) List(c *gin.Context) {
+	sources, err := h.sources.List(c.Request.Context())
+	if err != nil {
+		h.respondError(c, "Failed to list sources", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sources": sources, "count": len(sources)})
+}
+
+// Get returns the registry entry for a domain
+func (h *SourceHandler) Get(c *gin.Context) {
+	source, err := h.sources.Get(c.Request.Context(), c.Param("domain"))
+	if err != nil {
+		h.respondError(c, "Failed to get source", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, source)
+}
+
+// Put creates or replaces the registry entry for a domain
+func (h *SourceHandler) Put(c *gin.Context) {
+	var req models.SourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	source, err := h.sources.Put(c.Request.Context(), c.Param("domain"), &req)
+	if err != nil {
+		h.respondError(c, "Failed to save source", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, source)
+}
+
+// Delete removes a domain from the registry
+func (h *SourceHandler) Delete(c *gin.Context) {
+	if err := h.sources.Delete(c.Request.Context(), c.Param("domain")); err != nil {
+		h.respondError(c, "Failed to delete source", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Import upserts registry entries from a CSV body or a multipart upload in
+// the "file" field
+func (h *SourceHandler) Import(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBatchUploadBytes)
+
+	body, _, err := batchUpload(c)
+	if err != nil {
+		h.respondError(c, "Failed to read upload", err)
+		return
+	}
+	defer body.Close()
+
+	result, err := h.sources.Import(c.Request.Context(), body)
+	if err != nil {
+		h.respondError(c, "Failed to import sources", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *SourceHandler) respondError(c *gin.Context, message string, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Upload exceeds the 5 MB limit"})
+	case errors.Is(err, services.ErrInvalidSource), errors.Is(err, services.ErrInvalidBatch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Source not found"})
+	default:
+		h.logger.WithContext(c.Request.Context()).Errorf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"fact-check/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type UsageHandler struct {
+	usageService *services.UsageService
+	logger       *logrus.Logger
+}
+
+func NewUsageHandler(usageService *services.UsageService, logger *logrus.Logger) *UsageHandler {
+	return &UsageHandler{
+		usageService: usageService,
+		logger:       logger,
+	}
+}
+
+// MyUsage returns the LLM usage and budgets of the current user
+func (h *UsageHandler) MyUsage(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	from, to, ok := parseReportRange(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.usageService.UserReport(c.Request.Context(), userID, from, to)
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to get usage report: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve usage"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Report returns LLM usage across all users, or a single user via ?user_id=
+func (h *UsageHandler) Report(c *gin.Context) {
+	from, to, ok := parseReportRange(c)
+	if !ok {
+		return
+	}
+
+	if userID := c.Query("user_id"); userID != "" {
+		report, err := h.usageService.UserReport(c.Request.Context(), userID, from, to)
+		if err != nil {
+			h.logger.WithContext(c.Request.Context()).Errorf("Failed to get usage report for user %s: %v", userID, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to retrieve usage for user"})
+			return
+		}
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	report, err := h.usageService.GlobalReport(c.Request.Context(), from, to)
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to get global usage report: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve usage"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// parseReportRange reads ?from= and ?to= (RFC3339 or YYYY-MM-DD), defaulting
+// to the current calendar month
+func parseReportRange(c *gin.Context) (time.Time, time.Time, bool) {
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := now.Add(time.Second)
+
+	for param, target := range map[string]*time.Time{"from": &from, "to": &to} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			parsed, err = time.Parse("2006-01-02", value)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + " date, use RFC3339 or YYYY-MM-DD"})
+			return time.Time{}, time.Time{}, false
+		}
+		*target = parsed
+	}
+
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return time.Time{}, time.Time{}, false
+	}
+
+	return from, to, true
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"fact-check/internal/models"
+	"fact-check/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultDeliveryLimit is how many deliveries are listed without ?limit=
+const defaultDeliveryLimit = 50
+
+type WebhookHandler struct {
+	orgs     *services.OrganizationService
+	webhooks *services.WebhookService
+	logger   *logrus.Logger
+}
+
+func NewWebhookHandler(orgs *services.OrganizationService, webhooks *services.WebhookService, logger *logrus.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		orgs:     orgs,
+		webhooks: webhooks,
+		logger:   logger,
+	}
+}
+
+// CreateOrganization creates an organization owned by the current user
+func (h *WebhookHandler) CreateOrganization(c *gin.Context) {
+	var req models.OrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	org, err := h.orgs.Create(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to create organization: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create organization"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, org)
+}
+
+// ListOrganizations returns the organizations the current user belongs to
+func (h *WebhookHandler) ListOrganizations(c *gin.Context) {
+	orgs, err := h.orgs.ListForUser(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Failed to list organizations: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve organizations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, orgs)
+}
+
+// AddMember adds a user to an organization; owners only
+func (h *WebhookHandler) AddMember(c *gin.Context) {
+	orgID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.OrganizationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	member, err := h.orgs.AddMember(c.Request.Context(), c.GetString("user_id"), orgID, &req)
+	if err != nil {
+		h.respondError(c, "Failed to add organization member", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// SetPolicy replaces the organization's policy; unset settings follow the
+// server configuration
+func (h *WebhookHandler) SetPolicy(c *gin.Context) {
+	orgID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var policy models.OrganizationPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	org, err := h.orgs.SetPolicy(c.Request.Context(), c.GetString("user_id"), orgID, &policy)
+	if err != nil {
+		h.respondError(c, "Failed to set organization policy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// CreateEndpoint registers a webhook endpoint. The signing secret is only
+// included in this response.
+func (h *WebhookHandler) CreateEndpoint(c *gin.Context) {
+	var req models.WebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	endpoint, err := h.webhooks.RegisterEndpoint(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		h.respondError(c, "Failed to register webhook", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, endpoint)
+}
+
+// ListEndpoints returns the user's endpoints, or an organization's with
+// ?organization_id=
+func (h *WebhookHandler) ListEndpoints(c *gin.Context) {
+	var orgID *uuid.UUID
+	if value := c.Query("organization_id"); value != "" {
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization_id"})
+			return
+		}
+		orgID = &parsed
+	}
+
+	endpoints, err := h.webhooks.ListEndpoints(c.Request.Context(), c.GetString("user_id"), orgID)
+	if err != nil {
+		h.respondError(c, "Failed to list webhooks", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoints)
+}
+
+func (h *WebhookHandler) DeleteEndpoint(c *gin.Context) {
+	endpointID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.webhooks.DeleteEndpoint(c.Request.Context(), c.GetString("user_id"), endpointID); err != nil {
+		h.respondError(c, "Failed to delete webhook", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries returns the endpoint's delivery log, newest first
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	endpointID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	limit := defaultDeliveryLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 500 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := h.webhooks.ListDeliveries(c.Request.Context(), c.GetString("user_id"), endpointID, limit)
+	if err != nil {
+		h.respondError(c, "Failed to list webhook deliveries", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// Redeliver queues a new attempt of a past delivery
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	endpointID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseUUIDParam(c, "delivery_id")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhooks.Redeliver(c.Request.Context(), c.GetString("user_id"), endpointID, deliveryID)
+	if err != nil {
+		h.respondError(c, "Failed to redeliver webhook", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// respondError maps organization and webhook errors to status codes
+func (h *WebhookHandler) respondError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidWebhook), errors.Is(err, services.ErrInvalidOrganizationRole):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrWebhookNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+	case errors.Is(err, services.ErrDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+	case errors.Is(err, services.ErrOrganizationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organization not found"})
+	case errors.Is(err, services.ErrNotOrganizationOwner):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		h.logger.WithContext(c.Request.Context()).Errorf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+func parseUUIDParam(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name})
+		return uuid.Nil, false
+	}
+	return id, true
+}
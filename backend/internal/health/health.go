@@ -0,0 +1,174 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusDegraded = "degraded"
+)
+
+// Probe checks a dependency and may return details to include in the
+// detailed status report
+type Probe func(ctx context.Context) (map[string]interface{}, error)
+
+// Check describes a registered probe. Critical checks decide readiness;
+// non-critical ones only degrade the overall status.
+type Check struct {
+	Name     string
+	Critical bool
+	Timeout  time.Duration
+	CacheTTL time.Duration
+	Probe    Probe
+}
+
+type Result struct {
+	Name      string                 `json:"name"`
+	Status    string                 `json:"status"`
+	Critical  bool                   `json:"critical"`
+	Error     string                 `json:"error,omitempty"`
+	Latency   string                 `json:"latency"`
+	CheckedAt time.Time              `json:"checked_at"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+type Report struct {
+	Status string   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+// Ready reports whether every critical check is up
+func (r Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+type entry struct {
+	check Check
+
+	mutex  sync.Mutex
+	result *Result
+}
+
+// Registry runs registered health checks with per-check timeouts and caches
+// their results so frequent probes don't hammer dependencies
+type Registry struct {
+	mutex   sync.RWMutex
+	entries []*entry
+	now     func() time.Time
+}
+
+func NewRegistry() *Registry {
+	return &Registry{now: time.Now}
+}
+
+// Register adds a check. Registering the same name again replaces it.
+func (r *Registry) Register(check Check) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, existing := range r.entries {
+		if existing.check.Name == check.Name {
+			r.entries[i] = &entry{check: check}
+			return
+		}
+	}
+	r.entries = append(r.entries, &entry{check: check})
+}
+
+// Run executes all checks concurrently, reusing cached results that are
+// still fresh
+func (r *Registry) Run(ctx context.Context) Report {
+	return r.run(ctx, false)
+}
+
+// RunCritical executes only the checks that decide readiness
+func (r *Registry) RunCritical(ctx context.Context) Report {
+	return r.run(ctx, true)
+}
+
+func (r *Registry) run(ctx context.Context, criticalOnly bool) Report {
+	r.mutex.RLock()
+	var entries []*entry
+	for _, e := range r.entries {
+		if !criticalOnly || e.check.Critical {
+			entries = append(entries, e)
+		}
+	}
+	r.mutex.RUnlock()
+
+	results := make([]Result, len(entries))
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func(i int, e *entry) {
+			defer wg.Done()
+			results[i] = r.result(ctx, e)
+		}(i, e)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: results}
+	for _, result := range results {
+		if result.Status == StatusUp {
+			continue
+		}
+		if result.Critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// result returns the cached result for an entry, running the probe when the
+// cache is stale. Concurrent callers wait for a single probe run.
+func (r *Registry) result(ctx context.Context, e *entry) Result {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	now := r.now()
+	if e.result != nil && now.Sub(e.result.CheckedAt) < e.check.CacheTTL {
+		return *e.result
+	}
+
+	probeCtx := ctx
+	if e.check.Timeout > 0 {
+		var cancel context.CancelFunc
+		probeCtx, cancel = context.WithTimeout(ctx, e.check.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	details, err := e.check.Probe(probeCtx)
+	if err == nil && probeCtx.Err() != nil {
+		err = probeCtx.Err()
+	}
+
+	result := Result{
+		Name:      e.check.Name,
+		Status:    StatusUp,
+		Critical:  e.check.Critical,
+		Latency:   time.Since(start).String(),
+		CheckedAt: now,
+		Details:   details,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+		if errors.Is(err, context.DeadlineExceeded) {
+			result.Error = "check timed out after " + e.check.Timeout.String()
+		}
+	}
+
+	// Don't cache results cut short by the caller going away
+	if ctx.Err() == nil {
+		e.result = &result
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistryReportsCriticalFailureAsDown(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(Check{Name: "database", Critical: true, Probe: func(ctx context.Context) (map[string]interface{}, error) {
+		return nil, errors.New("connection refused")
+	}})
+	registry.Register(Check{Name: "llm", Probe: func(ctx context.Context) (map[string]interface{}, error) {
+		return nil, nil
+	}})
+
+	report := registry.Run(context.Background())
+	if report.Status != StatusDown || report.Ready() {
+		t.Fatalf("expected down report, got %s", report.Status)
+	}
+	if report.Checks[0].Error != "connection refused" {
+		t.Fatalf("unexpected error: %q", report.Checks[0].Error)
+	}
+}
+
+func TestRegistryNonCriticalFailureDegrades(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(Check{Name: "database", Critical: true, Probe: func(ctx context.Context) (map[string]interface{}, error) {
+		return nil, nil
+	}})
+	registry.Register(Check{Name: "llm", Probe: func(ctx context.Context) (map[string]interface{}, error) {
+		return nil, errors.New("quota exceeded")
+	}})
+
+	report := registry.Run(context.Background())
+	if report.Status != StatusDegraded || !report.Ready() {
+		t.Fatalf("expected degraded but ready report, got %s", report.Status)
+	}
+
+	if critical := registry.RunCritical(context.Background()); len(critical.Checks) != 1 || critical.Status != StatusUp {
+		t.Fatalf("expected only the critical check to run, got %+v", critical)
+	}
+}
+
+func TestRegistryCachesResults(t *testing.T) {
+	calls := 0
+	registry := NewRegistry()
+	now := time.Now()
+	registry.now = func() time.Time { return now }
+	registry.Register(Check{Name: "database", CacheTTL: time.Minute, Probe: func(ctx context.Context) (map[string]interface{}, error) {
+		calls++
+		return nil, nil
+	}})
+
+	registry.Run(context.Background())
+	registry.Run(context.Background())
+	if calls != 1 {
+		t.Fatalf("expected cached result, probe ran %d times", calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	registry.Run(context.Background())
+	if calls != 2 {
+		t.Fatalf("expected probe to rerun after TTL, ran %d times", calls)
+	}
+}
+
+func TestRegistryAppliesTimeout(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(Check{Name: "slow", Critical: true, Timeout: 10 * time.Millisecond, Probe: func(ctx context.Context) (map[string]interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}})
+
+	report := registry.Run(context.Background())
+	if report.Status != StatusDown || report.Checks[0].Error != "check timed out after 10ms" {
+		t.Fatalf("expected timeout failure, got %+v", report.Checks[0])
+	}
+}
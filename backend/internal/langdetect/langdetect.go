@@ -0,0 +1,171 @@
+// Package langdetect guesses the language of short texts such as news
+// claims. Non-Latin scripts are identified by their Unicode block; Latin
+// text is scored against lists of common function words.
+package langdetect
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Undetermined is the ISO 639 code for text whose language is unknown
+const Undetermined = "und"
+
+// minLatinHits is how many function words Latin text needs before a
+// language is reported
+const minLatinHits = 2
+
+var names = map[string]string{
+	"ar": "Arabic",
+	"de": "German",
+	"el": "Greek",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"he": "Hebrew",
+	"hi": "Hindi",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"pt": "Portuguese",
+	"ru": "Russian",
+	"th": "Thai",
+	"zh": "Chinese",
+}
+
+// scripts maps a script to the language reported for it, in the order
+// they are checked
+var scripts = []struct {
+	table    *unicode.RangeTable
+	language string
+}{
+	{unicode.Devanagari, "hi"},
+	{unicode.Arabic, "ar"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Greek, "el"},
+	{unicode.Hebrew, "he"},
+	{unicode.Thai, "th"},
+	{unicode.Hangul, "ko"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Han, "zh"},
+}
+
+var stopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "was", "were", "of", "to", "in", "that", "it", "with", "for", "on", "this", "has", "have", "not", "by", "from"},
+	"es": {"el", "la", "los", "las", "de", "que", "y", "en", "un", "una", "es", "por", "con", "para", "del", "se", "no", "su", "al", "como"},
+	"pt": {"o", "a", "os", "as", "de", "que", "e", "em", "um", "uma", "é", "do", "da", "não", "com", "para", "por", "dos", "das", "foi"},
+	"fr": {"le", "la", "les", "de", "des", "et", "est", "un", "une", "du", "que", "en", "dans", "pour", "pas", "sur", "qui", "au", "avec", "ce"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "zu", "den", "von", "mit", "sich", "des", "auf", "für", "im", "dem", "wurde", "auch"},
+	"it": {"il", "lo", "la", "gli", "le", "di", "che", "e", "è", "un", "una", "per", "non", "del", "della", "con", "sono", "nel", "alla", "ha"},
+}
+
+// Detect returns the ISO 639-1 code of the text's language, or Undetermined
+func Detect(text string) string {
+	counts := make(map[string]int)
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, script := range scripts {
+			if unicode.Is(script.table, r) {
+				counts[script.language]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return Undetermined
+	}
+
+	// Japanese mixes kana with Han characters, so any kana wins over Chinese
+	if counts["ja"] > 0 && counts["ja"]+counts["zh"] > letters/2 {
+		return "ja"
+	}
+	best, bestCount := "", 0
+	for _, script := range scripts {
+		if n := counts[script.language]; n > bestCount {
+			best, bestCount = script.language, n
+		}
+	}
+	if bestCount > letters/2 {
+		return best
+	}
+
+	return detectLatin(text)
+}
+
+func detectLatin(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	best, bestScore, tied := Undetermined, 0, false
+	for language, list := range stopwords {
+		score := 0
+		for _, word := range words {
+			for _, stopword := range list {
+				if word == stopword {
+					score++
+					break
+				}
+			}
+		}
+		switch {
+		case score > bestScore:
+			best, bestScore, tied = language, score, false
+		case score == bestScore && score > 0:
+			tied = true
+		}
+	}
+	if bestScore < minLatinHits || tied {
+		return Undetermined
+	}
+	return best
+}
+
+// Name returns the English name of a language or locale code such as "es"
+// or "pt-BR", falling back to the code itself
+func Name(code string) string {
+	if name, ok := names[Base(code)]; ok {
+		return name
+	}
+	return code
+}
+
+// Base returns the language part of a locale code, lowercased
+func Base(code string) string {
+	base, _, _ := strings.Cut(code, "-")
+	return strings.ToLower(base)
+}
+
+// NormalizeLocale canonicalizes a locale of the form "xx" or "xx-YY",
+// accepting "_" as separator. It reports false for anything else.
+func NormalizeLocale(locale string) (string, bool) {
+	base, region, hasRegion := strings.Cut(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"), "-")
+	if !isLetters(base, 2, 3) {
+		return "", false
+	}
+	normalized := strings.ToLower(base)
+	if hasRegion {
+		if !isLetters(region, 2, 2) {
+			return "", false
+		}
+		normalized += "-" + strings.ToUpper(region)
+	}
+	return normalized, true
+}
+
+func isLetters(s string, min, max int) bool {
+	if len(s) < min || len(s) > max {
+		return false
+	}
+	for _, r := range s {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package langdetect
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	cases := map[string]string{
+		"The president said that the new law is not in effect yet":            "en",
+		"El gobierno anunció que los precios de la gasolina bajarán en enero": "es",
+		"O governo anunciou que os preços da gasolina não vão subir":          "pt",
+		"Le gouvernement a annoncé que les prix vont baisser dans le pays":    "fr",
+		"Die Regierung hat angekündigt, dass die Preise nicht steigen werden": "de",
+		"Il governo ha detto che il prezzo della benzina non è aumentato":     "it",
+		"सरकार ने घोषणा की है कि पेट्रोल की कीमतें कम होंगी":                  "hi",
+		"أعلنت الحكومة أن أسعار الوقود ستنخفض الشهر المقبل":                   "ar",
+		"Правительство объявило о снижении цен на бензин":                     "ru",
+		"政府宣布下个月汽油价格将下降":                                                      "zh",
+		"政府は来月ガソリン価格が下がると発表した":                                                "ja",
+		"정부는 다음 달 휘발유 가격이 내려간다고 발표했다":                                         "ko",
+		"1234 !!!": Undetermined,
+		"Breaking": Undetermined,
+		"":         Undetermined,
+	}
+	for text, want := range cases {
+		if got := Detect(text); got != want {
+			t.Errorf("Detect(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	cases := map[string]string{
+		"es":     "es",
+		"PT_br":  "pt-BR",
+		" en-us": "en-US",
+	}
+	for locale, want := range cases {
+		if got, ok := NormalizeLocale(locale); !ok || got != want {
+			t.Errorf("NormalizeLocale(%q) = %q, %v, want %q", locale, got, ok, want)
+		}
+	}
+	for _, locale := range []string{"", "e", "english", "es-", "es-419", "ñe"} {
+		if _, ok := NormalizeLocale(locale); ok {
+			t.Errorf("NormalizeLocale(%q) accepted an invalid locale", locale)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+type contextKey struct{}
+
+// requestFields holds the IDs attached to every log line of a request. It is
+// shared by pointer so the auth middleware can add the user ID after the
+// request ID middleware has already stored it in the context.
+type requestFields struct {
+	mu        sync.RWMutex
+	requestID string
+	userID    string
+}
+
+// WithRequestID returns a context whose log entries carry the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, &requestFields{requestID: requestID})
+}
+
+// SetUserID records the authenticated user on the request's log fields. It is
+// a no-op for contexts that did not pass through WithRequestID.
+func SetUserID(ctx context.Context, userID string) {
+	if fields, ok := ctx.Value(contextKey{}).(*requestFields); ok {
+		fields.mu.Lock()
+		fields.userID = userID
+		fields.mu.Unlock()
+	}
+}
+
+// RequestID returns the request ID stored in the context, if any
+func RequestID(ctx context.Context) string {
+	if fields, ok := ctx.Value(contextKey{}).(*requestFields); ok {
+		fields.mu.RLock()
+		defer fields.mu.RUnlock()
+		return fields.requestID
+	}
+	return ""
+}
+
+// ContextHook adds request_id and user_id to entries logged with
+// logger.WithContext(ctx)
+type ContextHook struct{}
+
+func (ContextHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (ContextHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	fields, ok := entry.Context.Value(contextKey{}).(*requestFields)
+	if !ok {
+		return nil
+	}
+
+	fields.mu.RLock()
+	defer fields.mu.RUnlock()
+	if fields.requestID != "" {
+		entry.Data["request_id"] = fields.requestID
+	}
+	if fields.userID != "" {
+		entry.Data["user_id"] = fields.userID
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LevelStatus describes the active log level and any temporary override
+type LevelStatus struct {
+	Level         string     `json:"level"`
+	ConfiguredAs  string     `json:"configured_level"`
+	OverrideUntil *time.Time `json:"override_until,omitempty"`
+}
+
+// LevelController changes the logger's level at runtime. The configured
+// level comes from config reloads; an override set by an admin replaces it
+// until it expires and the configured level is restored.
+type LevelController struct {
+	logger *logrus.Logger
+
+	mu            sync.Mutex
+	configured    logrus.Level
+	overrideUntil time.Time
+	timer         *time.Timer
+}
+
+func NewLevelController(logger *logrus.Logger) *LevelController {
+	return &LevelController{
+		logger:     logger,
+		configured: logger.GetLevel(),
+	}
+}
+
+// SetConfigured updates the configured level. It takes effect immediately
+// unless an override is active.
+func (lc *LevelController) SetConfigured(level logrus.Level) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	lc.configured = level
+	if lc.timer == nil {
+		lc.logger.SetLevel(level)
+	}
+}
+
+// Override sets level for duration, then reverts to the configured level
+func (lc *LevelController) Override(level logrus.Level, duration time.Duration) LevelStatus {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if lc.timer != nil {
+		lc.timer.Stop()
+	}
+
+	lc.logger.SetLevel(level)
+	lc.overrideUntil = time.Now().Add(duration)
+
+	var timer *time.Timer
+	timer = time.AfterFunc(duration, func() {
+		lc.mu.Lock()
+		defer lc.mu.Unlock()
+		// A newer override or a reset replaced this timer
+		if lc.timer != timer {
+			return
+		}
+		lc.clearLocked()
+		lc.logger.Infof("Log level override expired, restored %s", lc.configured)
+	})
+	lc.timer = timer
+
+	return lc.statusLocked()
+}
+
+// Reset removes any override and restores the configured level
+func (lc *LevelController) Reset() LevelStatus {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if lc.timer != nil {
+		lc.timer.Stop()
+	}
+	lc.clearLocked()
+	return lc.statusLocked()
+}
+
+func (lc *LevelController) Status() LevelStatus {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	return lc.statusLocked()
+}
+
+func (lc *LevelController) clearLocked() {
+	lc.timer = nil
+	lc.overrideUntil = time.Time{}
+	lc.logger.SetLevel(lc.configured)
+}
+
+func (lc *LevelController) statusLocked() LevelStatus {
+	status := LevelStatus{
+		Level:        lc.logger.GetLevel().String(),
+		ConfiguredAs: lc.configured.String(),
+	}
+	if lc.timer != nil {
+		until := lc.overrideUntil
+		status.OverrideUntil = &until
+	}
+	return status
+}
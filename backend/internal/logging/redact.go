@@ -0,0 +1,99 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are field names whose values are always dropped. A key also
+// matches with a prefix, e.g. access_token or client_secret.
+var sensitiveKeys = []string{"authorization", "cookie", "password", "secret", "token", "api_key", "apikey"}
+
+var redactPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	// Authorization headers and bearer credentials
+	{regexp.MustCompile(`(?i)\b(authorization\s*[:=]\s*)("?)[^\s",]+(\s+[^\s",]+)?`), "${1}${2}" + redacted},
+	{regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`), "Bearer " + redacted},
+	// JWTs
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), redacted},
+	// OpenAI and Google OAuth credentials
+	{regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{8,}`), redacted},
+	{regexp.MustCompile(`\bya29\.[A-Za-z0-9_-]+`), redacted},
+	{regexp.MustCompile(`\b4/[0-9A-Za-z_-]{10,}`), redacted},
+	// key=value pairs such as token=... or client_secret: ...
+	{regexp.MustCompile(`(?i)\b([a-z_]*(?:token|secret|password|api_key|apikey)\s*[:=]\s*)("?)[^\s",&]+`), "${1}${2}" + redacted},
+	// Email addresses
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[REDACTED_EMAIL]"},
+}
+
+// RedactionHook scrubs credentials and email addresses from log messages and
+// fields. Register it after any hook that adds fields so their values are
+// scrubbed too.
+type RedactionHook struct {
+	secrets []string
+}
+
+// NewRedactionHook returns a hook that also removes the given literal values,
+// such as the configured JWT secret and API keys, wherever they appear
+func NewRedactionHook(secrets ...string) *RedactionHook {
+	hook := &RedactionHook{}
+	for _, secret := range secrets {
+		// Very short values would redact unrelated text
+		if len(secret) >= 8 {
+			hook.secrets = append(hook.secrets, secret)
+		}
+	}
+	return hook
+}
+
+func (h *RedactionHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *RedactionHook) Fire(entry *logrus.Entry) error {
+	entry.Message = h.Redact(entry.Message)
+
+	for key, value := range entry.Data {
+		if isSensitiveKey(key) {
+			entry.Data[key] = redacted
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = h.Redact(v)
+		case error:
+			entry.Data[key] = h.Redact(v.Error())
+		}
+	}
+	return nil
+}
+
+// Redact returns s with secrets, tokens and email addresses replaced
+func (h *RedactionHook) Redact(s string) string {
+	for _, secret := range h.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	for _, p := range redactPatterns {
+		s = p.pattern.ReplaceAllString(s, p.replacement)
+	}
+	return s
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	if key == "code" {
+		return true
+	}
+	for _, sensitive := range sensitiveKeys {
+		if key == sensitive || strings.HasSuffix(key, "_"+sensitive) {
+			return true
+		}
+	}
+	return false
+}
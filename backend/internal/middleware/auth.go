@@ -1,15 +1,27 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
-	"fact-check/internal/services"
+	"fact-check/internal/logging"
+	"fact-check/internal/models"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
+// TokenValidator resolves a bearer token to a user ID
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, tokenString string) (string, error)
+}
+
+// UserFinder looks up users by ID
+type UserFinder interface {
+	GetUserByID(ctx context.Context, userID string) (*models.User, error)
+}
+
+func AuthMiddleware(authService TokenValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -29,7 +41,7 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 		token := tokenParts[1]
 
 		// Validate token
-		userID, err := authService.ValidateToken(token)
+		userID, err := authService.ValidateToken(c.Request.Context(), token)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
@@ -38,6 +50,28 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 
 		// Set user ID in context for handlers to use
 		c.Set("user_id", userID)
+		logging.SetUserID(c.Request.Context(), userID)
+		c.Next()
+	}
+}
+
+// RequireRole must run after AuthMiddleware and rejects users without the given role
+func RequireRole(users UserFinder, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := users.GetUserByID(c.Request.Context(), c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			c.Abort()
+			return
+		}
+
+		if user.Role != role {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_role", user.Role)
 		c.Next()
 	}
 }
@@ -5,6 +5,8 @@ import (
 	"sync"
 	"time"
 
+	"fact-check/internal/metrics"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -23,6 +25,15 @@ func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
 	}
 }
 
+// SetLimit changes the limit and window for subsequent requests
+func (rl *RateLimiter) SetLimit(limit int, window time.Duration) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	rl.limit = limit
+	rl.window = window
+}
+
 func (rl *RateLimiter) Limit() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get client IP
@@ -47,6 +58,7 @@ func (rl *RateLimiter) Limit() gin.HandlerFunc {
 
 		// Check if limit exceeded
 		if len(rl.requests[clientIP]) >= rl.limit {
+			metrics.RateLimitRejections.WithLabelValues(routeLabel(c)).Inc()
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error": "Rate limit exceeded. Please try again later.",
 			})
@@ -1,43 +1,208 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Moderation states of a news item. Pending batch rows passed the rules but
+// are classified by the LLM before they are verified.
+const (
+	ModerationAllowed     = "allowed"
+	ModerationPending     = "pending"
+	ModerationQuarantined = "quarantined"
+	ModerationRejected    = "rejected"
+)
+
 type User struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	GoogleID  string    `json:"google_id" db:"google_id"`
-	Email     string    `json:"email" db:"email"`
-	Name      string    `json:"name" db:"name"`
-	Picture   string    `json:"picture" db:"picture"`
+	ID       uuid.UUID `json:"id" db:"id"`
+	GoogleID string    `json:"google_id" db:"google_id"`
+	Email    string    `json:"email" db:"email"`
+	Name     string    `json:"name" db:"name"`
+	Picture  string    `json:"picture" db:"picture"`
+	Role     string    `json:"role" db:"role"`
+	// AdminByEmail is set while the admin role comes from ADMIN_EMAILS,
+	// which takes it back once the address is no longer listed
+	AdminByEmail bool `json:"-" db:"admin_by_email"`
+	// Locale is the language explanations are written in for this user
+	Locale    *string   `json:"locale,omitempty" db:"locale"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// DeletionScheduledFor is set while an account deletion is pending
+	DeletionScheduledFor *time.Time `json:"deletion_scheduled_for,omitempty" db:"deletion_scheduled_for"`
+	// DeletedAt is set once the account has been anonymized
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 type News struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	UserID      uuid.UUID `json:"user_id" db:"user_id"`
-	Content     string    `json:"content" db:"content"`
-	Link        *string   `json:"link,omitempty" db:"link"`
-	PhotoURL    *string   `json:"photo_url,omitempty" db:"photo_url"`
-	Status      string    `json:"status" db:"status"`
-	Explanation *string   `json:"explanation,omitempty" db:"explanation"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID      uuid.UUID `json:"id" db:"id"`
+	UserID  uuid.UUID `json:"user_id" db:"user_id"`
+	Content string    `json:"content" db:"content"`
+	Link    *string   `json:"link,omitempty" db:"link"`
+	// CanonicalLink is Link after following redirects and removing
+	// tracking parameters and AMP or mobile variants
+	CanonicalLink *string `json:"canonical_link,omitempty" db:"canonical_link"`
+	// FetchedHost is the host the link's page was served from after
+	// redirects, nil when it was not fetched. Sources are matched on it
+	// rather than on the canonical link the page declares.
+	FetchedHost *string `json:"fetched_host,omitempty" db:"fetched_host"`
+	PhotoURL    *string `json:"photo_url,omitempty" db:"photo_url"`
+	Topic       *string `json:"topic,omitempty" db:"topic"`
+	// Language is the detected ISO 639-1 code of Content, "und" if unknown
+	Language    *string `json:"language,omitempty" db:"language"`
+	Status      string  `json:"status" db:"status"`
+	Explanation *string `json:"explanation,omitempty" db:"explanation"`
+	// PromptVersion is the published prompt template the verdict came from,
+	// nil for the built-in prompt
+	PromptVersion *int `json:"prompt_version,omitempty" db:"prompt_version"`
+	// ModelVerdicts holds each model's answer when an ensemble verified the
+	// news; NeedsReview is set when they disagreed
+	ModelVerdicts []ModelVerdict `json:"model_verdicts,omitempty" db:"model_verdicts"`
+	NeedsReview   bool           `json:"needs_review" db:"needs_review"`
+	// Confidence is the calibrated confidence of the verdict and
+	// RawConfidence the one the model stated. ModelStatus is the model's
+	// verdict before abstention or review replaced it.
+	Confidence    *float64 `json:"confidence,omitempty" db:"confidence"`
+	RawConfidence *float64 `json:"raw_confidence,omitempty" db:"raw_confidence"`
+	ModelStatus   *string  `json:"model_status,omitempty" db:"model_status"`
+	// ReviewedBy and ReviewedAt are set once a reviewer resolved the verdict
+	ReviewedBy *uuid.UUID `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	// Moderation is "allowed", "quarantined" while the submission waits for
+	// a moderator, or "rejected" by one. Only allowed news is verified.
+	Moderation         string     `json:"moderation" db:"moderation"`
+	ModerationCategory *string    `json:"moderation_category,omitempty" db:"moderation_category"`
+	ModerationReason   *string    `json:"moderation_reason,omitempty" db:"moderation_reason"`
+	ModeratedBy        *uuid.UUID `json:"moderated_by,omitempty" db:"moderated_by"`
+	ModeratedAt        *time.Time `json:"moderated_at,omitempty" db:"moderated_at"`
+	// Published is set when the submitter opted in to the public feeds and
+	// ClaimReview; other news is only visible to its owner
+	Published bool       `json:"published" db:"published"`
+	BatchID   *uuid.UUID `json:"batch_id,omitempty" db:"batch_id"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 type NewsSubmission struct {
 	Content  string  `json:"content" binding:"required"`
 	Link     *string `json:"link,omitempty"`
 	PhotoURL *string `json:"photo_url,omitempty"`
+	Topic    *string `json:"topic,omitempty" binding:"omitempty,max=50"`
+	// Publish opts the news in to the public feeds once verified
+	Publish bool `json:"publish"`
+}
+
+// PublishRequest publishes news or takes it off the public feeds
+type PublishRequest struct {
+	Published *bool `json:"published" binding:"required"`
 }
 
 type NewsVerification struct {
 	ID          uuid.UUID `json:"id"`
 	Status      string    `json:"status"`
 	Explanation string    `json:"explanation"`
+	Model       string    `json:"model,omitempty"`
+	Source      *Source   `json:"source,omitempty"`
+	// Language is the detected language of the news, Locale the one the
+	// explanation is written in
+	Language      string         `json:"language,omitempty"`
+	Locale        string         `json:"locale,omitempty"`
+	PromptVersion *int           `json:"prompt_version,omitempty"`
+	ModelVerdicts []ModelVerdict `json:"model_verdicts,omitempty"`
+	NeedsReview   bool           `json:"needs_review,omitempty"`
+	// Confidence is calibrated from reviewed outcomes; RawConfidence is the
+	// one the model stated
+	Confidence    float64 `json:"confidence"`
+	RawConfidence float64 `json:"raw_confidence"`
+	// ModelStatus is the model's verdict when Status is "unverifiable"
+	// because the confidence was below the abstention threshold
+	ModelStatus string `json:"model_status,omitempty"`
+}
+
+// ModelVerdict is one ensemble member's answer. Error is set instead of a
+// status when the model could not be asked.
+type ModelVerdict struct {
+	Model       string  `json:"model"`
+	Status      string  `json:"status,omitempty"`
+	Confidence  float64 `json:"confidence,omitempty"`
+	Explanation string  `json:"explanation,omitempty"`
+	Error       string  `json:"error,omitempty"`
+}
+
+// Verdict is the outcome of a verification as stored on a news item
+type Verdict struct {
+	Status        string
+	Explanation   string
+	PromptVersion *int
+	ModelVerdicts []ModelVerdict
+	NeedsReview   bool
+	Confidence    float64
+	RawConfidence float64
+	ModelStatus   string
+}
+
+// Resolution is a reviewer's verdict on a news item flagged for review
+type Resolution struct {
+	Status      string
+	Explanation string
+	ReviewerID  uuid.UUID
+	ReviewedAt  time.Time
+}
+
+// Calibration is a fitted mapping from stated to calibrated confidence.
+// BrierBefore and BrierAfter score the stated and calibrated confidences on
+// the samples it was fitted to; lower is better.
+type Calibration struct {
+	ID          uuid.UUID       `json:"id" db:"id"`
+	Method      string          `json:"method" db:"method"`
+	Params      json.RawMessage `json:"params" db:"params"`
+	Samples     int             `json:"samples" db:"samples"`
+	BrierBefore float64         `json:"brier_before" db:"brier_before"`
+	BrierAfter  float64         `json:"brier_after" db:"brier_after"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+}
+
+// CalibrationOutcome is a reviewed verdict: the confidence the model stated
+// and whether the reviewer kept its verdict
+type CalibrationOutcome struct {
+	Confidence float64
+	Correct    bool
+}
+
+// ModerationDecision is a moderator's decision on a quarantined news item
+type ModerationDecision struct {
+	Moderation  string
+	Reason      string
+	ModeratorID uuid.UUID
+	ModeratedAt time.Time
+}
+
+// Screening is the LLM's classification of a pending news item. Category
+// and Reason are empty when it is allowed.
+type Screening struct {
+	Moderation string
+	Category   string
+	Reason     string
+}
+
+// ModerationRequest allows or rejects a quarantined submission
+type ModerationRequest struct {
+	Action string `json:"action" binding:"required,oneof=allow reject"`
+	Reason string `json:"reason" binding:"max=1000"`
+}
+
+// ReviewRequest resolves a flagged verdict; an empty explanation keeps the
+// model's one
+type ReviewRequest struct {
+	Status      string `json:"status" binding:"required,oneof=true false uncertain"`
+	Explanation string `json:"explanation" binding:"max=5000"`
 }
 
 type GoogleUserInfo struct {
@@ -56,3 +221,398 @@ type AuthCallbackResponse struct {
 	Token string `json:"token"`
 	User  User   `json:"user"`
 }
+
+type TokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type LLMUsage struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	UserID           uuid.UUID  `json:"user_id" db:"user_id"`
+	NewsID           *uuid.UUID `json:"news_id,omitempty" db:"news_id"`
+	Model            string     `json:"model" db:"model"`
+	PromptTokens     int        `json:"prompt_tokens" db:"prompt_tokens"`
+	CompletionTokens int        `json:"completion_tokens" db:"completion_tokens"`
+	TotalTokens      int        `json:"total_tokens" db:"total_tokens"`
+	CostUSD          float64    `json:"cost_usd" db:"cost_usd"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+}
+
+type UsageSummary struct {
+	Key              string  `json:"key"`
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+}
+
+type BudgetStatus struct {
+	Scope        string  `json:"scope"`
+	Period       string  `json:"period"`
+	LimitUSD     float64 `json:"limit_usd"`
+	SpentUSD     float64 `json:"spent_usd"`
+	RemainingUSD float64 `json:"remaining_usd"`
+}
+
+type UsageReport struct {
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	UserID  *uuid.UUID     `json:"user_id,omitempty"`
+	Total   UsageSummary   `json:"total"`
+	ByModel []UsageSummary `json:"by_model"`
+	ByUser  []UsageSummary `json:"by_user,omitempty"`
+	Budgets []BudgetStatus `json:"budgets,omitempty"`
+}
+
+// Organization roles
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleMember = "member"
+)
+
+type Organization struct {
+	ID        uuid.UUID          `json:"id" db:"id"`
+	Name      string             `json:"name" db:"name"`
+	Policy    OrganizationPolicy `json:"policy"`
+	CreatedAt time.Time          `json:"created_at" db:"created_at"`
+}
+
+// OrganizationPolicy holds the settings applied to members' submissions.
+// Unset settings follow the server configuration.
+type OrganizationPolicy struct {
+	// RedactPII replaces personal data with placeholders before content is
+	// sent to an LLM
+	RedactPII *bool `json:"redact_pii" db:"redact_pii"`
+}
+
+type OrganizationMember struct {
+	OrganizationID uuid.UUID `json:"organization_id" db:"organization_id"`
+	UserID         uuid.UUID `json:"user_id" db:"user_id"`
+	Role           string    `json:"role" db:"role"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// Webhook event types
+const (
+	EventNewsSubmitted  = "news.submitted"
+	EventNewsVerified   = "news.verified"
+	EventVerdictChanged = "verdict.changed"
+)
+
+// WebhookEvents lists the events an endpoint can subscribe to
+var WebhookEvents = []string{EventNewsSubmitted, EventNewsVerified, EventVerdictChanged}
+
+// WebhookEndpoint is owned by either a user or an organization
+type WebhookEndpoint struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	UserID         *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty" db:"organization_id"`
+	URL            string     `json:"url" db:"url"`
+	Secret         string     `json:"-" db:"secret"`
+	Events         []string   `json:"events" db:"events"`
+	Active         bool       `json:"active" db:"active"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Webhook delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id" db:"id"`
+	EndpointID     uuid.UUID       `json:"endpoint_id" db:"endpoint_id"`
+	EventID        uuid.UUID       `json:"event_id" db:"event_id"`
+	Event          string          `json:"event" db:"event"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	LastStatusCode *int            `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      *string         `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	RedeliveryOf   *uuid.UUID      `json:"redelivery_of,omitempty" db:"redelivery_of"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+type OrganizationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type OrganizationMemberRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+	Role   string    `json:"role"`
+}
+
+type WebhookEndpointRequest struct {
+	URL            string     `json:"url" binding:"required"`
+	Events         []string   `json:"events" binding:"required"`
+	OrganizationID *uuid.UUID `json:"organization_id"`
+}
+
+// WebhookEndpointCreated is returned once on registration; the signing
+// secret is not shown again
+type WebhookEndpointCreated struct {
+	WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+// WebhookEvent is the JSON body posted to webhook endpoints
+type WebhookEvent struct {
+	ID        uuid.UUID   `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// NewsEventData describes a news item in webhook events
+type NewsEventData struct {
+	NewsID         uuid.UUID `json:"news_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previous_status,omitempty"`
+	Explanation    *string   `json:"explanation,omitempty"`
+	Link           *string   `json:"link,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Verification job statuses
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// QueueBacklog summarizes the verification jobs waiting for or held by workers
+type QueueBacklog struct {
+	Queued         int
+	Running        int
+	OldestQueuedAt *time.Time
+}
+
+// Batch groups news items submitted together
+type Batch struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Total     int       `json:"total" db:"total"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// VerificationJob is a queued verification of one news item
+type VerificationJob struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	NewsID     uuid.UUID  `json:"news_id" db:"news_id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	BatchID    *uuid.UUID `json:"batch_id,omitempty" db:"batch_id"`
+	Status     string     `json:"status" db:"status"`
+	Attempts   int        `json:"attempts" db:"attempts"`
+	Verdict    *string    `json:"verdict,omitempty" db:"verdict"`
+	Error      *string    `json:"error,omitempty" db:"error"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty" db:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+// BatchRow is one parsed row of a batch submission; Row is 1-based
+type BatchRow struct {
+	Row        int
+	Submission NewsSubmission
+	// Err is set when the row could not be parsed
+	Err error
+}
+
+type BatchRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+type BatchItem struct {
+	Row    int       `json:"row"`
+	NewsID uuid.UUID `json:"news_id"`
+	// JobID is nil for quarantined rows, which are verified once a
+	// moderator allows them
+	JobID       *uuid.UUID `json:"job_id,omitempty"`
+	Quarantined bool       `json:"quarantined,omitempty"`
+}
+
+// BatchSubmissionResult reports which rows were accepted and why the others
+// were rejected
+type BatchSubmissionResult struct {
+	BatchID  *uuid.UUID `json:"batch_id,omitempty"`
+	Accepted int        `json:"accepted"`
+	Rejected int        `json:"rejected"`
+	// Quarantined counts the accepted rows held for a moderator
+	Quarantined int             `json:"quarantined"`
+	Items       []BatchItem     `json:"items"`
+	Errors      []BatchRowError `json:"errors,omitempty"`
+}
+
+// BatchProgress aggregates the verification jobs of a batch
+type BatchProgress struct {
+	BatchID   uuid.UUID          `json:"batch_id"`
+	Total     int                `json:"total"`
+	Queued    int                `json:"queued"`
+	Running   int                `json:"running"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Completed bool               `json:"completed"`
+	Verdicts  map[string]int     `json:"verdicts"`
+	Jobs      []*VerificationJob `json:"jobs"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// Audit actions
+const (
+	AuditAccountExported          = "account.exported"
+	AuditAccountDeletionRequested = "account.deletion_requested"
+	AuditAccountDeletionCancelled = "account.deletion_cancelled"
+	AuditAccountDeleted           = "account.deleted"
+)
+
+// AuditEntry records a privacy-relevant action. SubjectID is not a foreign
+// key so entries outlive the account they describe.
+type AuditEntry struct {
+	ID        uuid.UUID              `json:"id" db:"id"`
+	Action    string                 `json:"action" db:"action"`
+	ActorID   *uuid.UUID             `json:"actor_id,omitempty" db:"actor_id"`
+	SubjectID uuid.UUID              `json:"subject_id" db:"subject_id"`
+	Details   map[string]interface{} `json:"details,omitempty" db:"details"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+}
+
+// AccountExport bundles everything stored about a user
+type AccountExport struct {
+	ExportedAt    time.Time          `json:"exported_at"`
+	Profile       *User              `json:"profile"`
+	News          []*News            `json:"news"`
+	Usage         []LLMUsage         `json:"usage"`
+	Organizations []*Organization    `json:"organizations"`
+	Webhooks      []*WebhookEndpoint `json:"webhooks"`
+}
+
+// ClaimReview is a schema.org ClaimReview document for a verified claim
+type ClaimReview struct {
+	Context       string          `json:"@context"`
+	Type          string          `json:"@type"`
+	URL           string          `json:"url,omitempty"`
+	ClaimReviewed string          `json:"claimReviewed"`
+	ReviewBody    string          `json:"reviewBody,omitempty"`
+	DatePublished string          `json:"datePublished"`
+	Author        SchemaOrgEntity `json:"author"`
+	ReviewRating  ReviewRating    `json:"reviewRating"`
+	ItemReviewed  ReviewedClaim   `json:"itemReviewed"`
+}
+
+// SchemaOrgEntity is a schema.org Organization, CreativeWork or similar
+// thing identified by name and URL
+type SchemaOrgEntity struct {
+	Type string `json:"@type"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// ReviewRating places a verdict on a numeric scale; AlternateName is the
+// human-readable verdict
+type ReviewRating struct {
+	Type          string `json:"@type"`
+	RatingValue   int    `json:"ratingValue"`
+	BestRating    int    `json:"bestRating"`
+	WorstRating   int    `json:"worstRating"`
+	AlternateName string `json:"alternateName"`
+}
+
+// ReviewedClaim is the schema.org Claim a review is about
+type ReviewedClaim struct {
+	Type       string            `json:"@type"`
+	Author     *SchemaOrgEntity  `json:"author,omitempty"`
+	URL        string            `json:"url,omitempty"`
+	Appearance []SchemaOrgEntity `json:"appearance,omitempty"`
+}
+
+// Source credibility ratings
+const (
+	CredibilityHigh  = "high"
+	CredibilityMixed = "mixed"
+	CredibilityLow   = "low"
+)
+
+// SourceCredibilities lists the accepted credibility ratings
+var SourceCredibilities = []string{CredibilityHigh, CredibilityMixed, CredibilityLow}
+
+// Source is a registry entry describing a publisher domain. Subdomains of
+// Domain match the entry too.
+type Source struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	Domain      string    `json:"domain" db:"domain"`
+	Name        string    `json:"name" db:"name"`
+	Credibility string    `json:"credibility" db:"credibility"`
+	Ownership   string    `json:"ownership,omitempty" db:"ownership"`
+	BiasNotes   string    `json:"bias_notes,omitempty" db:"bias_notes"`
+	Satire      bool      `json:"satire" db:"satire"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type SourceRequest struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	Credibility string `json:"credibility" binding:"required"`
+	Ownership   string `json:"ownership,omitempty" binding:"max=255"`
+	BiasNotes   string `json:"bias_notes,omitempty" binding:"max=2000"`
+	Satire      bool   `json:"satire"`
+}
+
+// SourceImportResult reports a CSV import of the source registry
+type SourceImportResult struct {
+	Imported int             `json:"imported"`
+	Rejected int             `json:"rejected"`
+	Errors   []BatchRowError `json:"errors,omitempty"`
+}
+
+// NewsTranslation is a news item's content, and explanation if it has a
+// verdict, translated into another locale
+type NewsTranslation struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	NewsID      uuid.UUID `json:"news_id" db:"news_id"`
+	Locale      string    `json:"locale" db:"locale"`
+	Content     string    `json:"content" db:"content"`
+	Explanation *string   `json:"explanation,omitempty" db:"explanation"`
+	Model       string    `json:"model" db:"model"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// LocaleRequest sets the preferred locale; an empty locale clears it
+type LocaleRequest struct {
+	Locale string `json:"locale" binding:"max=16"`
+}
+
+// PromptVersion is a published verification prompt. The system prompt and
+// template never change once published; Weight is the share of
+// verifications assigned to it, 0 taking it out of rotation.
+type PromptVersion struct {
+	Version     int        `json:"version" db:"version"`
+	Description string     `json:"description,omitempty" db:"description"`
+	System      string     `json:"system" db:"system_prompt"`
+	Template    string     `json:"template" db:"template"`
+	Weight      int        `json:"weight" db:"weight"`
+	CreatedBy   *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type PromptVersionRequest struct {
+	Description string `json:"description,omitempty" binding:"max=500"`
+	System      string `json:"system" binding:"required,max=10000"`
+	Template    string `json:"template" binding:"required,max=20000"`
+	Weight      int    `json:"weight" binding:"min=0,max=1000"`
+}
+
+type PromptWeightRequest struct {
+	Weight *int `json:"weight" binding:"required,min=0,max=1000"`
+}
@@ -0,0 +1,148 @@
+// Package moderation decides whether a submission is allowed, held for a
+// moderator or rejected, by running it through a chain of classifiers.
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Actions a classifier can take, from least to most strict
+const (
+	ActionAllow      = "allow"
+	ActionQuarantine = "quarantine"
+	ActionReject     = "reject"
+)
+
+// severity orders actions so the strictest decision wins
+var severity = map[string]int{
+	ActionAllow:      0,
+	ActionQuarantine: 1,
+	ActionReject:     2,
+}
+
+// ValidAction reports whether action is allow, quarantine or reject
+func ValidAction(action string) bool {
+	_, ok := severity[action]
+	return ok
+}
+
+// Submission is the content a classifier judges
+type Submission struct {
+	UserID  string
+	Content string
+	Link    string
+}
+
+// Decision is what a classifier made of a submission. Category and Reason
+// are empty when it is allowed.
+type Decision struct {
+	Action     string `json:"action"`
+	Category   string `json:"category,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	Classifier string `json:"classifier,omitempty"`
+}
+
+// Allow is the decision for content nothing objected to
+var Allow = Decision{Action: ActionAllow}
+
+// Classifier judges submissions
+type Classifier interface {
+	Name() string
+	Classify(ctx context.Context, submission *Submission) (Decision, error)
+}
+
+// Rule takes Action on submissions whose content or link matches Pattern
+type Rule struct {
+	Category string
+	Action   string
+	Pattern  *regexp.Regexp
+}
+
+// KeywordPattern matches any of the keywords as whole words, ignoring case
+func KeywordPattern(keywords []string) (*regexp.Regexp, error) {
+	quoted := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			quoted = append(quoted, regexp.QuoteMeta(keyword))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil, errors.New("no keywords given")
+	}
+	return regexp.Compile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+}
+
+// RuleClassifier matches keyword and regular expression rules
+type RuleClassifier struct {
+	rules []Rule
+}
+
+func NewRuleClassifier(rules []Rule) *RuleClassifier {
+	return &RuleClassifier{rules: rules}
+}
+
+func (c *RuleClassifier) Name() string {
+	return "rules"
+}
+
+// Classify returns the strictest action of the matching rules
+func (c *RuleClassifier) Classify(ctx context.Context, submission *Submission) (Decision, error) {
+	decision := Allow
+	for _, rule := range c.rules {
+		if severity[rule.Action] <= severity[decision.Action] {
+			continue
+		}
+		text := submission.Content + "\n" + submission.Link
+		if match := rule.Pattern.FindString(text); match != "" {
+			decision = Decision{
+				Action:     rule.Action,
+				Category:   rule.Category,
+				Reason:     fmt.Sprintf("matched %q", match),
+				Classifier: c.Name(),
+			}
+		}
+	}
+	return decision, nil
+}
+
+// Gate runs classifiers in order and keeps the strictest decision. A
+// rejection stops the chain so later, costlier classifiers are skipped.
+type Gate struct {
+	classifiers []Classifier
+	onError     string
+}
+
+// NewGate creates a gate; onError is the action taken when a classifier fails
+func NewGate(onError string, classifiers ...Classifier) *Gate {
+	return &Gate{classifiers: classifiers, onError: onError}
+}
+
+// Check classifies a submission. The error reports failed classifiers; the
+// decision already accounts for them.
+func (g *Gate) Check(ctx context.Context, submission *Submission) (Decision, error) {
+	decision := Allow
+	var errs []error
+	for _, classifier := range g.classifiers {
+		result, err := classifier.Classify(ctx, submission)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s classifier: %w", classifier.Name(), err))
+			result = Decision{
+				Action:     g.onError,
+				Category:   "unclassified",
+				Reason:     "classifier failed",
+				Classifier: classifier.Name(),
+			}
+		}
+		if severity[result.Action] > severity[decision.Action] {
+			decision = result
+		}
+		if decision.Action == ActionReject {
+			break
+		}
+	}
+	return decision, errors.Join(errs...)
+}
@@ -0,0 +1,82 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+)
+
+type fixedClassifier struct {
+	decision Decision
+	err      error
+	calls    int
+}
+
+func (c *fixedClassifier) Name() string {
+	return "fixed"
+}
+
+func (c *fixedClassifier) Classify(ctx context.Context, submission *Submission) (Decision, error) {
+	c.calls++
+	return c.decision, c.err
+}
+
+func TestRuleClassifier(t *testing.T) {
+	spam, err := KeywordPattern([]string{"buy now", " casino "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rules := NewRuleClassifier([]Rule{
+		{Category: "spam", Action: ActionQuarantine, Pattern: spam},
+		{Category: "doxxing", Action: ActionReject, Pattern: regexp.MustCompile(`(?i)home address of`)},
+	})
+
+	tests := []struct {
+		content string
+		link    string
+		want    Decision
+	}{
+		{content: "The mayor opened a new bridge", want: Allow},
+		{content: "Casinos are banned", want: Allow},
+		{content: "BUY NOW before the election", want: Decision{Action: ActionQuarantine, Category: "spam", Reason: `matched "BUY NOW"`, Classifier: "rules"}},
+		{content: "Best odds", link: "https://example.com/casino", want: Decision{Action: ActionQuarantine, Category: "spam", Reason: `matched "casino"`, Classifier: "rules"}},
+		{content: "Casino spam and the home address of a reporter", want: Decision{Action: ActionReject, Category: "doxxing", Reason: `matched "home address of"`, Classifier: "rules"}},
+	}
+
+	for _, test := range tests {
+		got, err := rules.Classify(context.Background(), &Submission{Content: test.content, Link: test.link})
+		if err != nil || got != test.want {
+			t.Errorf("%q: expected %+v, got %+v (%v)", test.content, test.want, got, err)
+		}
+	}
+
+	if _, err := KeywordPattern([]string{" ", ""}); err == nil {
+		t.Error("expected an error without keywords")
+	}
+}
+
+func TestGateKeepsStrictestDecision(t *testing.T) {
+	quarantine := &fixedClassifier{decision: Decision{Action: ActionQuarantine, Category: "spam"}}
+	reject := &fixedClassifier{decision: Decision{Action: ActionReject, Category: "harassment"}}
+	skipped := &fixedClassifier{decision: Allow}
+
+	decision, err := NewGate(ActionAllow, quarantine, reject, skipped).Check(context.Background(), &Submission{Content: "claim"})
+	if err != nil || decision.Category != "harassment" {
+		t.Fatalf("expected the rejection, got %+v (%v)", decision, err)
+	}
+	if skipped.calls != 0 {
+		t.Fatal("expected classifiers after a rejection to be skipped")
+	}
+
+	// Failures take the configured action and are reported
+	failing := &fixedClassifier{err: errors.New("timeout")}
+	decision, err = NewGate(ActionQuarantine, failing).Check(context.Background(), &Submission{Content: "claim"})
+	if err == nil || decision.Action != ActionQuarantine || decision.Category != "unclassified" {
+		t.Fatalf("expected a quarantine on failure, got %+v (%v)", decision, err)
+	}
+	decision, _ = NewGate(ActionAllow, failing).Check(context.Background(), &Submission{Content: "claim"})
+	if decision.Action != ActionAllow {
+		t.Fatalf("expected failures to allow, got %+v", decision)
+	}
+}
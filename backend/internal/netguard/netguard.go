@@ -0,0 +1,79 @@
+// Package netguard keeps requests to user-supplied URLs away from loopback,
+// private and link-local networks.
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned for targets that are not publicly routable
+var ErrForbiddenAddress = errors.New("address is not publicly routable")
+
+// IsPublic reports whether ip is a globally routable unicast address
+func IsPublic(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which
+// net.IP.IsPrivate does not cover
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// ValidateURL checks that raw is an absolute http(s) URL without credentials.
+// Unless allowPrivate is set, hosts that are obviously internal are rejected
+// up front; Dialer still checks every resolved address at connect time.
+func ValidateURL(raw string, allowPrivate bool) (*url.URL, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("URL scheme must be http or https")
+	}
+	if parsed.Hostname() == "" {
+		return nil, fmt.Errorf("URL must include a host")
+	}
+	if parsed.User != nil {
+		return nil, fmt.Errorf("URL must not include credentials")
+	}
+
+	if !allowPrivate {
+		host := strings.ToLower(parsed.Hostname())
+		if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+			return nil, ErrForbiddenAddress
+		}
+		if ip := net.ParseIP(host); ip != nil && !IsPublic(ip) {
+			return nil, ErrForbiddenAddress
+		}
+	}
+	return parsed, nil
+}
+
+// Dialer returns a dialer that refuses connections to non-public addresses.
+// The check runs after DNS resolution, so names that resolve to internal
+// addresses are caught too.
+func Dialer(timeout time.Duration, allowPrivate bool) *net.Dialer {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+	}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !IsPublic(ip) {
+				return fmt.Errorf("dial %s: %w", address, ErrForbiddenAddress)
+			}
+			return nil
+		}
+	}
+	return dialer
+}
@@ -0,0 +1,312 @@
+// Package redact replaces personal data in text with numbered placeholders
+// such as [EMAIL_1] and puts it back afterwards, so claims can be sent to
+// third-party models without the personal details in them.
+package redact
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Built-in detector kinds
+const (
+	KindEmail      = "email"
+	KindPhone      = "phone"
+	KindCard       = "card"
+	KindNationalID = "national_id"
+	KindAddress    = "address"
+)
+
+// Match is a span of personal data found in a text
+type Match struct {
+	Start int
+	End   int
+	Kind  string
+}
+
+// Detector finds one kind of personal data
+type Detector interface {
+	Detect(text string) []Match
+}
+
+// PatternDetector reports every match of Pattern that Valid, if set, accepts
+type PatternDetector struct {
+	Kind    string
+	Pattern *regexp.Regexp
+	Valid   func(match string) bool
+}
+
+func (d *PatternDetector) Detect(text string) []Match {
+	var matches []Match
+	for _, span := range d.Pattern.FindAllStringIndex(text, -1) {
+		if d.Valid == nil || d.Valid(text[span[0]:span[1]]) {
+			matches = append(matches, Match{Start: span[0], End: span[1], Kind: d.Kind})
+		}
+	}
+	return matches
+}
+
+// Builtin holds the built-in detectors by kind
+var Builtin = map[string]Detector{
+	KindEmail: &PatternDetector{
+		Kind:    KindEmail,
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	},
+	// International numbers, North American 3-3-4 numbers and national
+	// numbers with a trunk 0, so plain figures like 331 449 281 stay
+	KindPhone: &PatternDetector{
+		Kind: KindPhone,
+		Pattern: regexp.MustCompile(`\+\d{1,3}[\s.-]?(?:\(\d{1,4}\)[\s.-]?)?\d{1,4}(?:[\s.-]?\d{2,4}){1,4}\b` +
+			`|(?:\(\d{3}\)\s?|\b\d{3}[\s.-])\d{3}[\s.-]\d{4}\b` +
+			`|\b0\d{1,4}[\s.-]?\d{3,4}[\s.-]?\d{3,4}\b`),
+		Valid: func(match string) bool {
+			n := len(digits(match))
+			return n >= 7 && n <= 15
+		},
+	},
+	KindCard: &PatternDetector{
+		Kind:    KindCard,
+		Pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		Valid:   luhn,
+	},
+	// US social security and UK national insurance numbers
+	KindNationalID: &PatternDetector{
+		Kind:    KindNationalID,
+		Pattern: regexp.MustCompile(`\b(?:\d{3}-\d{2}-\d{4}|[A-CEGHJ-PR-TW-Z]{2} ?\d{2} ?\d{2} ?\d{2} ?[A-D])\b`),
+		Valid: func(match string) bool {
+			area := match[:3]
+			return !strings.Contains(match, "-") || (area != "000" && area != "666" && area[0] != '9')
+		},
+	},
+	// Street addresses written in English, like "221B Baker Street"
+	KindAddress: &PatternDetector{
+		Kind: KindAddress,
+		Pattern: regexp.MustCompile(`\b\d{1,5}[A-Za-z]?\s+(?:[A-Z][a-z]+\s+){1,3}` +
+			`(?:Street|St|Avenue|Ave|Road|Rd|Boulevard|Blvd|Lane|Ln|Drive|Dr|Court|Ct|Way|Place|Pl|Terrace|Square|Sq)\b\.?` +
+			`(?:,?\s+(?:Apt|Apartment|Suite|Unit|Flat)\.?\s*#?\w+)?`),
+	},
+}
+
+// Kinds returns the kinds of the built-in detectors, sorted
+func Kinds() []string {
+	kinds := make([]string, 0, len(Builtin))
+	for kind := range Builtin {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Redactor finds personal data with a set of detectors
+type Redactor struct {
+	detectors []Detector
+}
+
+// New creates a redactor running the given detectors
+func New(detectors ...Detector) *Redactor {
+	return &Redactor{detectors: detectors}
+}
+
+// NewBuiltin creates a redactor running the built-in detectors of the
+// given kinds
+func NewBuiltin(kinds []string) (*Redactor, error) {
+	detectors := make([]Detector, 0, len(kinds))
+	for _, kind := range kinds {
+		detector, ok := Builtin[kind]
+		if !ok {
+			return nil, fmt.Errorf("unknown PII detector %q", kind)
+		}
+		detectors = append(detectors, detector)
+	}
+	return New(detectors...), nil
+}
+
+// Session starts redacting related texts. Placeholders are numbered per
+// kind across the session, and the same value always gets the same one.
+func (r *Redactor) Session() *Session {
+	return &Session{
+		redactor:     r,
+		placeholders: make(map[string]string),
+		counts:       make(map[string]int),
+	}
+}
+
+// Session redacts texts and restores the placeholders in answers about them
+type Session struct {
+	redactor     *Redactor
+	placeholders map[string]string
+	counts       map[string]int
+	restore      []string
+}
+
+// Redact replaces the personal data in text with placeholders. Where
+// detectors overlap the earliest, then longest, match wins.
+func (s *Session) Redact(text string) string {
+	var matches []Match
+	for _, detector := range s.redactor.detectors {
+		matches = append(matches, detector.Detect(text)...)
+	}
+	if len(matches) == 0 {
+		return text
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		return matches[i].End > matches[j].End
+	})
+
+	var redacted strings.Builder
+	last := 0
+	for _, match := range matches {
+		if match.Start < last {
+			continue
+		}
+		redacted.WriteString(text[last:match.Start])
+		redacted.WriteString(s.placeholder(match.Kind, text[match.Start:match.End]))
+		last = match.End
+	}
+	redacted.WriteString(text[last:])
+	return redacted.String()
+}
+
+// Masked replaces credentials in URLs. Unlike placeholders it is never
+// restored.
+const Masked = "[REDACTED]"
+
+// secretParams are query parameters that carry credentials rather than
+// identify a page. Parameter names containing token, secret, password or
+// auth are treated as secret too.
+var secretParams = map[string]bool{
+	"key": true, "apikey": true, "api_key": true, "sig": true, "signature": true,
+	"code": true, "sid": true, "session": true, "sessionid": true, "pwd": true,
+}
+
+// RedactURL redacts a link: user info and the values of credential
+// parameters are masked, and personal data in the path, query values and
+// fragment replaced with placeholders. Query values are decoded first so
+// percent-encoded data is found too. Text that is not a URL is redacted
+// like any other.
+func (s *Session) RedactURL(link string) string {
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return s.Redact(link)
+	}
+
+	var redacted strings.Builder
+	redacted.WriteString(u.Scheme + "://")
+	if u.User != nil {
+		redacted.WriteString(Masked + "@")
+	}
+	redacted.WriteString(u.Host)
+	redacted.WriteString(s.redactEscaped(u.EscapedPath(), url.PathUnescape))
+	if u.RawQuery != "" {
+		redacted.WriteString("?")
+		for i, pair := range strings.Split(u.RawQuery, "&") {
+			if i > 0 {
+				redacted.WriteString("&")
+			}
+			name, value, found := strings.Cut(pair, "=")
+			if !found {
+				redacted.WriteString(s.redactEscaped(pair, url.QueryUnescape))
+				continue
+			}
+			redacted.WriteString(name + "=")
+			if secretParam(name) && value != "" {
+				redacted.WriteString(Masked)
+				continue
+			}
+			redacted.WriteString(s.redactEscaped(value, url.QueryUnescape))
+		}
+	}
+	if fragment := u.EscapedFragment(); fragment != "" {
+		redacted.WriteString("#" + s.redactEscaped(fragment, url.PathUnescape))
+	}
+	return redacted.String()
+}
+
+// redactEscaped redacts the decoded form of an escaped URL part, keeping the
+// part as it was when there is nothing to redact
+func (s *Session) redactEscaped(part string, unescape func(string) (string, error)) string {
+	decoded, err := unescape(part)
+	if err != nil {
+		return s.Redact(part)
+	}
+	if redacted := s.Redact(decoded); redacted != decoded {
+		return redacted
+	}
+	return part
+}
+
+func secretParam(name string) bool {
+	name, _ = url.QueryUnescape(name)
+	name = strings.ToLower(name)
+	if secretParams[name] {
+		return true
+	}
+	for _, word := range []string{"token", "secret", "password", "auth"} {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// Restore puts the redacted values back in place of their placeholders
+func (s *Session) Restore(text string) string {
+	if len(s.restore) == 0 {
+		return text
+	}
+	return strings.NewReplacer(s.restore...).Replace(text)
+}
+
+// Count returns how many distinct values have been redacted
+func (s *Session) Count() int {
+	return len(s.placeholders)
+}
+
+func (s *Session) placeholder(kind, value string) string {
+	key := kind + "\x00" + value
+	if placeholder, ok := s.placeholders[key]; ok {
+		return placeholder
+	}
+	s.counts[kind]++
+	placeholder := fmt.Sprintf("[%s_%d]", strings.ToUpper(kind), s.counts[kind])
+	s.placeholders[key] = placeholder
+	s.restore = append(s.restore, placeholder, value)
+	return placeholder
+}
+
+func digits(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// luhn reports whether a 13 to 19 digit number passes the Luhn checksum
+// used by payment cards
+func luhn(match string) bool {
+	number := digits(match)
+	if len(number) < 13 || len(number) > 19 {
+		return false
+	}
+	sum := 0
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if (len(number)-i)%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,1307 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"fact-check/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// The in-memory repositories keep copies of stored records so callers can't
+// mutate them without going through the repository. They are safe for
+// concurrent use and intended for tests and local development.
+
+type MemoryNewsRepository struct {
+	mutex sync.RWMutex
+	news  map[uuid.UUID]models.News
+	orgs  *MemoryOrganizationRepository
+}
+
+// NewMemoryNewsRepository creates the repository; orgs resolves organization
+// filters and may be nil when they are not used
+func NewMemoryNewsRepository(orgs *MemoryOrganizationRepository) *MemoryNewsRepository {
+	return &MemoryNewsRepository{news: make(map[uuid.UUID]models.News), orgs: orgs}
+}
+
+func (r *MemoryNewsRepository) Create(ctx context.Context, news *models.News) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.news[news.ID]; exists {
+		return fmt.Errorf("failed to insert news: duplicate id %s", news.ID)
+	}
+	r.news[news.ID] = *news
+	return nil
+}
+
+func (r *MemoryNewsRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.News, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	news, ok := r.news[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &news, nil
+}
+
+func (r *MemoryNewsRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.News, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var newsList []*models.News
+	for _, news := range r.news {
+		if news.UserID == userID {
+			news := news
+			newsList = append(newsList, &news)
+		}
+	}
+
+	sort.Slice(newsList, func(i, j int) bool {
+		return newsList[i].CreatedAt.After(newsList[j].CreatedAt)
+	})
+	return newsList, nil
+}
+
+func (r *MemoryNewsRepository) ListPublished(ctx context.Context, filter PublishedFilter) ([]*models.News, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	newsList := []*models.News{}
+	for _, news := range r.news {
+		if news.Status == "pending" || news.Moderation != models.ModerationAllowed ||
+			(filter.Status != "" && news.Status != filter.Status) ||
+			(filter.Topic != "" && (news.Topic == nil || *news.Topic != filter.Topic)) ||
+			(filter.NeedsReview && !news.NeedsReview) ||
+			(filter.Public && (!news.Published || news.NeedsReview)) ||
+			(filter.OrganizationID != nil && (r.orgs == nil || !r.orgs.isMember(*filter.OrganizationID, news.UserID))) {
+			continue
+		}
+		news := news
+		newsList = append(newsList, &news)
+	}
+
+	sort.Slice(newsList, func(i, j int) bool {
+		if !newsList[i].UpdatedAt.Equal(newsList[j].UpdatedAt) {
+			return newsList[i].UpdatedAt.After(newsList[j].UpdatedAt)
+		}
+		return newsList[i].ID.String() < newsList[j].ID.String()
+	})
+	if filter.Limit > 0 && len(newsList) > filter.Limit {
+		newsList = newsList[:filter.Limit]
+	}
+	return newsList, nil
+}
+
+// deleteByUser removes the user's news and returns the deleted IDs
+func (r *MemoryNewsRepository) deleteByUser(userID uuid.UUID) map[uuid.UUID]bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	deleted := make(map[uuid.UUID]bool)
+	for id, news := range r.news {
+		if news.UserID == userID {
+			delete(r.news, id)
+			deleted[id] = true
+		}
+	}
+	return deleted
+}
+
+func (r *MemoryNewsRepository) UpdateStatus(ctx context.Context, id uuid.UUID, verdict models.Verdict) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	news, ok := r.news[id]
+	if !ok {
+		return ErrNotFound
+	}
+	news.Status = verdict.Status
+	news.Explanation = &verdict.Explanation
+	news.PromptVersion = verdict.PromptVersion
+	news.ModelVerdicts = append([]models.ModelVerdict(nil), verdict.ModelVerdicts...)
+	news.NeedsReview = verdict.NeedsReview
+	news.Confidence = &verdict.Confidence
+	news.RawConfidence = &verdict.RawConfidence
+	news.ModelStatus = &verdict.ModelStatus
+	news.ReviewedBy = nil
+	news.ReviewedAt = nil
+	news.UpdatedAt = time.Now()
+	r.news[id] = news
+	return nil
+}
+
+func (r *MemoryNewsRepository) Resolve(ctx context.Context, id uuid.UUID, resolution models.Resolution) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	news, ok := r.news[id]
+	if !ok {
+		return ErrNotFound
+	}
+	news.Status = resolution.Status
+	news.Explanation = &resolution.Explanation
+	news.NeedsReview = false
+	news.ReviewedBy = &resolution.ReviewerID
+	news.ReviewedAt = &resolution.ReviewedAt
+	news.UpdatedAt = time.Now()
+	r.news[id] = news
+	return nil
+}
+
+func (r *MemoryNewsRepository) ListQuarantined(ctx context.Context, limit int) ([]*models.News, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	newsList := []*models.News{}
+	for _, news := range r.news {
+		if news.Moderation == models.ModerationQuarantined {
+			news := news
+			newsList = append(newsList, &news)
+		}
+	}
+
+	sort.Slice(newsList, func(i, j int) bool {
+		if !newsList[i].CreatedAt.Equal(newsList[j].CreatedAt) {
+			return newsList[i].CreatedAt.Before(newsList[j].CreatedAt)
+		}
+		return newsList[i].ID.String() < newsList[j].ID.String()
+	})
+	if limit > 0 && len(newsList) > limit {
+		newsList = newsList[:limit]
+	}
+	return newsList, nil
+}
+
+func (r *MemoryNewsRepository) Moderate(ctx context.Context, id uuid.UUID, decision models.ModerationDecision) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	news, ok := r.news[id]
+	if !ok {
+		return ErrNotFound
+	}
+	news.Moderation = decision.Moderation
+	if decision.Reason != "" {
+		news.ModerationReason = &decision.Reason
+	}
+	news.ModeratedBy = &decision.ModeratorID
+	news.ModeratedAt = &decision.ModeratedAt
+	news.UpdatedAt = time.Now()
+	r.news[id] = news
+	return nil
+}
+
+func (r *MemoryNewsRepository) Screen(ctx context.Context, id uuid.UUID, screening models.Screening) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	news, ok := r.news[id]
+	if !ok || news.Moderation != models.ModerationPending {
+		return ErrNotFound
+	}
+	news.Moderation = screening.Moderation
+	if screening.Category != "" {
+		news.ModerationCategory = &screening.Category
+	}
+	if screening.Reason != "" {
+		news.ModerationReason = &screening.Reason
+	}
+	news.UpdatedAt = time.Now()
+	r.news[id] = news
+	return nil
+}
+
+func (r *MemoryNewsRepository) SetPublished(ctx context.Context, id uuid.UUID, published bool) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	news, ok := r.news[id]
+	if !ok {
+		return ErrNotFound
+	}
+	news.Published = published
+	news.UpdatedAt = time.Now()
+	r.news[id] = news
+	return nil
+}
+
+type MemoryUserRepository struct {
+	mutex sync.RWMutex
+	users map[uuid.UUID]models.User
+}
+
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{users: make(map[uuid.UUID]models.User)}
+}
+
+func (r *MemoryUserRepository) Create(ctx context.Context, user *models.User) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, existing := range r.users {
+		if existing.ID == user.ID || existing.GoogleID == user.GoogleID || existing.Email == user.Email {
+			return fmt.Errorf("failed to create user: duplicate user")
+		}
+	}
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *MemoryUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &user, nil
+}
+
+func (r *MemoryUserRepository) GetByGoogleID(ctx context.Context, googleID string) (*models.User, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, user := range r.users {
+		if user.GoogleID == googleID {
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryUserRepository) UpdateProfile(ctx context.Context, user *models.User) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, ok := r.users[user.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	existing.Name = user.Name
+	existing.Picture = user.Picture
+	existing.Role = user.Role
+	existing.AdminByEmail = user.AdminByEmail
+	existing.UpdatedAt = time.Now()
+	r.users[user.ID] = existing
+
+	user.UpdatedAt = existing.UpdatedAt
+	return nil
+}
+
+func (r *MemoryUserRepository) ScheduleDeletion(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	user, ok := r.users[id]
+	if !ok || user.DeletedAt != nil {
+		return ErrNotFound
+	}
+	user.DeletionScheduledFor = at
+	r.users[id] = user
+	return nil
+}
+
+func (r *MemoryUserRepository) SetLocale(ctx context.Context, id uuid.UUID, locale *string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	user, ok := r.users[id]
+	if !ok || user.DeletedAt != nil {
+		return ErrNotFound
+	}
+	user.Locale = locale
+	user.UpdatedAt = time.Now()
+	r.users[id] = user
+	return nil
+}
+
+func (r *MemoryUserRepository) ListDueDeletions(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var due []models.User
+	for _, user := range r.users {
+		if user.DeletedAt == nil && user.DeletionScheduledFor != nil && !user.DeletionScheduledFor.After(now) {
+			due = append(due, user)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].DeletionScheduledFor.Before(*due[j].DeletionScheduledFor)
+	})
+
+	var ids []uuid.UUID
+	for _, user := range due {
+		if limit > 0 && len(ids) == limit {
+			break
+		}
+		ids = append(ids, user.ID)
+	}
+	return ids, nil
+}
+
+func (r *MemoryUserRepository) anonymize(id uuid.UUID, now time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	user.GoogleID = "deleted:" + id.String()
+	user.Email = id.String() + "@deleted.invalid"
+	user.Name = "Deleted user"
+	user.Picture = ""
+	user.Role = models.RoleUser
+	user.AdminByEmail = false
+	user.Locale = nil
+	user.DeletionScheduledFor = nil
+	user.DeletedAt = &now
+	user.UpdatedAt = now
+	r.users[id] = user
+	return nil
+}
+
+func (r *MemoryUserRepository) role(id uuid.UUID) string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.users[id].Role
+}
+
+type MemoryUsageRepository struct {
+	mutex sync.RWMutex
+	usage []models.LLMUsage
+	users *MemoryUserRepository
+}
+
+// NewMemoryUsageRepository needs the user repository to resolve role filters
+func NewMemoryUsageRepository(users *MemoryUserRepository) *MemoryUsageRepository {
+	return &MemoryUsageRepository{users: users}
+}
+
+func (r *MemoryUsageRepository) Create(ctx context.Context, usage *models.LLMUsage) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.usage = append(r.usage, *usage)
+	return nil
+}
+
+func (r *MemoryUsageRepository) Spend(ctx context.Context, filter UsageFilter) (float64, error) {
+	var spent float64
+	for _, usage := range r.matching(filter) {
+		spent += usage.CostUSD
+	}
+	return spent, nil
+}
+
+func (r *MemoryUsageRepository) Summarize(ctx context.Context, filter UsageFilter, groupBy UsageGroup) ([]models.UsageSummary, error) {
+	groups := make(map[string]*models.UsageSummary)
+	for _, usage := range r.matching(filter) {
+		key := usage.Model
+		if groupBy == GroupByUser {
+			key = usage.UserID.String()
+		}
+		summary, ok := groups[key]
+		if !ok {
+			summary = &models.UsageSummary{Key: key}
+			groups[key] = summary
+		}
+		summary.Calls++
+		summary.PromptTokens += usage.PromptTokens
+		summary.CompletionTokens += usage.CompletionTokens
+		summary.TotalTokens += usage.TotalTokens
+		summary.CostUSD += usage.CostUSD
+	}
+
+	summaries := []models.UsageSummary{}
+	for _, summary := range groups {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].CostUSD > summaries[j].CostUSD
+	})
+	return summaries, nil
+}
+
+func (r *MemoryUsageRepository) List(ctx context.Context, filter UsageFilter) ([]models.LLMUsage, error) {
+	usage := r.matching(filter)
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].CreatedAt.Before(usage[j].CreatedAt)
+	})
+	if usage == nil {
+		usage = []models.LLMUsage{}
+	}
+	return usage, nil
+}
+
+// unlinkNews clears the news reference of usage rows for deleted news
+func (r *MemoryUsageRepository) unlinkNews(newsIDs map[uuid.UUID]bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i := range r.usage {
+		if r.usage[i].NewsID != nil && newsIDs[*r.usage[i].NewsID] {
+			r.usage[i].NewsID = nil
+		}
+	}
+}
+
+func (r *MemoryUsageRepository) matching(filter UsageFilter) []models.LLMUsage {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var matched []models.LLMUsage
+	for _, usage := range r.usage {
+		if filter.UserID != nil && usage.UserID != *filter.UserID {
+			continue
+		}
+		if filter.Role != "" && r.users.role(usage.UserID) != filter.Role {
+			continue
+		}
+		if !filter.From.IsZero() && usage.CreatedAt.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !usage.CreatedAt.Before(filter.To) {
+			continue
+		}
+		matched = append(matched, usage)
+	}
+	return matched
+}
+
+type MemoryOrganizationRepository struct {
+	mutex   sync.RWMutex
+	orgs    map[uuid.UUID]models.Organization
+	members map[uuid.UUID]map[uuid.UUID]models.OrganizationMember
+}
+
+func NewMemoryOrganizationRepository() *MemoryOrganizationRepository {
+	return &MemoryOrganizationRepository{
+		orgs:    make(map[uuid.UUID]models.Organization),
+		members: make(map[uuid.UUID]map[uuid.UUID]models.OrganizationMember),
+	}
+}
+
+func (r *MemoryOrganizationRepository) Create(ctx context.Context, org *models.Organization, owner uuid.UUID) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.orgs[org.ID]; exists {
+		return fmt.Errorf("failed to create organization: duplicate id %s", org.ID)
+	}
+	r.orgs[org.ID] = *org
+	r.members[org.ID] = map[uuid.UUID]models.OrganizationMember{
+		owner: {OrganizationID: org.ID, UserID: owner, Role: models.OrgRoleOwner, CreatedAt: org.CreatedAt},
+	}
+	return nil
+}
+
+func (r *MemoryOrganizationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	org, ok := r.orgs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &org, nil
+}
+
+func (r *MemoryOrganizationRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Organization, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var orgs []*models.Organization
+	for id, members := range r.members {
+		if _, ok := members[userID]; ok {
+			org := r.orgs[id]
+			orgs = append(orgs, &org)
+		}
+	}
+	sort.Slice(orgs, func(i, j int) bool {
+		return orgs[i].CreatedAt.Before(orgs[j].CreatedAt)
+	})
+	return orgs, nil
+}
+
+func (r *MemoryOrganizationRepository) SetPolicy(ctx context.Context, id uuid.UUID, policy models.OrganizationPolicy) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	org, ok := r.orgs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if policy.RedactPII != nil {
+		redact := *policy.RedactPII
+		policy.RedactPII = &redact
+	}
+	org.Policy = policy
+	r.orgs[id] = org
+	return nil
+}
+
+func (r *MemoryOrganizationRepository) AddMember(ctx context.Context, member *models.OrganizationMember) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	members, ok := r.members[member.OrganizationID]
+	if !ok {
+		return fmt.Errorf("failed to add organization member: unknown organization %s", member.OrganizationID)
+	}
+	if existing, ok := members[member.UserID]; ok {
+		existing.Role = member.Role
+		members[member.UserID] = existing
+		return nil
+	}
+	members[member.UserID] = *member
+	return nil
+}
+
+func (r *MemoryOrganizationRepository) GetMember(ctx context.Context, orgID, userID uuid.UUID) (*models.OrganizationMember, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	member, ok := r.members[orgID][userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &member, nil
+}
+
+// removeUser drops the user's memberships, deletes organizations left
+// without members and makes the longest-standing member the owner of those
+// left without one
+func (r *MemoryOrganizationRepository) removeUser(userID uuid.UUID) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for orgID, members := range r.members {
+		if _, ok := members[userID]; !ok {
+			continue
+		}
+		delete(members, userID)
+		if len(members) == 0 {
+			delete(r.members, orgID)
+			delete(r.orgs, orgID)
+			continue
+		}
+
+		var heir *models.OrganizationMember
+		for _, member := range members {
+			if member.Role == models.OrgRoleOwner {
+				heir = nil
+				break
+			}
+			if heir == nil || member.CreatedAt.Before(heir.CreatedAt) ||
+				(member.CreatedAt.Equal(heir.CreatedAt) && member.UserID.String() < heir.UserID.String()) {
+				member := member
+				heir = &member
+			}
+		}
+		if heir != nil {
+			heir.Role = models.OrgRoleOwner
+			members[heir.UserID] = *heir
+		}
+	}
+}
+
+func (r *MemoryOrganizationRepository) exists(orgID uuid.UUID) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	_, ok := r.orgs[orgID]
+	return ok
+}
+
+func (r *MemoryOrganizationRepository) isMember(orgID, userID uuid.UUID) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	_, ok := r.members[orgID][userID]
+	return ok
+}
+
+type MemoryWebhookRepository struct {
+	mutex      sync.RWMutex
+	orgs       *MemoryOrganizationRepository
+	endpoints  map[uuid.UUID]models.WebhookEndpoint
+	deliveries map[uuid.UUID]models.WebhookDelivery
+}
+
+// NewMemoryWebhookRepository resolves organization membership through orgs
+func NewMemoryWebhookRepository(orgs *MemoryOrganizationRepository) *MemoryWebhookRepository {
+	return &MemoryWebhookRepository{
+		orgs:       orgs,
+		endpoints:  make(map[uuid.UUID]models.WebhookEndpoint),
+		deliveries: make(map[uuid.UUID]models.WebhookDelivery),
+	}
+}
+
+func (r *MemoryWebhookRepository) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.endpoints[endpoint.ID]; exists {
+		return fmt.Errorf("failed to create webhook endpoint: duplicate id %s", endpoint.ID)
+	}
+	stored := *endpoint
+	stored.Events = append([]string(nil), endpoint.Events...)
+	r.endpoints[endpoint.ID] = stored
+	return nil
+}
+
+func (r *MemoryWebhookRepository) GetEndpoint(ctx context.Context, id uuid.UUID) (*models.WebhookEndpoint, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	endpoint, ok := r.endpoints[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	endpoint.Events = append([]string(nil), endpoint.Events...)
+	return &endpoint, nil
+}
+
+func (r *MemoryWebhookRepository) ListEndpoints(ctx context.Context, owner WebhookOwner) ([]*models.WebhookEndpoint, error) {
+	return r.filterEndpoints(func(endpoint models.WebhookEndpoint) bool {
+		if owner.OrganizationID != nil {
+			return endpoint.OrganizationID != nil && *endpoint.OrganizationID == *owner.OrganizationID
+		}
+		return owner.UserID != nil && endpoint.UserID != nil && *endpoint.UserID == *owner.UserID
+	}), nil
+}
+
+func (r *MemoryWebhookRepository) ListSubscribed(ctx context.Context, userID uuid.UUID, event string) ([]*models.WebhookEndpoint, error) {
+	return r.filterEndpoints(func(endpoint models.WebhookEndpoint) bool {
+		if !endpoint.Active || !containsString(endpoint.Events, event) {
+			return false
+		}
+		if endpoint.UserID != nil {
+			return *endpoint.UserID == userID
+		}
+		return endpoint.OrganizationID != nil && r.orgs.isMember(*endpoint.OrganizationID, userID)
+	}), nil
+}
+
+func (r *MemoryWebhookRepository) filterEndpoints(match func(models.WebhookEndpoint) bool) []*models.WebhookEndpoint {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var endpoints []*models.WebhookEndpoint
+	for _, endpoint := range r.endpoints {
+		if match(endpoint) {
+			endpoint := endpoint
+			endpoint.Events = append([]string(nil), endpoint.Events...)
+			endpoints = append(endpoints, &endpoint)
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].CreatedAt.Before(endpoints[j].CreatedAt)
+	})
+	return endpoints
+}
+
+func (r *MemoryWebhookRepository) DeleteEndpoint(ctx context.Context, id uuid.UUID) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.endpoints[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.endpoints, id)
+	for deliveryID, delivery := range r.deliveries {
+		if delivery.EndpointID == id {
+			delete(r.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+// deleteOrphaned removes endpoints owned by the user or by organizations
+// that no longer exist
+func (r *MemoryWebhookRepository) deleteOrphaned(userID uuid.UUID) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for id, endpoint := range r.endpoints {
+		owned := endpoint.UserID != nil && *endpoint.UserID == userID
+		orphaned := endpoint.OrganizationID != nil && !r.orgs.exists(*endpoint.OrganizationID)
+		if !owned && !orphaned {
+			continue
+		}
+		delete(r.endpoints, id)
+		for deliveryID, delivery := range r.deliveries {
+			if delivery.EndpointID == id {
+				delete(r.deliveries, deliveryID)
+			}
+		}
+	}
+}
+
+func (r *MemoryWebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.endpoints[delivery.EndpointID]; !ok {
+		return fmt.Errorf("failed to create webhook delivery: unknown endpoint %s", delivery.EndpointID)
+	}
+	r.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (r *MemoryWebhookRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	delivery, ok := r.deliveries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &delivery, nil
+}
+
+func (r *MemoryWebhookRepository) ListDeliveries(ctx context.Context, endpointID uuid.UUID, limit int) ([]*models.WebhookDelivery, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var deliveries []*models.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.EndpointID == endpointID {
+			delivery := delivery
+			deliveries = append(deliveries, &delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+	if limit > 0 && len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (r *MemoryWebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.WebhookDelivery, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var due []*models.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == models.DeliveryPending && delivery.NextAttemptAt != nil && !delivery.NextAttemptAt.After(now) {
+			delivery := delivery
+			due = append(due, &delivery)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(*due[j].NextAttemptAt)
+	})
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+
+	leased := now.Add(lease)
+	for _, delivery := range due {
+		delivery.NextAttemptAt = &leased
+		r.deliveries[delivery.ID] = *delivery
+	}
+	return due, nil
+}
+
+func (r *MemoryWebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.deliveries[delivery.ID]; !ok {
+		return ErrNotFound
+	}
+	r.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+type MemoryVerificationJobRepository struct {
+	mutex   sync.RWMutex
+	news    *MemoryNewsRepository
+	batches map[uuid.UUID]models.Batch
+	jobs    map[uuid.UUID]models.VerificationJob
+}
+
+// NewMemoryVerificationJobRepository stores batch news items in news
+func NewMemoryVerificationJobRepository(news *MemoryNewsRepository) *MemoryVerificationJobRepository {
+	return &MemoryVerificationJobRepository{
+		news:    news,
+		batches: make(map[uuid.UUID]models.Batch),
+		jobs:    make(map[uuid.UUID]models.VerificationJob),
+	}
+}
+
+func (r *MemoryVerificationJobRepository) CreateBatch(ctx context.Context, batch *models.Batch, news []*models.News, jobs []*models.VerificationJob) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.batches[batch.ID]; exists {
+		return fmt.Errorf("failed to create batch: duplicate id %s", batch.ID)
+	}
+	for _, item := range news {
+		if err := r.news.Create(ctx, item); err != nil {
+			return err
+		}
+	}
+	r.batches[batch.ID] = *batch
+	for _, job := range jobs {
+		r.jobs[job.ID] = *job
+	}
+	return nil
+}
+
+func (r *MemoryVerificationJobRepository) GetBatch(ctx context.Context, id uuid.UUID) (*models.Batch, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	batch, ok := r.batches[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &batch, nil
+}
+
+func (r *MemoryVerificationJobRepository) ListBatchJobs(ctx context.Context, batchID uuid.UUID) ([]*models.VerificationJob, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var jobs []*models.VerificationJob
+	for _, job := range r.jobs {
+		if job.BatchID != nil && *job.BatchID == batchID {
+			job := job
+			jobs = append(jobs, &job)
+		}
+	}
+	sortJobs(jobs)
+	return jobs, nil
+}
+
+func (r *MemoryVerificationJobRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.VerificationJob, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var claimable []*models.VerificationJob
+	for _, job := range r.jobs {
+		stale := job.Status == models.JobRunning && job.StartedAt != nil && job.StartedAt.Before(now.Add(-lease))
+		if job.Status == models.JobQueued || stale {
+			job := job
+			claimable = append(claimable, &job)
+		}
+	}
+	sortJobs(claimable)
+	if limit > 0 && len(claimable) > limit {
+		claimable = claimable[:limit]
+	}
+
+	for _, job := range claimable {
+		started := now
+		job.Status = models.JobRunning
+		job.StartedAt = &started
+		job.Attempts++
+		r.jobs[job.ID] = *job
+	}
+	return claimable, nil
+}
+
+func (r *MemoryVerificationJobRepository) UpdateJob(ctx context.Context, job *models.VerificationJob) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.jobs[job.ID]; !ok {
+		return ErrNotFound
+	}
+	r.jobs[job.ID] = *job
+	return nil
+}
+
+func (r *MemoryVerificationJobRepository) Backlog(ctx context.Context) (*models.QueueBacklog, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	backlog := &models.QueueBacklog{}
+	for _, job := range r.jobs {
+		switch job.Status {
+		case models.JobQueued:
+			backlog.Queued++
+			if backlog.OldestQueuedAt == nil || job.CreatedAt.Before(*backlog.OldestQueuedAt) {
+				createdAt := job.CreatedAt
+				backlog.OldestQueuedAt = &createdAt
+			}
+		case models.JobRunning:
+			backlog.Running++
+		}
+	}
+	return backlog, nil
+}
+
+// deleteByUser removes the user's batches and jobs
+func (r *MemoryVerificationJobRepository) deleteByUser(userID uuid.UUID) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for id, batch := range r.batches {
+		if batch.UserID == userID {
+			delete(r.batches, id)
+		}
+	}
+	for id, job := range r.jobs {
+		if job.UserID == userID {
+			delete(r.jobs, id)
+		}
+	}
+}
+
+func sortJobs(jobs []*models.VerificationJob) {
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+		}
+		return jobs[i].ID.String() < jobs[j].ID.String()
+	})
+}
+
+// MemoryAccountEraser erases a user across the other in-memory repositories
+type MemoryAccountEraser struct {
+	Users    *MemoryUserRepository
+	News     *MemoryNewsRepository
+	Usage    *MemoryUsageRepository
+	Jobs     *MemoryVerificationJobRepository
+	Orgs     *MemoryOrganizationRepository
+	Webhooks *MemoryWebhookRepository
+	// Translations is optional
+	Translations *MemoryTranslationRepository
+}
+
+func (r *MemoryAccountEraser) EraseUser(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	if _, err := r.Users.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	deleted := r.News.deleteByUser(userID)
+	r.Usage.unlinkNews(deleted)
+	if r.Translations != nil {
+		r.Translations.deleteByNews(deleted)
+	}
+	r.Jobs.deleteByUser(userID)
+	r.Orgs.removeUser(userID)
+	r.Webhooks.deleteOrphaned(userID)
+	return r.Users.anonymize(userID, now)
+}
+
+type MemoryAuditRepository struct {
+	mutex   sync.RWMutex
+	entries []models.AuditEntry
+}
+
+func NewMemoryAuditRepository() *MemoryAuditRepository {
+	return &MemoryAuditRepository{}
+}
+
+func (r *MemoryAuditRepository) Record(ctx context.Context, entry *models.AuditEntry) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *MemoryAuditRepository) List(ctx context.Context, filter AuditFilter) ([]*models.AuditEntry, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entries := []*models.AuditEntry{}
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		entry := r.entries[i]
+		if filter.SubjectID != nil && entry.SubjectID != *filter.SubjectID {
+			continue
+		}
+		if filter.Action != "" && entry.Action != filter.Action {
+			continue
+		}
+		entries = append(entries, &entry)
+		if filter.Limit > 0 && len(entries) == filter.Limit {
+			break
+		}
+	}
+	return entries, nil
+}
+
+type MemorySourceRepository struct {
+	mutex   sync.RWMutex
+	sources map[string]models.Source
+}
+
+func NewMemorySourceRepository() *MemorySourceRepository {
+	return &MemorySourceRepository{sources: make(map[string]models.Source)}
+}
+
+func (r *MemorySourceRepository) Upsert(ctx context.Context, source *models.Source) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if existing, ok := r.sources[source.Domain]; ok {
+		source.ID = existing.ID
+		source.CreatedAt = existing.CreatedAt
+	}
+	r.sources[source.Domain] = *source
+	return nil
+}
+
+func (r *MemorySourceRepository) GetByDomain(ctx context.Context, domain string) (*models.Source, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	source, ok := r.sources[domain]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &source, nil
+}
+
+func (r *MemorySourceRepository) FindByDomains(ctx context.Context, domains []string) ([]*models.Source, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	sources := []*models.Source{}
+	for _, domain := range domains {
+		if source, ok := r.sources[domain]; ok {
+			sources = append(sources, &source)
+		}
+	}
+	return sources, nil
+}
+
+func (r *MemorySourceRepository) List(ctx context.Context) ([]*models.Source, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	sources := []*models.Source{}
+	for _, source := range r.sources {
+		source := source
+		sources = append(sources, &source)
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Domain < sources[j].Domain })
+	return sources, nil
+}
+
+func (r *MemorySourceRepository) Delete(ctx context.Context, domain string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.sources[domain]; !ok {
+		return ErrNotFound
+	}
+	delete(r.sources, domain)
+	return nil
+}
+
+type translationKey struct {
+	newsID uuid.UUID
+	locale string
+}
+
+type MemoryTranslationRepository struct {
+	mutex        sync.RWMutex
+	translations map[translationKey]models.NewsTranslation
+}
+
+func NewMemoryTranslationRepository() *MemoryTranslationRepository {
+	return &MemoryTranslationRepository{translations: make(map[translationKey]models.NewsTranslation)}
+}
+
+func (r *MemoryTranslationRepository) Upsert(ctx context.Context, translation *models.NewsTranslation) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := translationKey{newsID: translation.NewsID, locale: translation.Locale}
+	if existing, ok := r.translations[key]; ok {
+		translation.ID = existing.ID
+		translation.CreatedAt = existing.CreatedAt
+	}
+	r.translations[key] = *translation
+	return nil
+}
+
+func (r *MemoryTranslationRepository) Get(ctx context.Context, newsID uuid.UUID, locale string) (*models.NewsTranslation, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	translation, ok := r.translations[translationKey{newsID: newsID, locale: locale}]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &translation, nil
+}
+
+func (r *MemoryTranslationRepository) deleteByNews(newsIDs map[uuid.UUID]bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for key := range r.translations {
+		if newsIDs[key.newsID] {
+			delete(r.translations, key)
+		}
+	}
+}
+
+type MemoryPromptRepository struct {
+	mutex   sync.RWMutex
+	prompts []models.PromptVersion
+}
+
+func NewMemoryPromptRepository() *MemoryPromptRepository {
+	return &MemoryPromptRepository{}
+}
+
+func (r *MemoryPromptRepository) Create(ctx context.Context, prompt *models.PromptVersion) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	prompt.Version = len(r.prompts) + 1
+	r.prompts = append(r.prompts, *prompt)
+	return nil
+}
+
+func (r *MemoryPromptRepository) Get(ctx context.Context, version int) (*models.PromptVersion, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if version < 1 || version > len(r.prompts) {
+		return nil, ErrNotFound
+	}
+	prompt := r.prompts[version-1]
+	return &prompt, nil
+}
+
+func (r *MemoryPromptRepository) List(ctx context.Context) ([]*models.PromptVersion, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	prompts := []*models.PromptVersion{}
+	for i := len(r.prompts) - 1; i >= 0; i-- {
+		prompt := r.prompts[i]
+		prompts = append(prompts, &prompt)
+	}
+	return prompts, nil
+}
+
+func (r *MemoryPromptRepository) ListActive(ctx context.Context) ([]*models.PromptVersion, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	prompts := []*models.PromptVersion{}
+	for _, prompt := range r.prompts {
+		if prompt.Weight > 0 {
+			prompt := prompt
+			prompts = append(prompts, &prompt)
+		}
+	}
+	return prompts, nil
+}
+
+func (r *MemoryPromptRepository) SetWeight(ctx context.Context, version, weight int) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if version < 1 || version > len(r.prompts) {
+		return ErrNotFound
+	}
+	r.prompts[version-1].Weight = weight
+	r.prompts[version-1].UpdatedAt = time.Now()
+	return nil
+}
+
+type MemoryCalibrationRepository struct {
+	mutex        sync.RWMutex
+	calibrations []models.Calibration
+	news         *MemoryNewsRepository
+}
+
+// NewMemoryCalibrationRepository creates the repository; reviewed outcomes
+// are read from news
+func NewMemoryCalibrationRepository(news *MemoryNewsRepository) *MemoryCalibrationRepository {
+	return &MemoryCalibrationRepository{news: news}
+}
+
+func (r *MemoryCalibrationRepository) Create(ctx context.Context, calibration *models.Calibration) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	calibration.ID = uuid.New()
+	stored := *calibration
+	stored.Params = append([]byte(nil), calibration.Params...)
+	r.calibrations = append(r.calibrations, stored)
+	return nil
+}
+
+func (r *MemoryCalibrationRepository) Latest(ctx context.Context) (*models.Calibration, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if len(r.calibrations) == 0 {
+		return nil, ErrNotFound
+	}
+	calibration := r.calibrations[len(r.calibrations)-1]
+	calibration.Params = append([]byte(nil), calibration.Params...)
+	return &calibration, nil
+}
+
+func (r *MemoryCalibrationRepository) ListOutcomes(ctx context.Context, limit int) ([]models.CalibrationOutcome, error) {
+	r.news.mutex.RLock()
+	defer r.news.mutex.RUnlock()
+
+	var reviewed []models.News
+	for _, news := range r.news.news {
+		if news.ReviewedAt != nil && news.RawConfidence != nil && news.ModelStatus != nil &&
+			(*news.ModelStatus == "true" || *news.ModelStatus == "false") {
+			reviewed = append(reviewed, news)
+		}
+	}
+	sort.Slice(reviewed, func(i, j int) bool {
+		return reviewed[i].ReviewedAt.After(*reviewed[j].ReviewedAt)
+	})
+	if limit > 0 && len(reviewed) > limit {
+		reviewed = reviewed[:limit]
+	}
+
+	outcomes := make([]models.CalibrationOutcome, len(reviewed))
+	for i, news := range reviewed {
+		outcomes[i] = models.CalibrationOutcome{Confidence: *news.RawConfidence, Correct: *news.ModelStatus == news.Status}
+	}
+	return outcomes, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	_ NewsRepository            = (*MemoryNewsRepository)(nil)
+	_ UserRepository            = (*MemoryUserRepository)(nil)
+	_ UsageRepository           = (*MemoryUsageRepository)(nil)
+	_ OrganizationRepository    = (*MemoryOrganizationRepository)(nil)
+	_ WebhookRepository         = (*MemoryWebhookRepository)(nil)
+	_ VerificationJobRepository = (*MemoryVerificationJobRepository)(nil)
+	_ AccountEraser             = (*MemoryAccountEraser)(nil)
+	_ AuditRepository           = (*MemoryAuditRepository)(nil)
+	_ SourceRepository          = (*MemorySourceRepository)(nil)
+	_ TranslationRepository     = (*MemoryTranslationRepository)(nil)
+	_ PromptRepository          = (*MemoryPromptRepository)(nil)
+	_ CalibrationRepository     = (*MemoryCalibrationRepository)(nil)
+)
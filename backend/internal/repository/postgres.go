@@ -0,0 +1,579 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"fact-check/internal/models"
+	"fact-check/internal/tracing"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type PostgresNewsRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresNewsRepository(db *sql.DB) *PostgresNewsRepository {
+	return &PostgresNewsRepository{db: db}
+}
+
+func (r *PostgresNewsRepository) Create(ctx context.Context, news *models.News) error {
+	ctx, span := startSpan(ctx, "INSERT", "news")
+	defer span.End()
+
+	query := `INSERT INTO news (id, user_id, content, link, canonical_link, fetched_host, photo_url, topic, language, status,
+			  moderation, moderation_category, moderation_reason, published, batch_id, created_at, updated_at) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`
+
+	_, err := r.db.ExecContext(ctx, query, news.ID, news.UserID, news.Content, news.Link, news.CanonicalLink, news.FetchedHost,
+		news.PhotoURL, news.Topic, news.Language, news.Status, news.Moderation, news.ModerationCategory, news.ModerationReason,
+		news.Published, news.BatchID, news.CreatedAt, news.UpdatedAt)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to insert news: %w", err))
+	}
+	return nil
+}
+
+// newsColumns are the columns scanNews reads, in order
+const newsColumns = `id, user_id, content, link, canonical_link, fetched_host, photo_url, topic, language, status, explanation, prompt_version,
+			  model_verdicts, needs_review, confidence, raw_confidence, model_status, reviewed_by, reviewed_at,
+			  moderation, moderation_category, moderation_reason, moderated_by, moderated_at, published, batch_id, created_at, updated_at`
+
+// scanNews reads a row selected with newsColumns
+func scanNews(row interface{ Scan(...interface{}) error }) (*models.News, error) {
+	var news models.News
+	var modelVerdicts []byte
+	err := row.Scan(
+		&news.ID, &news.UserID, &news.Content, &news.Link, &news.CanonicalLink, &news.FetchedHost, &news.PhotoURL, &news.Topic, &news.Language,
+		&news.Status, &news.Explanation, &news.PromptVersion, &modelVerdicts, &news.NeedsReview,
+		&news.Confidence, &news.RawConfidence, &news.ModelStatus, &news.ReviewedBy, &news.ReviewedAt,
+		&news.Moderation, &news.ModerationCategory, &news.ModerationReason, &news.ModeratedBy, &news.ModeratedAt,
+		&news.Published, &news.BatchID, &news.CreatedAt, &news.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(modelVerdicts) > 0 {
+		if err := json.Unmarshal(modelVerdicts, &news.ModelVerdicts); err != nil {
+			return nil, fmt.Errorf("failed to decode model verdicts: %w", err)
+		}
+	}
+	return &news, nil
+}
+
+func (r *PostgresNewsRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.News, error) {
+	ctx, span := startSpan(ctx, "SELECT", "news")
+	defer span.End()
+
+	query := `SELECT ` + newsColumns + ` FROM news WHERE id = $1`
+
+	news, err := scanNews(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, spanError(span, fmt.Errorf("failed to get news: %w", err))
+	}
+
+	return news, nil
+}
+
+func (r *PostgresNewsRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.News, error) {
+	ctx, span := startSpan(ctx, "SELECT", "news")
+	defer span.End()
+
+	query := `SELECT ` + newsColumns + ` FROM news WHERE user_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to query user news: %w", err))
+	}
+	defer rows.Close()
+
+	var newsList []*models.News
+	for rows.Next() {
+		news, err := scanNews(rows)
+		if err != nil {
+			return nil, spanError(span, fmt.Errorf("failed to scan news row: %w", err))
+		}
+		newsList = append(newsList, news)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, spanError(span, fmt.Errorf("error iterating over news rows: %w", err))
+	}
+
+	return newsList, nil
+}
+
+func (r *PostgresNewsRepository) UpdateStatus(ctx context.Context, id uuid.UUID, verdict models.Verdict) error {
+	ctx, span := startSpan(ctx, "UPDATE", "news")
+	defer span.End()
+
+	var modelVerdicts []byte
+	if len(verdict.ModelVerdicts) > 0 {
+		var err error
+		if modelVerdicts, err = json.Marshal(verdict.ModelVerdicts); err != nil {
+			return spanError(span, fmt.Errorf("failed to encode model verdicts: %w", err))
+		}
+	}
+
+	query := `UPDATE news SET status = $1, explanation = $2, prompt_version = $3, model_verdicts = $4, needs_review = $5,
+			  confidence = $6, raw_confidence = $7, model_status = $8, reviewed_by = NULL, reviewed_at = NULL, updated_at = CURRENT_TIMESTAMP 
+			  WHERE id = $9`
+
+	result, err := r.db.ExecContext(ctx, query, verdict.Status, verdict.Explanation, verdict.PromptVersion, modelVerdicts, verdict.NeedsReview,
+		verdict.Confidence, verdict.RawConfidence, verdict.ModelStatus, id)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to update news status: %w", err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to get rows affected: %w", err))
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNewsRepository) Resolve(ctx context.Context, id uuid.UUID, resolution models.Resolution) error {
+	ctx, span := startSpan(ctx, "UPDATE", "news")
+	defer span.End()
+
+	query := `UPDATE news SET status = $1, explanation = $2, needs_review = FALSE, reviewed_by = $3, reviewed_at = $4, updated_at = CURRENT_TIMESTAMP 
+			  WHERE id = $5`
+
+	result, err := r.db.ExecContext(ctx, query, resolution.Status, resolution.Explanation, resolution.ReviewerID, resolution.ReviewedAt, id)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to resolve news review: %w", err))
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNewsRepository) ListPublished(ctx context.Context, filter PublishedFilter) ([]*models.News, error) {
+	ctx, span := startSpan(ctx, "SELECT", "news")
+	defer span.End()
+
+	conditions := []string{"status <> 'pending'", "moderation = 'allowed'"}
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+	if filter.Topic != "" {
+		add("topic = $%d", filter.Topic)
+	}
+	if filter.OrganizationID != nil {
+		add("user_id IN (SELECT user_id FROM organization_members WHERE organization_id = $%d)", *filter.OrganizationID)
+	}
+	if filter.NeedsReview {
+		conditions = append(conditions, "needs_review")
+	}
+	if filter.Public {
+		conditions = append(conditions, "published", "NOT needs_review")
+	}
+	args = append(args, filter.Limit)
+
+	query := fmt.Sprintf(`SELECT `+newsColumns+` 
+			  FROM news WHERE %s ORDER BY updated_at DESC, id LIMIT NULLIF($%d, 0)`, strings.Join(conditions, " AND "), len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to query published news: %w", err))
+	}
+	defer rows.Close()
+
+	newsList := []*models.News{}
+	for rows.Next() {
+		news, err := scanNews(rows)
+		if err != nil {
+			return nil, spanError(span, fmt.Errorf("failed to scan news row: %w", err))
+		}
+		newsList = append(newsList, news)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, spanError(span, fmt.Errorf("error iterating over news rows: %w", err))
+	}
+
+	return newsList, nil
+}
+
+func (r *PostgresNewsRepository) ListQuarantined(ctx context.Context, limit int) ([]*models.News, error) {
+	ctx, span := startSpan(ctx, "SELECT", "news")
+	defer span.End()
+
+	query := `SELECT ` + newsColumns + ` FROM news WHERE moderation = 'quarantined' ORDER BY created_at, id LIMIT NULLIF($1, 0)`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to query quarantined news: %w", err))
+	}
+	defer rows.Close()
+
+	newsList := []*models.News{}
+	for rows.Next() {
+		news, err := scanNews(rows)
+		if err != nil {
+			return nil, spanError(span, fmt.Errorf("failed to scan news row: %w", err))
+		}
+		newsList = append(newsList, news)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, spanError(span, fmt.Errorf("error iterating over news rows: %w", err))
+	}
+
+	return newsList, nil
+}
+
+func (r *PostgresNewsRepository) Moderate(ctx context.Context, id uuid.UUID, decision models.ModerationDecision) error {
+	ctx, span := startSpan(ctx, "UPDATE", "news")
+	defer span.End()
+
+	query := `UPDATE news SET moderation = $1, moderation_reason = COALESCE(NULLIF($2, ''), moderation_reason), moderated_by = $3, moderated_at = $4,
+			  updated_at = CURRENT_TIMESTAMP WHERE id = $5`
+
+	result, err := r.db.ExecContext(ctx, query, decision.Moderation, decision.Reason, decision.ModeratorID, decision.ModeratedAt, id)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to moderate news: %w", err))
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNewsRepository) Screen(ctx context.Context, id uuid.UUID, screening models.Screening) error {
+	ctx, span := startSpan(ctx, "UPDATE", "news")
+	defer span.End()
+
+	query := `UPDATE news SET moderation = $1, moderation_category = NULLIF($2, ''), moderation_reason = NULLIF($3, ''),
+			  updated_at = CURRENT_TIMESTAMP WHERE id = $4 AND moderation = 'pending'`
+
+	result, err := r.db.ExecContext(ctx, query, screening.Moderation, screening.Category, screening.Reason, id)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to screen news: %w", err))
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNewsRepository) SetPublished(ctx context.Context, id uuid.UUID, published bool) error {
+	ctx, span := startSpan(ctx, "UPDATE", "news")
+	defer span.End()
+
+	query := `UPDATE news SET published = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, published, id)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to publish news: %w", err))
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type PostgresUserRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresUserRepository(db *sql.DB) *PostgresUserRepository {
+	return &PostgresUserRepository{db: db}
+}
+
+func (r *PostgresUserRepository) Create(ctx context.Context, user *models.User) error {
+	ctx, span := startSpan(ctx, "INSERT", "users")
+	defer span.End()
+
+	query := `INSERT INTO users (id, google_id, email, name, picture, role, admin_by_email, created_at, updated_at) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := r.db.ExecContext(ctx, query, user.ID, user.GoogleID, user.Email, user.Name,
+		user.Picture, user.Role, user.AdminByEmail, user.CreatedAt, user.UpdatedAt)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to create user: %w", err))
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return r.getOne(ctx, "id = $1", id)
+}
+
+func (r *PostgresUserRepository) GetByGoogleID(ctx context.Context, googleID string) (*models.User, error) {
+	return r.getOne(ctx, "google_id = $1", googleID)
+}
+
+func (r *PostgresUserRepository) getOne(ctx context.Context, where string, arg interface{}) (*models.User, error) {
+	ctx, span := startSpan(ctx, "SELECT", "users")
+	defer span.End()
+
+	var user models.User
+	query := `SELECT id, google_id, email, name, picture, role, admin_by_email, locale, created_at, updated_at, deletion_scheduled_for, deleted_at 
+			  FROM users WHERE ` + where
+
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(
+		&user.ID, &user.GoogleID, &user.Email, &user.Name,
+		&user.Picture, &user.Role, &user.AdminByEmail, &user.Locale, &user.CreatedAt, &user.UpdatedAt,
+		&user.DeletionScheduledFor, &user.DeletedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, spanError(span, fmt.Errorf("failed to get user: %w", err))
+	}
+
+	return &user, nil
+}
+
+func (r *PostgresUserRepository) UpdateProfile(ctx context.Context, user *models.User) error {
+	ctx, span := startSpan(ctx, "UPDATE", "users")
+	defer span.End()
+
+	query := `UPDATE users SET name = $1, picture = $2, role = $3, admin_by_email = $4, updated_at = CURRENT_TIMESTAMP 
+			  WHERE id = $5 RETURNING updated_at`
+
+	err := r.db.QueryRowContext(ctx, query, user.Name, user.Picture, user.Role, user.AdminByEmail, user.ID).Scan(&user.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		return spanError(span, fmt.Errorf("failed to update user: %w", err))
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) ScheduleDeletion(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	ctx, span := startSpan(ctx, "UPDATE", "users")
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, `UPDATE users SET deletion_scheduled_for = $1 WHERE id = $2 AND deleted_at IS NULL`, at, id)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to schedule user deletion: %w", err))
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) SetLocale(ctx context.Context, id uuid.UUID, locale *string) error {
+	ctx, span := startSpan(ctx, "UPDATE", "users")
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, `UPDATE users SET locale = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 AND deleted_at IS NULL`, locale, id)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to set user locale: %w", err))
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) ListDueDeletions(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	ctx, span := startSpan(ctx, "SELECT", "users")
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id FROM users
+		WHERE deletion_scheduled_for <= $1 AND deleted_at IS NULL
+		ORDER BY deletion_scheduled_for LIMIT $2`, now, limit)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to query due deletions: %w", err))
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, spanError(span, fmt.Errorf("failed to scan user id: %w", err))
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, spanError(span, fmt.Errorf("error iterating over user rows: %w", err))
+	}
+	return ids, nil
+}
+
+type PostgresUsageRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresUsageRepository(db *sql.DB) *PostgresUsageRepository {
+	return &PostgresUsageRepository{db: db}
+}
+
+func (r *PostgresUsageRepository) Create(ctx context.Context, usage *models.LLMUsage) error {
+	ctx, span := startSpan(ctx, "INSERT", "llm_usage")
+	defer span.End()
+
+	query := `INSERT INTO llm_usage (id, user_id, news_id, model, prompt_tokens, completion_tokens, total_tokens, cost_usd, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := r.db.ExecContext(ctx, query, usage.ID, usage.UserID, usage.NewsID, usage.Model, usage.PromptTokens,
+		usage.CompletionTokens, usage.TotalTokens, usage.CostUSD, usage.CreatedAt)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to insert LLM usage: %w", err))
+	}
+	return nil
+}
+
+func (r *PostgresUsageRepository) Spend(ctx context.Context, filter UsageFilter) (float64, error) {
+	ctx, span := startSpan(ctx, "SELECT", "llm_usage")
+	defer span.End()
+
+	where, args := usageWhere(filter)
+	query := `SELECT COALESCE(SUM(cost_usd), 0) FROM llm_usage WHERE ` + where
+
+	var spent float64
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&spent); err != nil {
+		return 0, spanError(span, fmt.Errorf("failed to sum LLM spend: %w", err))
+	}
+	return spent, nil
+}
+
+func (r *PostgresUsageRepository) Summarize(ctx context.Context, filter UsageFilter, groupBy UsageGroup) ([]models.UsageSummary, error) {
+	ctx, span := startSpan(ctx, "SELECT", "llm_usage")
+	defer span.End()
+
+	column := "model"
+	if groupBy == GroupByUser {
+		column = "user_id::text"
+	}
+
+	where, args := usageWhere(filter)
+	query := `SELECT ` + column + `, COUNT(*), COALESCE(SUM(prompt_tokens), 0), COALESCE(SUM(completion_tokens), 0),
+			  COALESCE(SUM(total_tokens), 0), COALESCE(SUM(cost_usd), 0)
+			  FROM llm_usage WHERE ` + where + `
+			  GROUP BY 1 ORDER BY 6 DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to query LLM usage: %w", err))
+	}
+	defer rows.Close()
+
+	summaries := []models.UsageSummary{}
+	for rows.Next() {
+		var summary models.UsageSummary
+		err := rows.Scan(&summary.Key, &summary.Calls, &summary.PromptTokens,
+			&summary.CompletionTokens, &summary.TotalTokens, &summary.CostUSD)
+		if err != nil {
+			return nil, spanError(span, fmt.Errorf("failed to scan usage row: %w", err))
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, spanError(span, fmt.Errorf("error iterating over usage rows: %w", err))
+	}
+
+	return summaries, nil
+}
+
+func (r *PostgresUsageRepository) List(ctx context.Context, filter UsageFilter) ([]models.LLMUsage, error) {
+	ctx, span := startSpan(ctx, "SELECT", "llm_usage")
+	defer span.End()
+
+	where, args := usageWhere(filter)
+	query := `SELECT id, user_id, news_id, model, prompt_tokens, completion_tokens, total_tokens, cost_usd, created_at
+			  FROM llm_usage WHERE ` + where + ` ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to query LLM usage: %w", err))
+	}
+	defer rows.Close()
+
+	usage := []models.LLMUsage{}
+	for rows.Next() {
+		var row models.LLMUsage
+		err := rows.Scan(&row.ID, &row.UserID, &row.NewsID, &row.Model, &row.PromptTokens,
+			&row.CompletionTokens, &row.TotalTokens, &row.CostUSD, &row.CreatedAt)
+		if err != nil {
+			return nil, spanError(span, fmt.Errorf("failed to scan usage row: %w", err))
+		}
+		usage = append(usage, row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, spanError(span, fmt.Errorf("error iterating over usage rows: %w", err))
+	}
+
+	return usage, nil
+}
+
+func usageWhere(filter UsageFilter) (string, []interface{}) {
+	conditions := []string{"TRUE"}
+	var args []interface{}
+
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.UserID != nil {
+		add("user_id = $%d", *filter.UserID)
+	}
+	if filter.Role != "" {
+		add("user_id IN (SELECT id FROM users WHERE role = $%d)", filter.Role)
+	}
+	if !filter.From.IsZero() {
+		add("created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("created_at < $%d", filter.To)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// startSpan starts a client span for a single Postgres statement
+func startSpan(ctx context.Context, operation, table string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, operation+" "+table,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperation(operation),
+			semconv.DBSQLTable(table),
+		),
+	)
+}
+
+// spanError marks the span as failed and returns err unchanged
+func spanError(span trace.Span, err error) error {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	return err
+}
+
+var (
+	_ NewsRepository  = (*PostgresNewsRepository)(nil)
+	_ UserRepository  = (*PostgresUserRepository)(nil)
+	_ UsageRepository = (*PostgresUsageRepository)(nil)
+)
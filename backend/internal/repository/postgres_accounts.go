@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"fact-check/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type PostgresAccountEraser struct {
+	db *sql.DB
+}
+
+func NewPostgresAccountEraser(db *sql.DB) *PostgresAccountEraser {
+	return &PostgresAccountEraser{db: db}
+}
+
+func (r *PostgresAccountEraser) EraseUser(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	ctx, span := startSpan(ctx, "DELETE", "users")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to begin transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	// News deletion cascades to verification jobs and unlinks usage rows
+	statements := []string{
+		`DELETE FROM news WHERE user_id = $1`,
+		`DELETE FROM batches WHERE user_id = $1`,
+		`DELETE FROM webhook_endpoints WHERE user_id = $1`,
+		// Orgs the user owned alone pass to their longest-standing member
+		`UPDATE organization_members m SET role = 'owner'
+		FROM (
+			SELECT DISTINCT ON (organization_id) organization_id, user_id
+			FROM organization_members
+			WHERE user_id <> $1
+			AND organization_id IN (
+				SELECT organization_id FROM organization_members WHERE user_id = $1 AND role = 'owner'
+			)
+			AND organization_id NOT IN (
+				SELECT organization_id FROM organization_members WHERE user_id <> $1 AND role = 'owner'
+			)
+			ORDER BY organization_id, created_at, user_id
+		) heir
+		WHERE m.organization_id = heir.organization_id AND m.user_id = heir.user_id`,
+		`WITH left_orgs AS (
+			DELETE FROM organization_members WHERE user_id = $1 RETURNING organization_id
+		)
+		DELETE FROM organizations o WHERE o.id IN (SELECT organization_id FROM left_orgs)
+		AND NOT EXISTS (
+			SELECT 1 FROM organization_members m WHERE m.organization_id = o.id AND m.user_id <> $1
+		)`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement, userID); err != nil {
+			return spanError(span, fmt.Errorf("failed to erase user data: %w", err))
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE users SET google_id = 'deleted:' || id::text, email = id::text || '@deleted.invalid',
+			name = 'Deleted user', picture = '', role = 'user', admin_by_email = FALSE, locale = NULL,
+			deletion_scheduled_for = NULL, deleted_at = $2, updated_at = $2
+		WHERE id = $1`, userID, now)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to anonymize user: %w", err))
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return spanError(span, fmt.Errorf("failed to commit user erasure: %w", err))
+	}
+	return nil
+}
+
+type PostgresAuditRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAuditRepository(db *sql.DB) *PostgresAuditRepository {
+	return &PostgresAuditRepository{db: db}
+}
+
+func (r *PostgresAuditRepository) Record(ctx context.Context, entry *models.AuditEntry) error {
+	ctx, span := startSpan(ctx, "INSERT", "audit_log")
+	defer span.End()
+
+	var details []byte
+	if entry.Details != nil {
+		var err error
+		if details, err = json.Marshal(entry.Details); err != nil {
+			return fmt.Errorf("failed to encode audit details: %w", err)
+		}
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO audit_log (id, action, actor_id, subject_id, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		entry.ID, entry.Action, entry.ActorID, entry.SubjectID, details, entry.CreatedAt)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to record audit entry: %w", err))
+	}
+	return nil
+}
+
+func (r *PostgresAuditRepository) List(ctx context.Context, filter AuditFilter) ([]*models.AuditEntry, error) {
+	ctx, span := startSpan(ctx, "SELECT", "audit_log")
+	defer span.End()
+
+	query := `SELECT id, action, actor_id, subject_id, details, created_at FROM audit_log
+			  WHERE ($1::uuid IS NULL OR subject_id = $1) AND ($2 = '' OR action = $2)
+			  ORDER BY created_at DESC LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, filter.SubjectID, filter.Action, filter.Limit)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to query audit log: %w", err))
+	}
+	defer rows.Close()
+
+	entries := []*models.AuditEntry{}
+	for rows.Next() {
+		var entry models.AuditEntry
+		var details []byte
+		if err := rows.Scan(&entry.ID, &entry.Action, &entry.ActorID, &entry.SubjectID, &details, &entry.CreatedAt); err != nil {
+			return nil, spanError(span, fmt.Errorf("failed to scan audit row: %w", err))
+		}
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &entry.Details); err != nil {
+				return nil, spanError(span, fmt.Errorf("failed to decode audit details: %w", err))
+			}
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, spanError(span, fmt.Errorf("error iterating over audit rows: %w", err))
+	}
+	return entries, nil
+}
+
+var (
+	_ AccountEraser   = (*PostgresAccountEraser)(nil)
+	_ AuditRepository = (*PostgresAuditRepository)(nil)
+)
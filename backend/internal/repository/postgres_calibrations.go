@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"fact-check/internal/models"
+)
+
+type PostgresCalibrationRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresCalibrationRepository(db *sql.DB) *PostgresCalibrationRepository {
+	return &PostgresCalibrationRepository{db: db}
+}
+
+func (r *PostgresCalibrationRepository) Create(ctx context.Context, calibration *models.Calibration) error {
+	ctx, span := startSpan(ctx, "INSERT", "calibrations")
+	defer span.End()
+
+	query := `INSERT INTO calibrations (method, params, samples, brier_before, brier_after, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6)
+			  RETURNING id`
+
+	err := r.db.QueryRowContext(ctx, query, calibration.Method, []byte(calibration.Params), calibration.Samples,
+		calibration.BrierBefore, calibration.BrierAfter, calibration.CreatedAt).Scan(&calibration.ID)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to insert calibration: %w", err))
+	}
+	return nil
+}
+
+func (r *PostgresCalibrationRepository) Latest(ctx context.Context) (*models.Calibration, error) {
+	ctx, span := startSpan(ctx, "SELECT", "calibrations")
+	defer span.End()
+
+	query := `SELECT id, method, params, samples, brier_before, brier_after, created_at
+			  FROM calibrations ORDER BY created_at DESC LIMIT 1`
+
+	var calibration models.Calibration
+	var params []byte
+	err := r.db.QueryRowContext(ctx, query).Scan(&calibration.ID, &calibration.Method, &params, &calibration.Samples,
+		&calibration.BrierBefore, &calibration.BrierAfter, &calibration.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, spanError(span, fmt.Errorf("failed to get calibration: %w", err))
+	}
+	calibration.Params = params
+	return &calibration, nil
+}
+
+func (r *PostgresCalibrationRepository) ListOutcomes(ctx context.Context, limit int) ([]models.CalibrationOutcome, error) {
+	ctx, span := startSpan(ctx, "SELECT", "news")
+	defer span.End()
+
+	query := `SELECT raw_confidence, model_status = status FROM news
+			  WHERE reviewed_at IS NOT NULL AND raw_confidence IS NOT NULL AND model_status IN ('true', 'false')
+			  ORDER BY reviewed_at DESC LIMIT NULLIF($1, 0)`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to query calibration outcomes: %w", err))
+	}
+	defer rows.Close()
+
+	var outcomes []models.CalibrationOutcome
+	for rows.Next() {
+		var outcome models.CalibrationOutcome
+		if err := rows.Scan(&outcome.Confidence, &outcome.Correct); err != nil {
+			return nil, spanError(span, fmt.Errorf("failed to scan calibration outcome row: %w", err))
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, spanError(span, fmt.Errorf("error iterating over calibration outcome rows: %w", err))
+	}
+	return outcomes, nil
+}
+
+var _ CalibrationRepository = (*PostgresCalibrationRepository)(nil)
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"fact-check/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type PostgresVerificationJobRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresVerificationJobRepository(db *sql.DB) *PostgresVerificationJobRepository {
+	return &PostgresVerificationJobRepository{db: db}
+}
+
+const jobColumns = `id, news_id, user_id, batch_id, status, attempts, verdict, error, created_at, started_at, finished_at`
+
+func scanJob(row interface{ Scan(...interface{}) error }) (*models.VerificationJob, error) {
+	var job models.VerificationJob
+	err := row.Scan(&job.ID, &job.NewsID, &job.UserID, &job.BatchID, &job.Status, &job.Attempts,
+		&job.Verdict, &job.Error, &job.CreatedAt, &job.StartedAt, &job.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *PostgresVerificationJobRepository) CreateBatch(ctx context.Context, batch *models.Batch, news []*models.News, jobs []*models.VerificationJob) error {
+	ctx, span := startSpan(ctx, "INSERT", "batches")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to begin transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO batches (id, user_id, total, created_at) VALUES ($1, $2, $3, $4)`,
+		batch.ID, batch.UserID, batch.Total, batch.CreatedAt); err != nil {
+		return spanError(span, fmt.Errorf("failed to create batch: %w", err))
+	}
+
+	insertNews, err := tx.PrepareContext(ctx, `
+		INSERT INTO news (id, user_id, content, link, canonical_link, fetched_host, photo_url, topic, language, status,
+			moderation, moderation_category, moderation_reason, published, batch_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to prepare news insert: %w", err))
+	}
+	defer insertNews.Close()
+	for _, item := range news {
+		if _, err := insertNews.ExecContext(ctx, item.ID, item.UserID, item.Content, item.Link, item.CanonicalLink, item.FetchedHost, item.PhotoURL,
+			item.Topic, item.Language, item.Status, item.Moderation, item.ModerationCategory, item.ModerationReason,
+			item.Published, item.BatchID, item.CreatedAt, item.UpdatedAt); err != nil {
+			return spanError(span, fmt.Errorf("failed to insert news: %w", err))
+		}
+	}
+
+	insertJob, err := tx.PrepareContext(ctx, `
+		INSERT INTO verification_jobs (id, news_id, user_id, batch_id, status, attempts, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to prepare job insert: %w", err))
+	}
+	defer insertJob.Close()
+	for _, job := range jobs {
+		if _, err := insertJob.ExecContext(ctx, job.ID, job.NewsID, job.UserID, job.BatchID,
+			job.Status, job.Attempts, job.CreatedAt); err != nil {
+			return spanError(span, fmt.Errorf("failed to insert verification job: %w", err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return spanError(span, fmt.Errorf("failed to commit batch: %w", err))
+	}
+	return nil
+}
+
+func (r *PostgresVerificationJobRepository) GetBatch(ctx context.Context, id uuid.UUID) (*models.Batch, error) {
+	ctx, span := startSpan(ctx, "SELECT", "batches")
+	defer span.End()
+
+	var batch models.Batch
+	err := r.db.QueryRowContext(ctx, `SELECT id, user_id, total, created_at FROM batches WHERE id = $1`, id).
+		Scan(&batch.ID, &batch.UserID, &batch.Total, &batch.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, spanError(span, fmt.Errorf("failed to get batch: %w", err))
+	}
+	return &batch, nil
+}
+
+func (r *PostgresVerificationJobRepository) ListBatchJobs(ctx context.Context, batchID uuid.UUID) ([]*models.VerificationJob, error) {
+	ctx, span := startSpan(ctx, "SELECT", "verification_jobs")
+	defer span.End()
+
+	jobs, err := r.queryJobs(ctx, `SELECT `+jobColumns+` FROM verification_jobs WHERE batch_id = $1 ORDER BY created_at, id`, batchID)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+	return jobs, nil
+}
+
+func (r *PostgresVerificationJobRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.VerificationJob, error) {
+	ctx, span := startSpan(ctx, "UPDATE", "verification_jobs")
+	defer span.End()
+
+	jobs, err := r.queryJobs(ctx, `
+		UPDATE verification_jobs SET status = 'running', started_at = $1, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM verification_jobs
+			WHERE status = 'queued' OR (status = 'running' AND started_at < $2)
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+jobColumns, now, now.Add(-lease), limit)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+	return jobs, nil
+}
+
+func (r *PostgresVerificationJobRepository) Backlog(ctx context.Context) (*models.QueueBacklog, error) {
+	ctx, span := startSpan(ctx, "SELECT", "verification_jobs")
+	defer span.End()
+
+	var backlog models.QueueBacklog
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FILTER (WHERE status = 'queued'), COUNT(*) FILTER (WHERE status = 'running'),
+			MIN(created_at) FILTER (WHERE status = 'queued')
+		FROM verification_jobs WHERE status IN ('queued', 'running')`).Scan(&backlog.Queued, &backlog.Running, &backlog.OldestQueuedAt)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to count verification jobs: %w", err))
+	}
+	return &backlog, nil
+}
+
+func (r *PostgresVerificationJobRepository) queryJobs(ctx context.Context, query string, args ...interface{}) ([]*models.VerificationJob, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query verification jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*models.VerificationJob
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan verification job row: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over verification job rows: %w", err)
+	}
+	return jobs, nil
+}
+
+func (r *PostgresVerificationJobRepository) UpdateJob(ctx context.Context, job *models.VerificationJob) error {
+	ctx, span := startSpan(ctx, "UPDATE", "verification_jobs")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE verification_jobs
+		SET status = $2, attempts = $3, verdict = $4, error = $5, started_at = $6, finished_at = $7
+		WHERE id = $1`,
+		job.ID, job.Status, job.Attempts, job.Verdict, job.Error, job.StartedAt, job.FinishedAt)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to update verification job: %w", err))
+	}
+	return nil
+}
+
+var _ VerificationJobRepository = (*PostgresVerificationJobRepository)(nil)
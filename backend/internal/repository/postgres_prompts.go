@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"fact-check/internal/models"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const promptColumns = `version, description, system_prompt, template, weight, created_by, created_at, updated_at`
+
+type PostgresPromptRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPromptRepository(db *sql.DB) *PostgresPromptRepository {
+	return &PostgresPromptRepository{db: db}
+}
+
+func (r *PostgresPromptRepository) Create(ctx context.Context, prompt *models.PromptVersion) error {
+	ctx, span := startSpan(ctx, "INSERT", "prompt_versions")
+	defer span.End()
+
+	query := `INSERT INTO prompt_versions (description, system_prompt, template, weight, created_by, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)
+			  RETURNING version`
+
+	err := r.db.QueryRowContext(ctx, query, prompt.Description, prompt.System, prompt.Template, prompt.Weight,
+		prompt.CreatedBy, prompt.CreatedAt, prompt.UpdatedAt).Scan(&prompt.Version)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to insert prompt version: %w", err))
+	}
+	return nil
+}
+
+func (r *PostgresPromptRepository) Get(ctx context.Context, version int) (*models.PromptVersion, error) {
+	ctx, span := startSpan(ctx, "SELECT", "prompt_versions")
+	defer span.End()
+
+	prompt, err := scanPrompt(r.db.QueryRowContext(ctx, `SELECT `+promptColumns+` FROM prompt_versions WHERE version = $1`, version))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, spanError(span, fmt.Errorf("failed to get prompt version: %w", err))
+	}
+	return prompt, nil
+}
+
+func (r *PostgresPromptRepository) List(ctx context.Context) ([]*models.PromptVersion, error) {
+	ctx, span := startSpan(ctx, "SELECT", "prompt_versions")
+	defer span.End()
+
+	return r.query(ctx, span, `SELECT `+promptColumns+` FROM prompt_versions ORDER BY version DESC`)
+}
+
+func (r *PostgresPromptRepository) ListActive(ctx context.Context) ([]*models.PromptVersion, error) {
+	ctx, span := startSpan(ctx, "SELECT", "prompt_versions")
+	defer span.End()
+
+	return r.query(ctx, span, `SELECT `+promptColumns+` FROM prompt_versions WHERE weight > 0 ORDER BY version`)
+}
+
+func (r *PostgresPromptRepository) SetWeight(ctx context.Context, version, weight int) error {
+	ctx, span := startSpan(ctx, "UPDATE", "prompt_versions")
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, `UPDATE prompt_versions SET weight = $1, updated_at = CURRENT_TIMESTAMP WHERE version = $2`, weight, version)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to set prompt weight: %w", err))
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresPromptRepository) query(ctx context.Context, span trace.Span, query string, args ...interface{}) ([]*models.PromptVersion, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to query prompt versions: %w", err))
+	}
+	defer rows.Close()
+
+	prompts := []*models.PromptVersion{}
+	for rows.Next() {
+		prompt, err := scanPrompt(rows)
+		if err != nil {
+			return nil, spanError(span, fmt.Errorf("failed to scan prompt version row: %w", err))
+		}
+		prompts = append(prompts, prompt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, spanError(span, fmt.Errorf("error iterating over prompt version rows: %w", err))
+	}
+	return prompts, nil
+}
+
+func scanPrompt(row interface{ Scan(...interface{}) error }) (*models.PromptVersion, error) {
+	var prompt models.PromptVersion
+	err := row.Scan(&prompt.Version, &prompt.Description, &prompt.System, &prompt.Template, &prompt.Weight,
+		&prompt.CreatedBy, &prompt.CreatedAt, &prompt.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &prompt, nil
+}
+
+var _ PromptRepository = (*PostgresPromptRepository)(nil)
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"fact-check/internal/models"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const sourceColumns = `id, domain, name, credibility, ownership, bias_notes, satire, created_at, updated_at`
+
+type PostgresSourceRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSourceRepository(db *sql.DB) *PostgresSourceRepository {
+	return &PostgresSourceRepository{db: db}
+}
+
+func (r *PostgresSourceRepository) Upsert(ctx context.Context, source *models.Source) error {
+	ctx, span := startSpan(ctx, "INSERT", "sources")
+	defer span.End()
+
+	query := `INSERT INTO sources (id, domain, name, credibility, ownership, bias_notes, satire, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			  ON CONFLICT (domain) DO UPDATE SET name = EXCLUDED.name, credibility = EXCLUDED.credibility,
+			  ownership = EXCLUDED.ownership, bias_notes = EXCLUDED.bias_notes, satire = EXCLUDED.satire,
+			  updated_at = EXCLUDED.updated_at
+			  RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query, source.ID, source.Domain, source.Name, source.Credibility,
+		source.Ownership, source.BiasNotes, source.Satire, source.CreatedAt, source.UpdatedAt).Scan(&source.ID, &source.CreatedAt)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to upsert source: %w", err))
+	}
+	return nil
+}
+
+func (r *PostgresSourceRepository) GetByDomain(ctx context.Context, domain string) (*models.Source, error) {
+	ctx, span := startSpan(ctx, "SELECT", "sources")
+	defer span.End()
+
+	source, err := scanSource(r.db.QueryRowContext(ctx, `SELECT `+sourceColumns+` FROM sources WHERE domain = $1`, domain))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, spanError(span, fmt.Errorf("failed to get source: %w", err))
+	}
+	return source, nil
+}
+
+func (r *PostgresSourceRepository) FindByDomains(ctx context.Context, domains []string) ([]*models.Source, error) {
+	ctx, span := startSpan(ctx, "SELECT", "sources")
+	defer span.End()
+
+	return r.query(ctx, span, `SELECT `+sourceColumns+` FROM sources WHERE domain = ANY($1)`, pq.Array(domains))
+}
+
+func (r *PostgresSourceRepository) List(ctx context.Context) ([]*models.Source, error) {
+	ctx, span := startSpan(ctx, "SELECT", "sources")
+	defer span.End()
+
+	return r.query(ctx, span, `SELECT `+sourceColumns+` FROM sources ORDER BY domain`)
+}
+
+func (r *PostgresSourceRepository) Delete(ctx context.Context, domain string) error {
+	ctx, span := startSpan(ctx, "DELETE", "sources")
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM sources WHERE domain = $1`, domain)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to delete source: %w", err))
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to get rows affected: %w", err))
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresSourceRepository) query(ctx context.Context, span trace.Span, query string, args ...interface{}) ([]*models.Source, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to query sources: %w", err))
+	}
+	defer rows.Close()
+
+	sources := []*models.Source{}
+	for rows.Next() {
+		source, err := scanSource(rows)
+		if err != nil {
+			return nil, spanError(span, fmt.Errorf("failed to scan source row: %w", err))
+		}
+		sources = append(sources, source)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, spanError(span, fmt.Errorf("error iterating over source rows: %w", err))
+	}
+	return sources, nil
+}
+
+func scanSource(row interface{ Scan(...interface{}) error }) (*models.Source, error) {
+	var source models.Source
+	err := row.Scan(&source.ID, &source.Domain, &source.Name, &source.Credibility, &source.Ownership,
+		&source.BiasNotes, &source.Satire, &source.CreatedAt, &source.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &source, nil
+}
+
+var _ SourceRepository = (*PostgresSourceRepository)(nil)
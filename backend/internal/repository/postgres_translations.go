@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"fact-check/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type PostgresTranslationRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTranslationRepository(db *sql.DB) *PostgresTranslationRepository {
+	return &PostgresTranslationRepository{db: db}
+}
+
+func (r *PostgresTranslationRepository) Upsert(ctx context.Context, translation *models.NewsTranslation) error {
+	ctx, span := startSpan(ctx, "INSERT", "news_translations")
+	defer span.End()
+
+	query := `INSERT INTO news_translations (id, news_id, locale, content, explanation, model, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			  ON CONFLICT (news_id, locale) DO UPDATE SET content = EXCLUDED.content,
+			  explanation = EXCLUDED.explanation, model = EXCLUDED.model, updated_at = EXCLUDED.updated_at
+			  RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query, translation.ID, translation.NewsID, translation.Locale, translation.Content,
+		translation.Explanation, translation.Model, translation.CreatedAt, translation.UpdatedAt).Scan(&translation.ID, &translation.CreatedAt)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to upsert translation: %w", err))
+	}
+	return nil
+}
+
+func (r *PostgresTranslationRepository) Get(ctx context.Context, newsID uuid.UUID, locale string) (*models.NewsTranslation, error) {
+	ctx, span := startSpan(ctx, "SELECT", "news_translations")
+	defer span.End()
+
+	query := `SELECT id, news_id, locale, content, explanation, model, created_at, updated_at
+			  FROM news_translations WHERE news_id = $1 AND locale = $2`
+
+	var translation models.NewsTranslation
+	err := r.db.QueryRowContext(ctx, query, newsID, locale).Scan(
+		&translation.ID, &translation.NewsID, &translation.Locale, &translation.Content,
+		&translation.Explanation, &translation.Model, &translation.CreatedAt, &translation.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, spanError(span, fmt.Errorf("failed to get translation: %w", err))
+	}
+	return &translation, nil
+}
+
+var _ TranslationRepository = (*PostgresTranslationRepository)(nil)
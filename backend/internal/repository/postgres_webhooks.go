@@ -0,0 +1,410 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"fact-check/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type PostgresOrganizationRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresOrganizationRepository(db *sql.DB) *PostgresOrganizationRepository {
+	return &PostgresOrganizationRepository{db: db}
+}
+
+func (r *PostgresOrganizationRepository) Create(ctx context.Context, org *models.Organization, owner uuid.UUID) error {
+	ctx, span := startSpan(ctx, "INSERT", "organizations")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to begin transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO organizations (id, name, created_at) VALUES ($1, $2, $3)`,
+		org.ID, org.Name, org.CreatedAt); err != nil {
+		return spanError(span, fmt.Errorf("failed to create organization: %w", err))
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role, created_at)
+		VALUES ($1, $2, $3, $4)`,
+		org.ID, owner, models.OrgRoleOwner, org.CreatedAt); err != nil {
+		return spanError(span, fmt.Errorf("failed to add organization owner: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return spanError(span, fmt.Errorf("failed to commit organization: %w", err))
+	}
+	return nil
+}
+
+func (r *PostgresOrganizationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
+	ctx, span := startSpan(ctx, "SELECT", "organizations")
+	defer span.End()
+
+	var org models.Organization
+	err := r.db.QueryRowContext(ctx, `SELECT id, name, redact_pii, created_at FROM organizations WHERE id = $1`, id).
+		Scan(&org.ID, &org.Name, &org.Policy.RedactPII, &org.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, spanError(span, fmt.Errorf("failed to get organization: %w", err))
+	}
+	return &org, nil
+}
+
+func (r *PostgresOrganizationRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Organization, error) {
+	ctx, span := startSpan(ctx, "SELECT", "organizations")
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT o.id, o.name, o.redact_pii, o.created_at
+		FROM organizations o
+		JOIN organization_members m ON m.organization_id = o.id
+		WHERE m.user_id = $1
+		ORDER BY o.created_at`, userID)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to query organizations: %w", err))
+	}
+	defer rows.Close()
+
+	var orgs []*models.Organization
+	for rows.Next() {
+		var org models.Organization
+		if err := rows.Scan(&org.ID, &org.Name, &org.Policy.RedactPII, &org.CreatedAt); err != nil {
+			return nil, spanError(span, fmt.Errorf("failed to scan organization row: %w", err))
+		}
+		orgs = append(orgs, &org)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, spanError(span, fmt.Errorf("error iterating over organization rows: %w", err))
+	}
+	return orgs, nil
+}
+
+func (r *PostgresOrganizationRepository) AddMember(ctx context.Context, member *models.OrganizationMember) error {
+	ctx, span := startSpan(ctx, "INSERT", "organization_members")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (organization_id, user_id) DO UPDATE SET role = EXCLUDED.role`,
+		member.OrganizationID, member.UserID, member.Role, member.CreatedAt)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to add organization member: %w", err))
+	}
+	return nil
+}
+
+func (r *PostgresOrganizationRepository) GetMember(ctx context.Context, orgID, userID uuid.UUID) (*models.OrganizationMember, error) {
+	ctx, span := startSpan(ctx, "SELECT", "organization_members")
+	defer span.End()
+
+	var member models.OrganizationMember
+	err := r.db.QueryRowContext(ctx, `
+		SELECT organization_id, user_id, role, created_at
+		FROM organization_members WHERE organization_id = $1 AND user_id = $2`, orgID, userID).
+		Scan(&member.OrganizationID, &member.UserID, &member.Role, &member.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, spanError(span, fmt.Errorf("failed to get organization member: %w", err))
+	}
+	return &member, nil
+}
+
+func (r *PostgresOrganizationRepository) SetPolicy(ctx context.Context, id uuid.UUID, policy models.OrganizationPolicy) error {
+	ctx, span := startSpan(ctx, "UPDATE", "organizations")
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, `UPDATE organizations SET redact_pii = $1 WHERE id = $2`, policy.RedactPII, id)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to set organization policy: %w", err))
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type PostgresWebhookRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresWebhookRepository(db *sql.DB) *PostgresWebhookRepository {
+	return &PostgresWebhookRepository{db: db}
+}
+
+const endpointColumns = `id, user_id, organization_id, url, secret, events, active, created_at`
+
+func scanEndpoint(row interface{ Scan(...interface{}) error }) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	var userID, orgID uuid.NullUUID
+	err := row.Scan(&endpoint.ID, &userID, &orgID, &endpoint.URL, &endpoint.Secret,
+		pq.Array(&endpoint.Events), &endpoint.Active, &endpoint.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if userID.Valid {
+		endpoint.UserID = &userID.UUID
+	}
+	if orgID.Valid {
+		endpoint.OrganizationID = &orgID.UUID
+	}
+	return &endpoint, nil
+}
+
+func (r *PostgresWebhookRepository) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	ctx, span := startSpan(ctx, "INSERT", "webhook_endpoints")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhook_endpoints (`+endpointColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		endpoint.ID, endpoint.UserID, endpoint.OrganizationID, endpoint.URL, endpoint.Secret,
+		pq.Array(endpoint.Events), endpoint.Active, endpoint.CreatedAt)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to create webhook endpoint: %w", err))
+	}
+	return nil
+}
+
+func (r *PostgresWebhookRepository) GetEndpoint(ctx context.Context, id uuid.UUID) (*models.WebhookEndpoint, error) {
+	ctx, span := startSpan(ctx, "SELECT", "webhook_endpoints")
+	defer span.End()
+
+	endpoint, err := scanEndpoint(r.db.QueryRowContext(ctx, `SELECT `+endpointColumns+` FROM webhook_endpoints WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, spanError(span, fmt.Errorf("failed to get webhook endpoint: %w", err))
+	}
+	return endpoint, nil
+}
+
+func (r *PostgresWebhookRepository) ListEndpoints(ctx context.Context, owner WebhookOwner) ([]*models.WebhookEndpoint, error) {
+	ctx, span := startSpan(ctx, "SELECT", "webhook_endpoints")
+	defer span.End()
+
+	query := `SELECT ` + endpointColumns + ` FROM webhook_endpoints WHERE user_id = $1 ORDER BY created_at`
+	arg := owner.UserID
+	if owner.OrganizationID != nil {
+		query = `SELECT ` + endpointColumns + ` FROM webhook_endpoints WHERE organization_id = $1 ORDER BY created_at`
+		arg = owner.OrganizationID
+	}
+
+	endpoints, err := r.queryEndpoints(ctx, query, arg)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+	return endpoints, nil
+}
+
+func (r *PostgresWebhookRepository) ListSubscribed(ctx context.Context, userID uuid.UUID, event string) ([]*models.WebhookEndpoint, error) {
+	ctx, span := startSpan(ctx, "SELECT", "webhook_endpoints")
+	defer span.End()
+
+	endpoints, err := r.queryEndpoints(ctx, `
+		SELECT `+endpointColumns+` FROM webhook_endpoints
+		WHERE active AND $2 = ANY(events)
+		AND (user_id = $1 OR organization_id IN (
+			SELECT organization_id FROM organization_members WHERE user_id = $1
+		))`, userID, event)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+	return endpoints, nil
+}
+
+func (r *PostgresWebhookRepository) queryEndpoints(ctx context.Context, query string, args ...interface{}) ([]*models.WebhookEndpoint, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	var endpoints []*models.WebhookEndpoint
+	for rows.Next() {
+		endpoint, err := scanEndpoint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook endpoint row: %w", err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over webhook endpoint rows: %w", err)
+	}
+	return endpoints, nil
+}
+
+func (r *PostgresWebhookRepository) DeleteEndpoint(ctx context.Context, id uuid.UUID) error {
+	ctx, span := startSpan(ctx, "DELETE", "webhook_endpoints")
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhook_endpoints WHERE id = $1`, id)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to delete webhook endpoint: %w", err))
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+const deliveryColumns = `id, endpoint_id, event_id, event, payload, status, attempts, last_status_code,
+	last_error, next_attempt_at, redelivery_of, created_at, delivered_at`
+
+func scanDelivery(row interface{ Scan(...interface{}) error }) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var payload []byte
+	var statusCode sql.NullInt64
+	var lastError sql.NullString
+	var nextAttempt, deliveredAt sql.NullTime
+	var redeliveryOf uuid.NullUUID
+	err := row.Scan(&delivery.ID, &delivery.EndpointID, &delivery.EventID, &delivery.Event, &payload,
+		&delivery.Status, &delivery.Attempts, &statusCode, &lastError, &nextAttempt, &redeliveryOf,
+		&delivery.CreatedAt, &deliveredAt)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Payload = payload
+	if statusCode.Valid {
+		code := int(statusCode.Int64)
+		delivery.LastStatusCode = &code
+	}
+	if lastError.Valid {
+		delivery.LastError = &lastError.String
+	}
+	if nextAttempt.Valid {
+		delivery.NextAttemptAt = &nextAttempt.Time
+	}
+	if redeliveryOf.Valid {
+		delivery.RedeliveryOf = &redeliveryOf.UUID
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	return &delivery, nil
+}
+
+func (r *PostgresWebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	ctx, span := startSpan(ctx, "INSERT", "webhook_deliveries")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (`+deliveryColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		delivery.ID, delivery.EndpointID, delivery.EventID, delivery.Event, []byte(delivery.Payload),
+		delivery.Status, delivery.Attempts, delivery.LastStatusCode, delivery.LastError,
+		delivery.NextAttemptAt, delivery.RedeliveryOf, delivery.CreatedAt, delivery.DeliveredAt)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to create webhook delivery: %w", err))
+	}
+	return nil
+}
+
+func (r *PostgresWebhookRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
+	ctx, span := startSpan(ctx, "SELECT", "webhook_deliveries")
+	defer span.End()
+
+	delivery, err := scanDelivery(r.db.QueryRowContext(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, spanError(span, fmt.Errorf("failed to get webhook delivery: %w", err))
+	}
+	return delivery, nil
+}
+
+func (r *PostgresWebhookRepository) ListDeliveries(ctx context.Context, endpointID uuid.UUID, limit int) ([]*models.WebhookDelivery, error) {
+	ctx, span := startSpan(ctx, "SELECT", "webhook_deliveries")
+	defer span.End()
+
+	deliveries, err := r.queryDeliveries(ctx, `
+		SELECT `+deliveryColumns+` FROM webhook_deliveries
+		WHERE endpoint_id = $1 ORDER BY created_at DESC LIMIT $2`, endpointID, limit)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+	return deliveries, nil
+}
+
+func (r *PostgresWebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.WebhookDelivery, error) {
+	ctx, span := startSpan(ctx, "UPDATE", "webhook_deliveries")
+	defer span.End()
+
+	// SKIP LOCKED lets several instances dispatch without double delivery
+	deliveries, err := r.queryDeliveries(ctx, `
+		UPDATE webhook_deliveries SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+deliveryColumns, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+	return deliveries, nil
+}
+
+func (r *PostgresWebhookRepository) queryDeliveries(ctx context.Context, query string, args ...interface{}) ([]*models.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*models.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery row: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over webhook delivery rows: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (r *PostgresWebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	ctx, span := startSpan(ctx, "UPDATE", "webhook_deliveries")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, last_status_code = $4, last_error = $5,
+			next_attempt_at = $6, delivered_at = $7
+		WHERE id = $1`,
+		delivery.ID, delivery.Status, delivery.Attempts, delivery.LastStatusCode, delivery.LastError,
+		delivery.NextAttemptAt, delivery.DeliveredAt)
+	if err != nil {
+		return spanError(span, fmt.Errorf("failed to update webhook delivery: %w", err))
+	}
+	return nil
+}
+
+var (
+	_ OrganizationRepository = (*PostgresOrganizationRepository)(nil)
+	_ WebhookRepository      = (*PostgresWebhookRepository)(nil)
+)
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"fact-check/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotFound is returned when a record does not exist
+var ErrNotFound = errors.New("not found")
+
+type NewsRepository interface {
+	Create(ctx context.Context, news *models.News) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.News, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.News, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, verdict models.Verdict) error
+	// ListPublished returns news with a verdict, most recently verified first
+	ListPublished(ctx context.Context, filter PublishedFilter) ([]*models.News, error)
+	// Resolve stores a reviewer's verdict and clears the review flag
+	Resolve(ctx context.Context, id uuid.UUID, resolution models.Resolution) error
+	// ListQuarantined returns news waiting for a moderator, oldest first
+	ListQuarantined(ctx context.Context, limit int) ([]*models.News, error)
+	// Moderate stores a moderator's decision on a quarantined news item
+	Moderate(ctx context.Context, id uuid.UUID, decision models.ModerationDecision) error
+	// Screen stores the LLM's classification of a pending news item; it
+	// returns ErrNotFound unless the item is still pending
+	Screen(ctx context.Context, id uuid.UUID, screening models.Screening) error
+	// SetPublished opts news in to or out of the public feeds
+	SetPublished(ctx context.Context, id uuid.UUID, published bool) error
+}
+
+// PublishedFilter selects verified news. Zero values match everything.
+type PublishedFilter struct {
+	Status         string
+	Topic          string
+	OrganizationID *uuid.UUID
+	// NeedsReview restricts the result to news flagged for human review
+	NeedsReview bool
+	// Public restricts the result to news its submitter published, leaving
+	// out verdicts flagged for review
+	Public bool
+	Limit  int
+}
+
+type UserRepository interface {
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetByGoogleID(ctx context.Context, googleID string) (*models.User, error)
+	// UpdateProfile saves the user's name, picture, role and AdminByEmail and
+	// refreshes UpdatedAt
+	UpdateProfile(ctx context.Context, user *models.User) error
+	// ScheduleDeletion sets or, with a nil time, clears a pending deletion
+	ScheduleDeletion(ctx context.Context, id uuid.UUID, at *time.Time) error
+	// ListDueDeletions returns users whose scheduled deletion is due
+	ListDueDeletions(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error)
+	// SetLocale sets or, with a nil locale, clears the preferred locale
+	SetLocale(ctx context.Context, id uuid.UUID, locale *string) error
+}
+
+// UsageFilter selects LLM usage rows. Zero values match everything.
+type UsageFilter struct {
+	UserID *uuid.UUID
+	Role   string
+	From   time.Time
+	To     time.Time
+}
+
+// UsageGroup is the dimension usage summaries are grouped by
+type UsageGroup string
+
+const (
+	GroupByModel UsageGroup = "model"
+	GroupByUser  UsageGroup = "user"
+)
+
+type UsageRepository interface {
+	Create(ctx context.Context, usage *models.LLMUsage) error
+	// Spend returns the summed cost of matching rows
+	Spend(ctx context.Context, filter UsageFilter) (float64, error)
+	// Summarize returns matching rows grouped by the given dimension, most expensive first
+	Summarize(ctx context.Context, filter UsageFilter, groupBy UsageGroup) ([]models.UsageSummary, error)
+	// List returns matching rows, oldest first
+	List(ctx context.Context, filter UsageFilter) ([]models.LLMUsage, error)
+}
+
+type OrganizationRepository interface {
+	// Create stores the organization and makes owner its first member
+	Create(ctx context.Context, org *models.Organization, owner uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Organization, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Organization, error)
+	// AddMember adds a member or updates the role of an existing one
+	AddMember(ctx context.Context, member *models.OrganizationMember) error
+	GetMember(ctx context.Context, orgID, userID uuid.UUID) (*models.OrganizationMember, error)
+	// SetPolicy replaces the organization's policy
+	SetPolicy(ctx context.Context, id uuid.UUID, policy models.OrganizationPolicy) error
+}
+
+// WebhookOwner identifies the user or organization owning webhook endpoints.
+// Exactly one of the fields is set.
+type WebhookOwner struct {
+	UserID         *uuid.UUID
+	OrganizationID *uuid.UUID
+}
+
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error
+	GetEndpoint(ctx context.Context, id uuid.UUID) (*models.WebhookEndpoint, error)
+	ListEndpoints(ctx context.Context, owner WebhookOwner) ([]*models.WebhookEndpoint, error)
+	// ListSubscribed returns active endpoints subscribed to event that belong
+	// to the user or to an organization the user is a member of
+	ListSubscribed(ctx context.Context, userID uuid.UUID, event string) ([]*models.WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, id uuid.UUID) error
+
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error)
+	// ListDeliveries returns an endpoint's most recent deliveries first
+	ListDeliveries(ctx context.Context, endpointID uuid.UUID, limit int) ([]*models.WebhookDelivery, error)
+	// ClaimDue returns pending deliveries whose next attempt is due and pushes
+	// their next attempt back by lease so concurrent workers skip them
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.WebhookDelivery, error)
+	// UpdateDelivery saves the status, attempt count and outcome of a delivery
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+type VerificationJobRepository interface {
+	// CreateBatch stores the batch with its news items and their queued jobs
+	// in one transaction
+	CreateBatch(ctx context.Context, batch *models.Batch, news []*models.News, jobs []*models.VerificationJob) error
+	GetBatch(ctx context.Context, id uuid.UUID) (*models.Batch, error)
+	ListBatchJobs(ctx context.Context, batchID uuid.UUID) ([]*models.VerificationJob, error)
+	// Claim marks up to limit queued jobs, and running jobs started before
+	// now minus lease, as running and returns them with attempts incremented
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.VerificationJob, error)
+	// UpdateJob saves the status and outcome of a job
+	UpdateJob(ctx context.Context, job *models.VerificationJob) error
+	// Backlog counts queued and running jobs
+	Backlog(ctx context.Context) (*models.QueueBacklog, error)
+}
+
+// AccountEraser removes a user's personal data
+type AccountEraser interface {
+	// EraseUser deletes the user's news, batches, webhooks and memberships,
+	// drops organizations left without members, hands organizations left
+	// without an owner to their longest-standing member and anonymizes the
+	// user row, clearing every personal column.
+	// Usage rows are kept, without a news link, for spend accounting.
+	EraseUser(ctx context.Context, userID uuid.UUID, now time.Time) error
+}
+
+// AuditFilter selects audit entries. Zero values match everything.
+type AuditFilter struct {
+	SubjectID *uuid.UUID
+	Action    string
+	Limit     int
+}
+
+type AuditRepository interface {
+	Record(ctx context.Context, entry *models.AuditEntry) error
+	// List returns matching entries, newest first
+	List(ctx context.Context, filter AuditFilter) ([]*models.AuditEntry, error)
+}
+
+// SourceRepository stores the source credibility registry
+type SourceRepository interface {
+	// Upsert creates the source or replaces the entry with the same domain,
+	// setting source.ID and CreatedAt to the stored values
+	Upsert(ctx context.Context, source *models.Source) error
+	GetByDomain(ctx context.Context, domain string) (*models.Source, error)
+	// FindByDomains returns the entries for any of the given domains
+	FindByDomains(ctx context.Context, domains []string) ([]*models.Source, error)
+	List(ctx context.Context) ([]*models.Source, error)
+	Delete(ctx context.Context, domain string) error
+}
+
+// TranslationRepository stores translations of news items
+type TranslationRepository interface {
+	// Upsert creates the translation or replaces the one for the same news
+	// item and locale, setting translation.ID and CreatedAt to the stored values
+	Upsert(ctx context.Context, translation *models.NewsTranslation) error
+	Get(ctx context.Context, newsID uuid.UUID, locale string) (*models.NewsTranslation, error)
+}
+
+// PromptRepository stores published prompt versions
+type PromptRepository interface {
+	// Create stores a new version and sets prompt.Version to its number
+	Create(ctx context.Context, prompt *models.PromptVersion) error
+	Get(ctx context.Context, version int) (*models.PromptVersion, error)
+	// List returns all versions, newest first
+	List(ctx context.Context) ([]*models.PromptVersion, error)
+	// ListActive returns the versions with a positive weight
+	ListActive(ctx context.Context) ([]*models.PromptVersion, error)
+	SetWeight(ctx context.Context, version, weight int) error
+}
+
+// CalibrationRepository stores fitted confidence calibrations
+type CalibrationRepository interface {
+	// Create stores a calibration and sets its ID
+	Create(ctx context.Context, calibration *models.Calibration) error
+	// Latest returns the most recently fitted calibration
+	Latest(ctx context.Context) (*models.Calibration, error)
+	// ListOutcomes returns up to limit reviewed verdicts, most recently
+	// reviewed first, for which the model gave true or false with a confidence
+	ListOutcomes(ctx context.Context, limit int) ([]models.CalibrationOutcome, error)
+}
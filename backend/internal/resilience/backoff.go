@@ -0,0 +1,55 @@
+package resilience
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Backoff computes exponential retry delays with full jitter
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Delay returns a random delay in [0, min(Max, Base*2^attempt)] for the
+// zero-based retry attempt
+func (b Backoff) Delay(attempt int) time.Duration {
+	ceiling := b.Base
+	for i := 0; i < attempt && ceiling < b.Max; i++ {
+		ceiling *= 2
+	}
+	if ceiling > b.Max {
+		ceiling = b.Max
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// RetryAfter parses a Retry-After header given either in seconds or as an
+// HTTP date
+func RetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,140 @@
+package resilience
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when a call is rejected by an open breaker
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half_open"
+)
+
+// CircuitBreaker fails fast after FailureThreshold consecutive failures and
+// lets a single trial call through once Cooldown has passed
+type CircuitBreaker struct {
+	name             string
+	failureThreshold int
+	cooldown         time.Duration
+
+	mutex       sync.Mutex
+	state       State
+	failures    int
+	openedAt    time.Time
+	trialActive bool
+	lastError   string
+	now         func() time.Time
+}
+
+// BreakerSnapshot is the externally visible state of a breaker
+type BreakerSnapshot struct {
+	Name                string     `json:"name"`
+	State               State      `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+func NewCircuitBreaker(name string, failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	return &CircuitBreaker{
+		name:             name,
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		state:            StateClosed,
+		now:              time.Now,
+	}
+}
+
+// Allow returns ErrCircuitOpen if the call should not be attempted
+func (b *CircuitBreaker) Allow() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = StateHalfOpen
+		b.trialActive = true
+		return nil
+	case StateHalfOpen:
+		if b.trialActive {
+			return ErrCircuitOpen
+		}
+		b.trialActive = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Success records a successful call and closes the breaker
+func (b *CircuitBreaker) Success() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.state = StateClosed
+	b.failures = 0
+	b.trialActive = false
+	b.lastError = ""
+}
+
+// Release ends a call that was abandoned before the provider answered, such
+// as a cancelled request. It counts neither as a success nor as a failure,
+// but frees the half-open trial so another call can take it.
+func (b *CircuitBreaker) Release() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.trialActive = false
+}
+
+// Failure records a failed call, opening the breaker when the threshold is
+// reached or when a half-open trial fails
+func (b *CircuitBreaker) Failure(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures++
+	b.trialActive = false
+	if err != nil {
+		b.lastError = err.Error()
+	}
+
+	if b.state == StateHalfOpen || b.failures >= b.failureThreshold {
+		b.state = StateOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Snapshot returns the current breaker state
+func (b *CircuitBreaker) Snapshot() BreakerSnapshot {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	snapshot := BreakerSnapshot{
+		Name:                b.name,
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		LastError:           b.lastError,
+	}
+	if b.state != StateClosed {
+		openedAt := b.openedAt
+		retryAt := openedAt.Add(b.cooldown)
+		snapshot.OpenedAt = &openedAt
+		snapshot.RetryAt = &retryAt
+	}
+	return snapshot
+}
@@ -0,0 +1,191 @@
+package resilience
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ClientConfig configures a resilient HTTP client
+type ClientConfig struct {
+	ConnectTimeout time.Duration
+	RequestTimeout time.Duration
+	MaxRetries     int
+	Backoff        Backoff
+}
+
+// Client wraps a shared http.Client with retries and a circuit breaker.
+// Response bodies are fully read so retry decisions can inspect them.
+type Client struct {
+	http       *http.Client
+	breaker    *CircuitBreaker
+	maxRetries int
+	backoff    Backoff
+
+	// ShouldRetry decides whether a response is retryable. It defaults to
+	// retrying 429 and 5xx responses.
+	ShouldRetry func(resp *http.Response, body []byte) bool
+
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func NewClient(cfg ClientConfig, breaker *CircuitBreaker) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   cfg.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = cfg.ConnectTimeout
+
+	return &Client{
+		http: &http.Client{
+			Timeout:   cfg.RequestTimeout,
+			Transport: transport,
+		},
+		breaker:     breaker,
+		maxRetries:  cfg.MaxRetries,
+		backoff:     cfg.Backoff,
+		ShouldRetry: DefaultShouldRetry,
+		sleep:       sleepContext,
+	}
+}
+
+// DefaultShouldRetry retries rate limits and server errors
+func DefaultShouldRetry(resp *http.Response, body []byte) bool {
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// HTTPClient returns the underlying client, for calls that should share its
+// timeouts and connection pool but skip retries
+func (c *Client) HTTPClient() *http.Client {
+	return c.http
+}
+
+// Breaker returns the circuit breaker guarding this client
+func (c *Client) Breaker() *CircuitBreaker {
+	return c.breaker
+}
+
+// Do sends the request, retrying transport errors and retryable responses
+// with exponential backoff. Retry-After is honored when present; when it
+// asks for a longer wait than the backoff's Max, or one that would outlast
+// the request context, the last response is returned instead of retrying
+// early. The request body must be replayable through
+// req.GetBody. Waiting between attempts stops as soon as the request context
+// is done.
+func (c *Client) Do(req *http.Request) (*http.Response, []byte, error) {
+	return c.do(req, false)
+}
+
+// Stream is like Do for streaming APIs: a 2xx response is returned with its
+// body unread and the caller must close it. Retries only happen before a
+// successful response starts; other responses are returned fully read.
+func (c *Client) Stream(req *http.Request) (*http.Response, []byte, error) {
+	return c.do(req, true)
+}
+
+func (c *Client) do(req *http.Request, stream bool) (*http.Response, []byte, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to rewind request body: %w", err)
+				}
+				req.Body = body
+			} else if req.Body != nil {
+				return nil, nil, lastErr
+			}
+		}
+
+		if err := c.breaker.Allow(); err != nil {
+			if lastErr != nil {
+				return nil, nil, fmt.Errorf("%w (last error: %v)", err, lastErr)
+			}
+			return nil, nil, err
+		}
+
+		resp, body, err := c.attempt(req, stream)
+		if err != nil {
+			// A cancelled request says nothing about the provider's health
+			if req.Context().Err() != nil {
+				c.breaker.Release()
+				return nil, nil, err
+			}
+			c.breaker.Failure(err)
+			lastErr = err
+			if attempt < c.maxRetries {
+				if err := c.sleep(req.Context(), c.backoff.Delay(attempt)); err != nil {
+					return nil, nil, fmt.Errorf("%w (last error: %v)", err, lastErr)
+				}
+			}
+			continue
+		}
+
+		if resp.StatusCode >= 500 {
+			c.breaker.Failure(fmt.Errorf("status %d", resp.StatusCode))
+		} else {
+			c.breaker.Success()
+		}
+
+		if attempt == c.maxRetries || !c.ShouldRetry(resp, body) {
+			return resp, body, nil
+		}
+
+		lastErr = fmt.Errorf("status %d", resp.StatusCode)
+		delay := c.backoff.Delay(attempt)
+		if retryAfter, ok := RetryAfter(resp.Header, time.Now()); ok {
+			// Retrying before the provider asked would only add to its load
+			if retryAfter > c.backoff.Max {
+				return resp, body, nil
+			}
+			delay = retryAfter
+		}
+		// Waiting past the deadline would only end in a timeout
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < delay {
+			return resp, body, nil
+		}
+		if err := c.sleep(req.Context(), delay); err != nil {
+			return nil, nil, fmt.Errorf("%w (last error: %v)", err, lastErr)
+		}
+	}
+
+	return nil, nil, fmt.Errorf("request failed after %d attempts: %w", c.maxRetries+1, lastErr)
+}
+
+func (c *Client) attempt(req *http.Request, stream bool) (*http.Response, []byte, error) {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if stream && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil, nil
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return resp, body, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,226 @@
+package resilience
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackoffDelayIsCapped(t *testing.T) {
+	backoff := Backoff{Base: 100 * time.Millisecond, Max: time.Second}
+	for attempt := 0; attempt < 10; attempt++ {
+		if delay := backoff.Delay(attempt); delay < 0 || delay > time.Second {
+			t.Fatalf("attempt %d: delay %v outside [0, 1s]", attempt, delay)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	header := http.Header{}
+	header.Set("Retry-After", "7")
+	if wait, ok := RetryAfter(header, now); !ok || wait != 7*time.Second {
+		t.Fatalf("expected 7s, got %v (ok=%v)", wait, ok)
+	}
+
+	header.Set("Retry-After", now.Add(3*time.Second).Format(http.TimeFormat))
+	if wait, ok := RetryAfter(header, now); !ok || wait != 3*time.Second {
+		t.Fatalf("expected 3s, got %v (ok=%v)", wait, ok)
+	}
+
+	header.Set("Retry-After", "soon")
+	if _, ok := RetryAfter(header, now); ok {
+		t.Fatal("expected invalid Retry-After to be ignored")
+	}
+}
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	now := time.Now()
+	breaker := NewCircuitBreaker("test", 2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	breaker.Failure(errors.New("boom"))
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("breaker opened too early: %v", err)
+	}
+	breaker.Failure(errors.New("boom"))
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected open breaker, got %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("expected half-open trial, got %v", err)
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatal("expected only one trial call while half-open")
+	}
+
+	breaker.Success()
+	if state := breaker.Snapshot().State; state != StateClosed {
+		t.Fatalf("expected closed breaker, got %s", state)
+	}
+}
+
+func TestCancelledTrialDoesNotWedgeBreaker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	breaker := NewCircuitBreaker("test", 1, time.Minute)
+	breaker.now = func() time.Time { return now }
+	breaker.Failure(errors.New("boom"))
+	now = now.Add(2 * time.Minute)
+
+	client := NewClient(ClientConfig{
+		ConnectTimeout: time.Second,
+		RequestTimeout: time.Second,
+		MaxRetries:     3,
+		Backoff:        Backoff{Base: time.Millisecond, Max: time.Millisecond},
+	}, breaker)
+	client.sleep = func(context.Context, time.Duration) error { return nil }
+
+	// The half-open trial is abandoned by its caller
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, _, err := client.Do(req); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a cancelled request, got %v", err)
+	}
+	if snapshot := breaker.Snapshot(); snapshot.State != StateHalfOpen || snapshot.ConsecutiveFailures != 1 {
+		t.Fatalf("expected the cancellation not to count, got %+v", snapshot)
+	}
+
+	// The next call gets the trial and closes the breaker
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	if _, _, err := client.Do(req); err != nil {
+		t.Fatalf("expected the next trial to go through, got %v", err)
+	}
+	if state := breaker.Snapshot().State; state != StateClosed {
+		t.Fatalf("expected closed breaker, got %s", state)
+	}
+}
+
+func TestClientRetriesRetryableResponses(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{
+		ConnectTimeout: time.Second,
+		RequestTimeout: time.Second,
+		MaxRetries:     3,
+		Backoff:        Backoff{Base: time.Millisecond, Max: time.Millisecond},
+	}, NewCircuitBreaker("test", 5, time.Minute))
+	client.sleep = func(context.Context, time.Duration) error { return nil }
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, bytes.NewBufferString("{}"))
+	resp, body, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Fatalf("unexpected response %d %q", resp.StatusCode, body)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestClientHonorsRetryAfter(t *testing.T) {
+	var calls int32
+	retryAfter := "1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", retryAfter)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{
+		ConnectTimeout: time.Second,
+		RequestTimeout: time.Second,
+		MaxRetries:     1,
+		Backoff:        Backoff{Base: time.Millisecond, Max: 2 * time.Second},
+	}, NewCircuitBreaker("test", 5, time.Minute))
+	var delays []time.Duration
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	if resp, _, err := client.Do(req); err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected the last 429, got %v", err)
+	}
+	if len(delays) != 1 || delays[0] != time.Second || calls != 2 {
+		t.Fatalf("expected one wait of 1s, got %v after %d calls", delays, calls)
+	}
+
+	// A wait longer than the backoff's Max returns the response instead of
+	// retrying before the provider asked
+	retryAfter = "3600"
+	delays = nil
+	calls = 0
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	if resp, _, err := client.Do(req); err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected the 429 returned, got %v", err)
+	}
+	if len(delays) != 0 || calls != 1 {
+		t.Fatalf("expected no wait and a single call, got %v after %d calls", delays, calls)
+	}
+
+	// A wait outlasting the deadline returns the response right away
+	retryAfter = "1"
+	delays = nil
+	calls = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if resp, _, err := client.Do(req); err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected the 429 returned, got %v", err)
+	}
+	if len(delays) != 0 || calls != 1 {
+		t.Fatalf("expected no wait and a single call, got %v after %d calls", delays, calls)
+	}
+}
+
+func TestClientFailsFastWhenBreakerOpen(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{
+		ConnectTimeout: time.Second,
+		RequestTimeout: time.Second,
+		MaxRetries:     5,
+		Backoff:        Backoff{Base: time.Millisecond, Max: time.Millisecond},
+	}, NewCircuitBreaker("test", 2, time.Minute))
+	client.sleep = func(context.Context, time.Duration) error { return nil }
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	if _, _, err := client.Do(req); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected circuit open error, got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected breaker to stop after 2 calls, got %d", calls)
+	}
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"fact-check/internal/config"
+	"fact-check/internal/handlers"
+	"fact-check/internal/health"
+	"fact-check/internal/logging"
+	"fact-check/internal/metrics"
+	"fact-check/internal/middleware"
+	"fact-check/internal/models"
+	"fact-check/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+)
+
+// Services bundles the dependencies the HTTP API is built from
+type Services struct {
+	Auth          *services.AuthService
+	News          *services.NewsService
+	Usage         *services.UsageService
+	Verifications *services.VerificationService
+	Streams       *services.VerificationStreams
+	Batches       *services.BatchService
+	Organizations *services.OrganizationService
+	Webhooks      *services.WebhookService
+	Accounts      *services.AccountService
+	Sources       *services.SourceService
+	Languages     *services.LanguageService
+	Prompts       *services.PromptService
+	Calibration   *services.CalibrationService
+	Health        *health.Registry
+	LogLevels     *logging.LevelController
+}
+
+// NewRouter builds the gin engine with middleware and all API routes
+func NewRouter(configs *config.Manager, svc Services, logger *logrus.Logger) *gin.Engine {
+	cfg := configs.Current()
+
+	// Initialize handlers
+	authHandler := handlers.NewAuthHandler(svc.Auth, logger)
+	newsHandler := handlers.NewNewsHandler(svc.News, svc.Verifications, svc.Streams, cfg.Stream.Keepalive, logger)
+	usageHandler := handlers.NewUsageHandler(svc.Usage, logger)
+	healthHandler := handlers.NewHealthHandler(svc.Health)
+	adminHandler := handlers.NewAdminHandler(configs, svc.LogLevels, logger)
+	webhookHandler := handlers.NewWebhookHandler(svc.Organizations, svc.Webhooks, logger)
+	batchHandler := handlers.NewBatchHandler(svc.Batches, logger)
+	accountHandler := handlers.NewAccountHandler(svc.Accounts, logger)
+	feedHandler := handlers.NewFeedHandler(svc.News, cfg.Publisher, logger)
+	sourceHandler := handlers.NewSourceHandler(svc.Sources, logger)
+	languageHandler := handlers.NewLanguageHandler(svc.Languages, logger)
+	promptHandler := handlers.NewPromptHandler(svc.Prompts, logger)
+	reviewHandler := handlers.NewReviewHandler(svc.News, logger)
+	moderationHandler := handlers.NewModerationHandler(svc.News, logger)
+	calibrationHandler := handlers.NewCalibrationHandler(svc.Calibration, logger)
+
+	// Rate limits follow config reloads
+	rateLimiter := middleware.NewRateLimiter(cfg.RateLimit.Requests, cfg.RateLimit.Window)
+	configs.Subscribe(func(cfg *config.Config) {
+		rateLimiter.SetLimit(cfg.RateLimit.Requests, cfg.RateLimit.Window)
+	})
+
+	// Setup Gin router
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(middleware.Tracing())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.CORS())
+	router.Use(middleware.RequestLogger(logger))
+	router.Use(middleware.Metrics())
+	router.Use(rateLimiter.Limit())
+
+	// Health check endpoints
+	router.GET("/health", healthHandler.Livez)
+	router.GET("/livez", healthHandler.Livez)
+	router.GET("/readyz", healthHandler.Readyz)
+
+	// Prometheus metrics
+	router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})))
+
+	// API routes
+	api := router.Group("/api/v1")
+	{
+		// Service status endpoint
+		api.GET("/services/status", healthHandler.Status)
+
+		// Auth routes
+		auth := api.Group("/auth")
+		{
+			auth.GET("/login", authHandler.Login)
+			auth.GET("/callback", authHandler.Callback)
+			auth.GET("/me", middleware.AuthMiddleware(svc.Auth), authHandler.Me)
+			auth.POST("/logout", middleware.AuthMiddleware(svc.Auth), authHandler.Logout)
+		}
+
+		// News routes
+		news := api.Group("/news")
+		{
+			news.POST("/submit", middleware.AuthMiddleware(svc.Auth), newsHandler.Submit)
+			news.POST("/batch", middleware.AuthMiddleware(svc.Auth), batchHandler.Submit)
+			news.GET("/batch/:id", middleware.AuthMiddleware(svc.Auth), batchHandler.Progress)
+			news.GET("/verify/:id", middleware.AuthMiddleware(svc.Auth), newsHandler.Verify)
+			news.GET("/verify/:id/stream", middleware.AuthMiddleware(svc.Auth), newsHandler.StreamVerify)
+			news.GET("/user/:id", middleware.AuthMiddleware(svc.Auth), newsHandler.GetUserNews)
+			news.GET("/:id/translation", middleware.AuthMiddleware(svc.Auth), languageHandler.Translation)
+			news.PUT("/:id/publish", middleware.AuthMiddleware(svc.Auth), newsHandler.Publish)
+			// Verdicts are published for search engines, so this needs no login
+			news.GET("/:id/claim-review", newsHandler.ClaimReview)
+		}
+
+		// Public feeds of the verdicts submitters chose to publish
+		feeds := api.Group("/feeds")
+		{
+			feeds.GET("/rss", feedHandler.RSS)
+			feeds.GET("/atom", feedHandler.Atom)
+		}
+
+		// Usage routes
+		api.GET("/usage/me", middleware.AuthMiddleware(svc.Auth), usageHandler.MyUsage)
+
+		// Source registry lookups
+		api.GET("/sources/:domain", middleware.AuthMiddleware(svc.Auth), sourceHandler.Get)
+
+		// Account routes
+		me := api.Group("/me", middleware.AuthMiddleware(svc.Auth))
+		{
+			me.GET("/export", accountHandler.Export)
+			me.DELETE("", accountHandler.Delete)
+			me.POST("/deletion/cancel", accountHandler.CancelDeletion)
+			me.PUT("/locale", languageHandler.SetLocale)
+		}
+
+		// Organization routes
+		orgs := api.Group("/orgs", middleware.AuthMiddleware(svc.Auth))
+		{
+			orgs.POST("", webhookHandler.CreateOrganization)
+			orgs.GET("", webhookHandler.ListOrganizations)
+			orgs.POST("/:id/members", webhookHandler.AddMember)
+			orgs.PUT("/:id/policy", webhookHandler.SetPolicy)
+		}
+
+		// Webhook routes
+		webhooks := api.Group("/webhooks", middleware.AuthMiddleware(svc.Auth))
+		{
+			webhooks.POST("", webhookHandler.CreateEndpoint)
+			webhooks.GET("", webhookHandler.ListEndpoints)
+			webhooks.DELETE("/:id", webhookHandler.DeleteEndpoint)
+			webhooks.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+			webhooks.POST("/:id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver)
+		}
+
+		// Admin routes
+		admin := api.Group("/admin", middleware.AuthMiddleware(svc.Auth), middleware.RequireRole(svc.Auth, models.RoleAdmin))
+		{
+			admin.GET("/usage", usageHandler.Report)
+			admin.GET("/status", healthHandler.DetailedStatus)
+			admin.GET("/log-level", adminHandler.GetLogLevel)
+			admin.PUT("/log-level", adminHandler.SetLogLevel)
+			admin.DELETE("/log-level", adminHandler.ResetLogLevel)
+			admin.POST("/config/reload", adminHandler.ReloadConfig)
+			admin.GET("/audit", accountHandler.AuditLog)
+			admin.GET("/sources", sourceHandler.List)
+			admin.POST("/sources/import", sourceHandler.Import)
+			admin.PUT("/sources/:domain", sourceHandler.Put)
+			admin.DELETE("/sources/:domain", sourceHandler.Delete)
+			admin.GET("/prompts", promptHandler.List)
+			admin.POST("/prompts", promptHandler.Publish)
+			admin.GET("/prompts/:version", promptHandler.Get)
+			admin.PUT("/prompts/:version/weight", promptHandler.SetWeight)
+			admin.GET("/reviews", reviewHandler.List)
+			admin.POST("/reviews/:id", reviewHandler.Resolve)
+			admin.GET("/moderation", moderationHandler.List)
+			admin.POST("/moderation/:id", moderationHandler.Resolve)
+			admin.GET("/calibration", calibrationHandler.Get)
+			admin.POST("/calibration/fit", calibrationHandler.Fit)
+		}
+	}
+
+	return router
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"fact-check/internal/config"
+	"fact-check/internal/models"
+	"fact-check/internal/repository"
+	"fact-check/internal/tracing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrUserNotFound is returned for unknown or already deleted accounts
+var ErrUserNotFound = errors.New("user not found")
+
+// deletionBatch is how many due deletions one pass processes at most
+const deletionBatch = 50
+
+// AccountRepositories are the stores a user's data lives in
+type AccountRepositories struct {
+	Users         repository.UserRepository
+	News          repository.NewsRepository
+	Usage         repository.UsageRepository
+	Organizations repository.OrganizationRepository
+	Webhooks      repository.WebhookRepository
+	Eraser        repository.AccountEraser
+	Audit         repository.AuditRepository
+}
+
+// AccountService exports a user's data and runs account deletion: a request
+// starts a grace period during which it can be cancelled, after which the
+// account is erased and an audit entry is kept
+type AccountService struct {
+	config *config.Config
+	repos  AccountRepositories
+	logger *logrus.Logger
+}
+
+func NewAccountService(cfg *config.Config, repos AccountRepositories, logger *logrus.Logger) *AccountService {
+	return &AccountService{
+		config: cfg,
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// Export collects the profile, submissions with their verdicts, LLM usage,
+// organizations and webhook endpoints of the user
+func (s *AccountService) Export(ctx context.Context, userID string) (*models.AccountExport, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "AccountService.Export")
+	defer span.End()
+
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &models.AccountExport{ExportedAt: time.Now().UTC(), Profile: user}
+	if export.News, err = s.repos.News.ListByUser(ctx, user.ID); err != nil {
+		return nil, fmt.Errorf("failed to export news: %w", err)
+	}
+	if export.Usage, err = s.repos.Usage.List(ctx, repository.UsageFilter{UserID: &user.ID}); err != nil {
+		return nil, fmt.Errorf("failed to export usage: %w", err)
+	}
+	if export.Organizations, err = s.repos.Organizations.ListByUser(ctx, user.ID); err != nil {
+		return nil, fmt.Errorf("failed to export organizations: %w", err)
+	}
+	if export.Webhooks, err = s.repos.Webhooks.ListEndpoints(ctx, repository.WebhookOwner{UserID: &user.ID}); err != nil {
+		return nil, fmt.Errorf("failed to export webhooks: %w", err)
+	}
+
+	s.audit(ctx, models.AuditAccountExported, &user.ID, user.ID, nil)
+	return export, nil
+}
+
+// RequestDeletion schedules the account for erasure after the grace period
+// and returns when that will happen. Repeated requests keep the original
+// schedule. Without a grace period the account is erased right away.
+func (s *AccountService) RequestDeletion(ctx context.Context, userID string) (time.Time, error) {
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if user.DeletionScheduledFor != nil {
+		return *user.DeletionScheduledFor, nil
+	}
+
+	scheduledFor := time.Now().Add(s.config.Privacy.DeletionGracePeriod).UTC()
+	if err := s.repos.Users.ScheduleDeletion(ctx, user.ID, &scheduledFor); err != nil {
+		return time.Time{}, err
+	}
+	s.audit(ctx, models.AuditAccountDeletionRequested, &user.ID, user.ID, map[string]interface{}{
+		"scheduled_for": scheduledFor,
+	})
+	s.logger.WithContext(ctx).Infof("Account deletion scheduled for %s", scheduledFor.Format(time.RFC3339))
+
+	if s.config.Privacy.DeletionGracePeriod == 0 {
+		if err := s.erase(ctx, user.ID); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return scheduledFor, nil
+}
+
+// CancelDeletion withdraws a pending deletion request. It reports whether
+// one was pending.
+func (s *AccountService) CancelDeletion(ctx context.Context, userID string) (bool, error) {
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if user.DeletionScheduledFor == nil {
+		return false, nil
+	}
+
+	if err := s.repos.Users.ScheduleDeletion(ctx, user.ID, nil); err != nil {
+		return false, err
+	}
+	s.audit(ctx, models.AuditAccountDeletionCancelled, &user.ID, user.ID, nil)
+	return true, nil
+}
+
+// AuditLog returns audit entries, newest first
+func (s *AccountService) AuditLog(ctx context.Context, filter repository.AuditFilter) ([]*models.AuditEntry, error) {
+	return s.repos.Audit.List(ctx, filter)
+}
+
+// Run erases accounts whose grace period has ended, checking every
+// deletion check interval until ctx is done
+func (s *AccountService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Privacy.DeletionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.ProcessDueDeletions(ctx); err != nil {
+			s.logger.WithContext(ctx).Errorf("Failed to process account deletions: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDueDeletions erases every account whose deletion is due and
+// returns how many were erased
+func (s *AccountService) ProcessDueDeletions(ctx context.Context) (int, error) {
+	erased := 0
+	for {
+		ids, err := s.repos.Users.ListDueDeletions(ctx, time.Now(), deletionBatch)
+		if err != nil {
+			return erased, err
+		}
+
+		for _, id := range ids {
+			if err := s.erase(ctx, id); err != nil {
+				return erased, err
+			}
+			erased++
+		}
+		if len(ids) < deletionBatch {
+			return erased, nil
+		}
+	}
+}
+
+func (s *AccountService) erase(ctx context.Context, userID uuid.UUID) error {
+	if err := s.repos.Eraser.EraseUser(ctx, userID, time.Now()); err != nil {
+		return fmt.Errorf("failed to erase user %s: %w", userID, err)
+	}
+
+	s.audit(ctx, models.AuditAccountDeleted, nil, userID, map[string]interface{}{
+		"erased":   []string{"profile", "news", "verifications", "batches", "webhooks", "organization_memberships"},
+		"retained": []string{"llm_usage"},
+	})
+	s.logger.WithContext(ctx).Infof("Account %s erased", userID)
+	return nil
+}
+
+// activeUser loads a user that has not been erased
+func (s *AccountService) activeUser(ctx context.Context, userID string) (*models.User, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	user, err := s.repos.Users.GetByID(ctx, userUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if user.DeletedAt != nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// audit records an entry; a failure is logged since the action itself has
+// already happened
+func (s *AccountService) audit(ctx context.Context, action string, actorID *uuid.UUID, subjectID uuid.UUID, details map[string]interface{}) {
+	entry := &models.AuditEntry{
+		ID:        uuid.New(),
+		Action:    action,
+		ActorID:   actorID,
+		SubjectID: subjectID,
+		Details:   details,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repos.Audit.Record(ctx, entry); err != nil {
+		s.logger.WithContext(ctx).Errorf("Failed to record audit entry %s for %s: %v", action, subjectID, err)
+	}
+}
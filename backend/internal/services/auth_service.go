@@ -2,14 +2,15 @@ package services
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	"fact-check/internal/config"
 	"fact-check/internal/models"
+	"fact-check/internal/repository"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -20,12 +21,12 @@ import (
 
 type AuthService struct {
 	config       *config.Config
-	db           *sql.DB
+	users        repository.UserRepository
 	logger       *logrus.Logger
 	oauth2Config *oauth2.Config
 }
 
-func NewAuthService(cfg *config.Config, db *sql.DB, logger *logrus.Logger) *AuthService {
+func NewAuthService(cfg *config.Config, users repository.UserRepository, logger *logrus.Logger) *AuthService {
 	oauth2Config := &oauth2.Config{
 		ClientID:     cfg.GoogleClientID,
 		ClientSecret: cfg.GoogleClientSecret,
@@ -39,7 +40,7 @@ func NewAuthService(cfg *config.Config, db *sql.DB, logger *logrus.Logger) *Auth
 
 	return &AuthService{
 		config:       cfg,
-		db:           db,
+		users:        users,
 		logger:       logger,
 		oauth2Config: oauth2Config,
 	}
@@ -49,43 +50,44 @@ func (s *AuthService) GetLoginURL() string {
 	return s.oauth2Config.AuthCodeURL("state", oauth2.AccessTypeOffline)
 }
 
-func (s *AuthService) HandleCallback(code string) (*models.AuthCallbackResponse, error) {
-	s.logger.Infof("Starting OAuth callback with code: %s", code[:10]+"...")
+func (s *AuthService) HandleCallback(ctx context.Context, code string) (*models.AuthCallbackResponse, error) {
+	logger := s.logger.WithContext(ctx)
+	logger.Info("Starting OAuth callback")
 
 	// Exchange code for token
-	token, err := s.oauth2Config.Exchange(context.Background(), code)
+	exchangeCtx, cancel := withTimeout(ctx, s.config.Timeouts.OAuth)
+	defer cancel()
+
+	token, err := s.oauth2Config.Exchange(exchangeCtx, code)
 	if err != nil {
-		s.logger.Errorf("OAuth exchange failed: %v", err)
-		s.logger.Errorf("OAuth config - ClientID: %s, RedirectURL: %s", s.oauth2Config.ClientID, s.oauth2Config.RedirectURL)
+		logger.Errorf("OAuth exchange failed (redirect URL %s): %v", s.oauth2Config.RedirectURL, err)
 		return nil, fmt.Errorf("failed to exchange code for token: %w", err)
 	}
 
-	s.logger.Infof("OAuth exchange successful, got access token: %s", token.AccessToken[:10]+"...")
+	logger.Debug("OAuth exchange successful")
 
 	// Get user info from Google
-	userInfo, err := s.getGoogleUserInfo(token.AccessToken)
+	userInfo, err := s.getGoogleUserInfo(exchangeCtx, token.AccessToken)
 	if err != nil {
-		s.logger.Errorf("Failed to get Google user info: %v", err)
+		logger.Errorf("Failed to get Google user info: %v", err)
 		return nil, fmt.Errorf("failed to get user info: %w", err)
 	}
 
-	s.logger.Infof("Got user info for: %s (%s)", userInfo.Email, userInfo.Name)
-
 	// Find or create user in database
-	user, err := s.findOrCreateUser(userInfo)
+	user, err := s.findOrCreateUser(ctx, userInfo)
 	if err != nil {
-		s.logger.Errorf("Failed to find or create user: %v", err)
+		logger.Errorf("Failed to find or create user: %v", err)
 		return nil, fmt.Errorf("failed to find or create user: %w", err)
 	}
 
 	// Generate JWT token
 	jwtToken, err := s.generateJWT(user.ID.String())
 	if err != nil {
-		s.logger.Errorf("Failed to generate JWT: %v", err)
+		logger.Errorf("Failed to generate JWT: %v", err)
 		return nil, fmt.Errorf("failed to generate JWT: %w", err)
 	}
 
-	s.logger.Infof("OAuth callback completed successfully for user: %s", user.Email)
+	logger.Infof("OAuth callback completed successfully for user: %s", user.ID)
 
 	return &models.AuthCallbackResponse{
 		Token: jwtToken,
@@ -93,9 +95,9 @@ func (s *AuthService) HandleCallback(code string) (*models.AuthCallbackResponse,
 	}, nil
 }
 
-func (s *AuthService) getGoogleUserInfo(accessToken string) (*models.GoogleUserInfo, error) {
+func (s *AuthService) getGoogleUserInfo(ctx context.Context, accessToken string) (*models.GoogleUserInfo, error) {
 	client := &http.Client{}
-	req, err := http.NewRequest("GET", "https://www.googleapis.com/oauth2/v2/userinfo", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://www.googleapis.com/oauth2/v2/userinfo", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -115,58 +117,73 @@ func (s *AuthService) getGoogleUserInfo(accessToken string) (*models.GoogleUserI
 	return &userInfo, nil
 }
 
-func (s *AuthService) findOrCreateUser(googleUser *models.GoogleUserInfo) (*models.User, error) {
-	// Try to find existing user
-	var user models.User
-	query := `SELECT id, google_id, email, name, picture, created_at, updated_at 
-			  FROM users WHERE google_id = $1`
+func (s *AuthService) findOrCreateUser(ctx context.Context, googleUser *models.GoogleUserInfo) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
 
-	err := s.db.QueryRow(query, googleUser.ID).Scan(
-		&user.ID, &user.GoogleID, &user.Email, &user.Name,
-		&user.Picture, &user.CreatedAt, &user.UpdatedAt,
-	)
+	// ADMIN_EMAILS only applies to addresses Google has verified
+	listed := googleUser.VerifiedEmail && s.config.IsAdminEmail(googleUser.Email)
 
+	// Try to find existing user
+	user, err := s.users.GetByGoogleID(ctx, googleUser.ID)
 	if err == nil {
+		// Promote users listed in ADMIN_EMAILS and demote those it promoted
+		// once they are no longer listed; admins granted the role otherwise
+		// keep it
+		role, adminByEmail := user.Role, user.AdminByEmail
+		demoted := false
+		switch {
+		case listed && role != models.RoleAdmin:
+			role, adminByEmail = models.RoleAdmin, true
+		case !listed && adminByEmail:
+			role, adminByEmail, demoted = models.RoleUser, false, true
+		}
+
 		// User exists, update if needed
-		if user.Name != googleUser.Name || user.Picture != googleUser.Picture {
-			updateQuery := `UPDATE users SET name = $1, picture = $2, updated_at = CURRENT_TIMESTAMP 
-						   WHERE id = $3 RETURNING updated_at`
-			s.db.QueryRow(updateQuery, googleUser.Name, googleUser.Picture, user.ID).Scan(&user.UpdatedAt)
+		if user.Name != googleUser.Name || user.Picture != googleUser.Picture || user.Role != role || user.AdminByEmail != adminByEmail {
+			user.Name = googleUser.Name
+			user.Picture = googleUser.Picture
+			user.Role = role
+			user.AdminByEmail = adminByEmail
+			if err := s.users.UpdateProfile(ctx, user); err != nil {
+				// Signing in would leave a revoked admin role in place
+				if demoted {
+					return nil, fmt.Errorf("failed to revoke admin role: %w", err)
+				}
+				s.logger.WithContext(ctx).Warnf("Failed to update user profile: %v", err)
+			}
 		}
-		return &user, nil
+		return user, nil
 	}
 
-	if err != sql.ErrNoRows {
+	if !errors.Is(err, repository.ErrNotFound) {
 		return nil, err
 	}
 
 	// Create new user
-	user = models.User{
+	user = &models.User{
 		ID:        uuid.New(),
 		GoogleID:  googleUser.ID,
 		Email:     googleUser.Email,
 		Name:      googleUser.Name,
 		Picture:   googleUser.Picture,
+		Role:      models.RoleUser,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+	if listed {
+		user.Role = models.RoleAdmin
+		user.AdminByEmail = true
+	}
 
-	insertQuery := `INSERT INTO users (id, google_id, email, name, picture, created_at, updated_at) 
-					VALUES ($1, $2, $3, $4, $5, $6, $7)`
-
-	_, err = s.db.Exec(insertQuery, user.ID, user.GoogleID, user.Email, user.Name,
-		user.Picture, user.CreatedAt, user.UpdatedAt)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
+	if err := s.users.Create(ctx, user); err != nil {
+		return nil, err
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 func (s *AuthService) generateJWT(userID string) (string, error) {
-	s.logger.Infof("Generating JWT for user: %s", userID)
-	s.logger.Infof("Using JWT secret: %s...", s.config.JWTSecret[:10]+"...")
-
 	claims := jwt.RegisteredClaims{
 		Subject:   userID,
 		ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
@@ -176,17 +193,14 @@ func (s *AuthService) generateJWT(userID string) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString([]byte(s.config.JWTSecret))
 	if err != nil {
-		s.logger.Errorf("Failed to sign JWT: %v", err)
 		return "", err
 	}
 
-	s.logger.Infof("JWT generated successfully: %s...", tokenString[:20]+"...")
 	return tokenString, nil
 }
 
-func (s *AuthService) ValidateToken(tokenString string) (string, error) {
-	s.logger.Infof("Validating token: %s...", tokenString[:10]+"...")
-	s.logger.Infof("Using JWT secret: %s...", s.config.JWTSecret[:10]+"...")
+func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (string, error) {
+	logger := s.logger.WithContext(ctx)
 
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -196,43 +210,49 @@ func (s *AuthService) ValidateToken(tokenString string) (string, error) {
 	})
 
 	if err != nil {
-		s.logger.Errorf("Token parsing failed: %v", err)
+		logger.Debugf("Token parsing failed: %v", err)
 		return "", fmt.Errorf("failed to parse token: %w", err)
 	}
 
 	if !token.Valid {
-		s.logger.Errorf("Token validation failed: token not valid")
+		logger.Debug("Token validation failed: token not valid")
 		return "", fmt.Errorf("invalid token")
 	}
 
 	// Extract claims from the token
-	if claims, ok := token.Claims.(jwt.MapClaims); ok {
-		if subject, exists := claims["sub"]; exists {
-			s.logger.Infof("Token validation successful for user: %s", subject)
-			return subject.(string), nil
-		} else {
-			s.logger.Errorf("Token validation failed: subject claim not found")
-			return "", fmt.Errorf("subject claim not found")
-		}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		logger.Debug("Token validation failed: claims not found")
+		return "", fmt.Errorf("invalid token")
+	}
+	subject, ok := claims["sub"].(string)
+	if !ok {
+		logger.Debug("Token validation failed: subject claim not found")
+		return "", fmt.Errorf("subject claim not found")
 	}
 
-	s.logger.Errorf("Token validation failed: claims not found")
-	return "", fmt.Errorf("invalid token")
-}
-
-func (s *AuthService) GetUserByID(userID string) (*models.User, error) {
-	var user models.User
-	query := `SELECT id, google_id, email, name, picture, created_at, updated_at 
-			  FROM users WHERE id = $1`
+	// Tokens issued before an account was erased must stop working
+	user, err := s.GetUserByID(ctx, subject)
+	if err != nil {
+		logger.Debugf("Token validation failed: %v", err)
+		return "", fmt.Errorf("unknown token subject: %w", err)
+	}
+	if user.DeletedAt != nil {
+		logger.Debug("Token validation failed: account deleted")
+		return "", fmt.Errorf("account deleted")
+	}
 
-	err := s.db.QueryRow(query, userID).Scan(
-		&user.ID, &user.GoogleID, &user.Email, &user.Name,
-		&user.Picture, &user.CreatedAt, &user.UpdatedAt,
-	)
+	return subject, nil
+}
 
+func (s *AuthService) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
+	userUUID, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 
-	return &user, nil
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
+
+	return s.users.GetByID(ctx, userUUID)
 }
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"fact-check/internal/config"
+	"fact-check/internal/models"
+	"fact-check/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestAdminEmails(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.AdminEmails = []string{"admin@example.com"}
+	users := repository.NewMemoryUserRepository()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	auth := NewAuthService(cfg, users, logger)
+	ctx := context.Background()
+
+	signIn := func(googleID, email string, verified bool) *models.User {
+		t.Helper()
+		user, err := auth.findOrCreateUser(ctx, &models.GoogleUserInfo{ID: googleID, Email: email, VerifiedEmail: verified, Name: "Name"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return user
+	}
+
+	// Unverified addresses are not trusted
+	if user := signIn("unverified", "admin@example.com", false); user.Role != models.RoleUser {
+		t.Fatalf("expected an unverified address not to be promoted, got %s", user.Role)
+	}
+
+	// The current Google address counts, not the one stored at sign-up
+	user := signIn("renamed", "old@example.com", true)
+	if user = signIn("renamed", "Admin@Example.com", true); user.Role != models.RoleAdmin {
+		t.Fatalf("expected the listed address promoted, got %s", user.Role)
+	}
+
+	// Removing the address from the list takes the role back
+	cfg.AdminEmails = nil
+	if user = signIn("renamed", "admin@example.com", true); user.Role != models.RoleUser {
+		t.Fatalf("expected the unlisted admin demoted, got %s", user.Role)
+	}
+	if stored, _ := users.GetByID(ctx, user.ID); stored.Role != models.RoleUser || stored.AdminByEmail {
+		t.Fatalf("expected the demotion stored, got %+v", stored)
+	}
+
+	// Admins granted the role some other way keep it
+	other := signIn("granted", "granted@example.com", true)
+	other.Role = models.RoleAdmin
+	if err := users.UpdateProfile(ctx, other); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other = signIn("granted", "granted@example.com", true); other.Role != models.RoleAdmin {
+		t.Fatalf("expected an admin not promoted by ADMIN_EMAILS to stay admin, got %s", other.Role)
+	}
+}
@@ -0,0 +1,405 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"fact-check/internal/config"
+	"fact-check/internal/models"
+	"fact-check/internal/moderation"
+	"fact-check/internal/repository"
+	"fact-check/internal/tracing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Batch upload formats
+const (
+	BatchFormatJSON  = "json"
+	BatchFormatJSONL = "jsonl"
+	BatchFormatCSV   = "csv"
+)
+
+// Limits applied to each batch row
+const (
+	maxContentLength = 10000
+	maxURLLength     = 500
+	maxTopicLength   = 50
+)
+
+var (
+	// ErrInvalidBatch wraps problems with the upload as a whole, such as a
+	// malformed file or too many rows
+	ErrInvalidBatch = errors.New("invalid batch")
+	// ErrBatchNotFound is returned for unknown batches and other users' batches
+	ErrBatchNotFound = errors.New("batch not found")
+)
+
+// BatchService submits many news items at once and queues their verification
+type BatchService struct {
+	config *config.Config
+	jobs   repository.VerificationJobRepository
+	queue  *VerificationQueue
+	news   *NewsService
+	events EventPublisher
+	logger *logrus.Logger
+}
+
+// NewBatchService creates the service; events may be nil. Rows are screened
+// by the news service's moderation.
+func NewBatchService(cfg *config.Config, jobs repository.VerificationJobRepository, queue *VerificationQueue, news *NewsService, events EventPublisher, logger *logrus.Logger) *BatchService {
+	return &BatchService{
+		config: cfg,
+		jobs:   jobs,
+		queue:  queue,
+		news:   news,
+		events: events,
+		logger: logger,
+	}
+}
+
+// ParseBatch reads submissions in the given format. JSON accepts an array of
+// submissions or {"items": [...]}; JSONL one submission per line; CSV needs a
+// header row with a content column and optional link, photo_url, topic and
+// publish columns.
+// Rows are numbered from 1, not counting blank lines or the CSV header. Rows
+// that cannot be decoded are returned with Err set.
+func (s *BatchService) ParseBatch(format string, r io.Reader) ([]models.BatchRow, error) {
+	var rows []models.BatchRow
+	var err error
+	switch format {
+	case BatchFormatJSON:
+		rows, err = parseJSONBatch(r)
+	case BatchFormatJSONL:
+		rows, err = parseJSONLBatch(r)
+	case BatchFormatCSV:
+		rows, err = parseCSVBatch(r)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidBatch, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBatch, err)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows", ErrInvalidBatch)
+	}
+	if max := s.config.Queue.MaxBatchRows; len(rows) > max {
+		return nil, fmt.Errorf("%w: %d rows exceed the limit of %d", ErrInvalidBatch, len(rows), max)
+	}
+	return rows, nil
+}
+
+func parseJSONBatch(r io.Reader) ([]models.BatchRow, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		var wrapped struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		if wrappedErr := json.Unmarshal(raw, &wrapped); wrappedErr != nil || wrapped.Items == nil {
+			return nil, fmt.Errorf("expected a JSON array of submissions: %v", err)
+		}
+		items = wrapped.Items
+	}
+
+	rows := make([]models.BatchRow, 0, len(items))
+	for i, item := range items {
+		rows = append(rows, decodeJSONRow(i+1, item))
+	}
+	return rows, nil
+}
+
+func parseJSONLBatch(r io.Reader) ([]models.BatchRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var rows []models.BatchRow
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		rows = append(rows, decodeJSONRow(len(rows)+1, []byte(line)))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	return rows, nil
+}
+
+func decodeJSONRow(row int, raw []byte) models.BatchRow {
+	var submission models.NewsSubmission
+	if err := json.Unmarshal(raw, &submission); err != nil {
+		return models.BatchRow{Row: row, Err: fmt.Errorf("invalid JSON object: %v", err)}
+	}
+	return models.BatchRow{Row: row, Submission: submission}
+}
+
+func parseCSVBatch(r io.Reader) ([]models.BatchRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["content"]; !ok {
+		return nil, fmt.Errorf("CSV header must include a content column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	optional := func(value string) *string {
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+
+	var rows []models.BatchRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		row := len(rows) + 1
+		if err != nil {
+			// A malformed record leaves the reader in an unknown position
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		batchRow := models.BatchRow{Row: row, Submission: models.NewsSubmission{
+			Content:  field(record, "content"),
+			Link:     optional(field(record, "link")),
+			PhotoURL: optional(field(record, "photo_url")),
+			Topic:    optional(field(record, "topic")),
+		}}
+		if publish := field(record, "publish"); publish != "" {
+			if batchRow.Submission.Publish, err = strconv.ParseBool(publish); err != nil {
+				batchRow.Err = fmt.Errorf("publish must be true or false, got %q", publish)
+			}
+		}
+		rows = append(rows, batchRow)
+	}
+	return rows, nil
+}
+
+// validateSubmission normalizes a batch row and reports why it is unusable
+func validateSubmission(submission *models.NewsSubmission) error {
+	submission.Content = strings.TrimSpace(submission.Content)
+	if submission.Content == "" {
+		return errors.New("content is required")
+	}
+	if utf8.RuneCountInString(submission.Content) > maxContentLength {
+		return fmt.Errorf("content exceeds %d characters", maxContentLength)
+	}
+
+	submission.Topic = normalizeTopic(submission.Topic)
+	if submission.Topic != nil && utf8.RuneCountInString(*submission.Topic) > maxTopicLength {
+		return fmt.Errorf("topic exceeds %d characters", maxTopicLength)
+	}
+
+	for name, value := range map[string]**string{"link": &submission.Link, "photo_url": &submission.PhotoURL} {
+		if *value == nil {
+			continue
+		}
+		trimmed := strings.TrimSpace(**value)
+		if trimmed == "" {
+			*value = nil
+			continue
+		}
+		if len(trimmed) > maxURLLength {
+			return fmt.Errorf("%s exceeds %d characters", name, maxURLLength)
+		}
+		parsed, err := url.ParseRequestURI(trimmed)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%s must be an http or https URL", name)
+		}
+		*value = &trimmed
+	}
+	return nil
+}
+
+// Submit validates and moderates the rows and stores every valid one as a
+// news item with a queued verification, grouped under a new batch. Invalid
+// and rejected rows are reported in the result; quarantined rows are stored
+// without a verification. Rows awaiting LLM moderation are stored as pending
+// and announced once the queue has classified them. No batch is created
+// when no row is valid.
+func (s *BatchService) Submit(ctx context.Context, userID string, rows []models.BatchRow) (*models.BatchSubmissionResult, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "BatchService.Submit")
+	defer span.End()
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	now := time.Now()
+	batchID := uuid.New()
+	result := &models.BatchSubmissionResult{Items: []models.BatchItem{}}
+	var newsItems []*models.News
+	var jobs []*models.VerificationJob
+
+	for _, row := range rows {
+		err := row.Err
+		if err == nil {
+			err = validateSubmission(&row.Submission)
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, models.BatchRowError{Row: row.Row, Error: err.Error()})
+			continue
+		}
+
+		// Only the rules run here; the LLM classifies the rows they allow
+		// in the queue so the upload does not wait on hundreds of calls
+		decision, deferred := s.news.ModerateRules(ctx, userID, &row.Submission)
+		if decision.Action == moderation.ActionReject {
+			rejected := &SubmissionRejectedError{Category: decision.Category, Reason: decision.Reason}
+			result.Errors = append(result.Errors, models.BatchRowError{Row: row.Row, Error: rejected.Error()})
+			continue
+		}
+
+		news := &models.News{
+			ID:      uuid.New(),
+			UserID:  userUUID,
+			Content: row.Submission.Content,
+			Link:    row.Submission.Link,
+			// Links are only cleaned up here; fetching hundreds of them
+			// would hold up the upload
+			CanonicalLink: cleanLink(row.Submission.Link),
+			PhotoURL:      row.Submission.PhotoURL,
+			Topic:         row.Submission.Topic,
+			Language:      detectLanguage(row.Submission.Content),
+			Status:        "pending",
+			Moderation:    models.ModerationAllowed,
+			Published:     row.Submission.Publish,
+			BatchID:       &batchID,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		newsItems = append(newsItems, news)
+		if decision.Action == moderation.ActionQuarantine {
+			news.Moderation = models.ModerationQuarantined
+			news.ModerationCategory = &decision.Category
+			news.ModerationReason = &decision.Reason
+			result.Quarantined++
+			result.Items = append(result.Items, models.BatchItem{Row: row.Row, NewsID: news.ID, Quarantined: true})
+			continue
+		}
+		if deferred {
+			news.Moderation = models.ModerationPending
+		}
+		job := &models.VerificationJob{
+			ID:        uuid.New(),
+			NewsID:    news.ID,
+			UserID:    userUUID,
+			BatchID:   &batchID,
+			Status:    models.JobQueued,
+			CreatedAt: now,
+		}
+		jobs = append(jobs, job)
+		result.Items = append(result.Items, models.BatchItem{Row: row.Row, NewsID: news.ID, JobID: &job.ID})
+	}
+
+	result.Accepted = len(newsItems)
+	result.Rejected = len(result.Errors)
+	if result.Accepted == 0 {
+		return result, nil
+	}
+
+	batch := &models.Batch{ID: batchID, UserID: userUUID, Total: len(jobs), CreatedAt: now}
+	if err := s.jobs.CreateBatch(ctx, batch, newsItems, jobs); err != nil {
+		return nil, err
+	}
+	result.BatchID = &batchID
+
+	s.logger.WithContext(ctx).Infof("Batch %s submitted: %d accepted, %d quarantined, %d rejected", batchID, result.Accepted, result.Quarantined, result.Rejected)
+
+	if s.events != nil {
+		for _, news := range newsItems {
+			if news.Moderation != models.ModerationAllowed {
+				continue
+			}
+			publishNewsEvent(ctx, s.events, s.logger, models.EventNewsSubmitted, news, "")
+		}
+	}
+	s.queue.Notify()
+
+	return result, nil
+}
+
+// Progress reports how far the verification of a batch has got
+func (s *BatchService) Progress(ctx context.Context, userID string, batchID uuid.UUID) (*models.BatchProgress, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "BatchService.Progress")
+	defer span.End()
+
+	batch, err := s.jobs.GetBatch(ctx, batchID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBatchNotFound
+		}
+		return nil, err
+	}
+	if batch.UserID.String() != userID {
+		return nil, ErrBatchNotFound
+	}
+
+	jobs, err := s.jobs.ListBatchJobs(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &models.BatchProgress{
+		BatchID:   batch.ID,
+		Total:     batch.Total,
+		Verdicts:  make(map[string]int),
+		Jobs:      jobs,
+		CreatedAt: batch.CreatedAt,
+	}
+	for _, job := range jobs {
+		switch job.Status {
+		case models.JobQueued:
+			progress.Queued++
+		case models.JobRunning:
+			progress.Running++
+		case models.JobSucceeded:
+			progress.Succeeded++
+		case models.JobFailed:
+			progress.Failed++
+		}
+		if job.Verdict != nil {
+			progress.Verdicts[*job.Verdict]++
+		}
+	}
+	progress.Completed = progress.Queued == 0 && progress.Running == 0
+
+	return progress, nil
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"fact-check/internal/calibration"
+	"fact-check/internal/config"
+	"fact-check/internal/metrics"
+	"fact-check/internal/models"
+	"fact-check/internal/repository"
+	"fact-check/internal/tracing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrNotEnoughOutcomes is returned when too few verdicts have been reviewed
+// to fit a calibration
+var ErrNotEnoughOutcomes = errors.New("not enough reviewed verdicts to fit a calibration")
+
+// CalibrationService maps the confidence models state onto calibrated
+// confidences fitted from reviewed verdicts, and decides which verdicts are
+// too uncertain to publish as true or false
+type CalibrationService struct {
+	config       *config.Config
+	calibrations repository.CalibrationRepository
+	current      atomic.Pointer[fittedCalibration]
+	abstention   atomic.Pointer[config.AbstentionConfig]
+	logger       *logrus.Logger
+}
+
+// fittedCalibration pairs a stored calibration with the calibrator it decodes to
+type fittedCalibration struct {
+	record     *models.Calibration
+	calibrator calibration.Calibrator
+}
+
+// NewCalibrationService creates the service. Confidences are used as stated
+// until a calibration is loaded or fitted.
+func NewCalibrationService(cfg *config.Config, calibrations repository.CalibrationRepository, logger *logrus.Logger) *CalibrationService {
+	service := &CalibrationService{
+		config:       cfg,
+		calibrations: calibrations,
+		logger:       logger,
+	}
+	service.current.Store(&fittedCalibration{calibrator: calibration.Identity{}})
+	service.ApplyConfig(cfg)
+	return service
+}
+
+// ApplyConfig switches the abstention thresholds used from now on
+func (s *CalibrationService) ApplyConfig(cfg *config.Config) {
+	abstention := cfg.Abstention
+	s.abstention.Store(&abstention)
+}
+
+// Current returns the calibration in use, or nil when confidences are used
+// as stated
+func (s *CalibrationService) Current() *models.Calibration {
+	return s.current.Load().record
+}
+
+// Assess returns the calibrated confidence of a verdict and whether it is
+// too low for the topic's abstention threshold. Only true and false verdicts
+// abstain; a verdict without a stated confidence counts as zero.
+func (s *CalibrationService) Assess(status string, confidence float64, topic string) (float64, bool) {
+	calibrated := s.current.Load().calibrator.Calibrate(confidence)
+	if status != "true" && status != "false" {
+		return calibrated, false
+	}
+	threshold := s.abstention.Load().ThresholdFor(topic)
+	return calibrated, threshold > 0 && calibrated < threshold
+}
+
+// Load switches to the most recently fitted calibration, if there is one
+func (s *CalibrationService) Load(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
+
+	record, err := s.calibrations.Latest(ctx)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	calibrator, err := calibration.Decode(record.Method, record.Params)
+	if err != nil {
+		return err
+	}
+	s.current.Store(&fittedCalibration{record: record, calibrator: calibrator})
+	return nil
+}
+
+// Fit fits a calibration to the most recently reviewed verdicts with the
+// configured method, stores it and uses it from now on
+func (s *CalibrationService) Fit(ctx context.Context) (*models.Calibration, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "CalibrationService.Fit")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
+
+	outcomes, err := s.calibrations.ListOutcomes(ctx, s.config.Calibration.MaxSamples)
+	if err != nil {
+		return nil, err
+	}
+	if len(outcomes) < s.config.Calibration.MinSamples {
+		return nil, ErrNotEnoughOutcomes
+	}
+
+	samples := make([]calibration.Sample, len(outcomes))
+	for i, outcome := range outcomes {
+		samples[i] = calibration.Sample{Score: outcome.Confidence, Correct: outcome.Correct}
+	}
+	method := s.config.Calibration.Method
+	calibrator, err := calibration.Fit(method, samples)
+	if err != nil {
+		if errors.Is(err, calibration.ErrNoSamples) {
+			return nil, ErrNotEnoughOutcomes
+		}
+		return nil, err
+	}
+	params, err := json.Marshal(calibrator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode calibration: %w", err)
+	}
+
+	record := &models.Calibration{
+		Method:      method,
+		Params:      params,
+		Samples:     len(samples),
+		BrierBefore: calibration.Brier(calibration.Identity{}, samples),
+		BrierAfter:  calibration.Brier(calibrator, samples),
+		CreatedAt:   time.Now(),
+	}
+	if err := s.calibrations.Create(ctx, record); err != nil {
+		return nil, err
+	}
+	s.current.Store(&fittedCalibration{record: record, calibrator: calibrator})
+	metrics.CalibrationBrier.WithLabelValues("stated").Set(record.BrierBefore)
+	metrics.CalibrationBrier.WithLabelValues("calibrated").Set(record.BrierAfter)
+
+	s.logger.WithContext(ctx).Infof("Fitted %s calibration on %d reviewed verdicts: Brier score %.4f -> %.4f",
+		method, record.Samples, record.BrierBefore, record.BrierAfter)
+	return record, nil
+}
+
+// Run refits the calibration every calibration interval until ctx is done
+func (s *CalibrationService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Calibration.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := s.Fit(ctx); err != nil {
+			if errors.Is(err, ErrNotEnoughOutcomes) {
+				s.logger.WithContext(ctx).Info("Skipping calibration: not enough reviewed verdicts")
+				continue
+			}
+			s.logger.WithContext(ctx).Errorf("Failed to fit calibration: %v", err)
+		}
+	}
+}
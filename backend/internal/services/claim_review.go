@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"fact-check/internal/models"
+)
+
+// ErrNewsNotVerified is returned when a claim review is requested for news
+// that has no verdict yet or whose verdict is not public
+var ErrNewsNotVerified = errors.New("news has not been verified")
+
+// verdictRatings maps our verdicts onto a 1 (false) to 5 (true) rating scale
+var verdictRatings = map[string]models.ReviewRating{
+	"false":        {RatingValue: 1, AlternateName: "False"},
+	"uncertain":    {RatingValue: 3, AlternateName: "Unproven"},
+	"unverifiable": {RatingValue: 3, AlternateName: "Unverifiable"},
+	"true":         {RatingValue: 5, AlternateName: "True"},
+}
+
+// VerdictLabel returns the human-readable name of a verdict and whether the
+// status is a published verdict at all
+func VerdictLabel(status string) (string, bool) {
+	rating, ok := verdictRatings[status]
+	return rating.AlternateName, ok
+}
+
+// publiclyVisible applies the rule of the public feeds: the submitter
+// published the news, moderation allowed it and no reviewer still has to
+// look at its verdict
+func publiclyVisible(news *models.News) bool {
+	return news.Published && news.Moderation == models.ModerationAllowed && !news.NeedsReview
+}
+
+// ClaimReview returns the schema.org ClaimReview document for a verified
+// news item the public feeds would list
+func (s *NewsService) ClaimReview(ctx context.Context, newsID string) (*models.ClaimReview, error) {
+	news, err := s.GetNewsByID(ctx, newsID)
+	if err != nil {
+		return nil, err
+	}
+
+	rating, ok := verdictRatings[news.Status]
+	if !ok || !publiclyVisible(news) {
+		return nil, ErrNewsNotVerified
+	}
+	rating.Type = "Rating"
+	rating.BestRating = 5
+	rating.WorstRating = 1
+
+	review := &models.ClaimReview{
+		Context:       "https://schema.org",
+		Type:          "ClaimReview",
+		ClaimReviewed: strings.TrimSpace(news.Content),
+		DatePublished: news.UpdatedAt.UTC().Format("2006-01-02"),
+		Author:        models.SchemaOrgEntity{Type: "Organization", Name: s.config.Publisher.Name, URL: s.config.Publisher.URL},
+		ReviewRating:  rating,
+		ItemReviewed:  models.ReviewedClaim{Type: "Claim"},
+	}
+	if s.config.Publisher.URL != "" {
+		review.URL = strings.TrimRight(s.config.Publisher.URL, "/") + "/api/v1/news/" + news.ID.String() + "/claim-review"
+	}
+	if news.Explanation != nil {
+		review.ReviewBody = strings.TrimSpace(*news.Explanation)
+	}
+
+	// The claim is attributed to the site it was found on, which is the one
+	// that served the page when the link was fetched
+	link := news.Link
+	if news.CanonicalLink != nil {
+		link = news.CanonicalLink
+	}
+	if link != nil && *link != "" {
+		review.ItemReviewed.URL = *link
+		review.ItemReviewed.Appearance = []models.SchemaOrgEntity{{Type: "CreativeWork", URL: *link}}
+		if source, err := url.Parse(*link); err == nil && source.Host != "" {
+			if news.FetchedHost != nil && *news.FetchedHost != "" {
+				source.Host = *news.FetchedHost
+			}
+			review.ItemReviewed.Author = &models.SchemaOrgEntity{
+				Type: "Organization",
+				Name: source.Hostname(),
+				URL:  source.Scheme + "://" + source.Host,
+			}
+		}
+	}
+
+	return review, nil
+}
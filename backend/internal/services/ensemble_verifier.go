@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"fact-check/internal/config"
+	"fact-check/internal/models"
+	"fact-check/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// EnsembleMember is one model taking part in ensemble verification
+type EnsembleMember struct {
+	Model    string
+	Verifier Verifier
+}
+
+// EnsembleVerifier asks several models in parallel and combines their
+// verdicts by vote. Disagreeing models flag the result for human review.
+type EnsembleVerifier struct {
+	members []EnsembleMember
+	voting  string
+}
+
+// NewEnsembleVerifier creates the verifier; voting is one of the
+// config.Voting* strategies
+func NewEnsembleVerifier(members []EnsembleMember, voting string) *EnsembleVerifier {
+	return &EnsembleVerifier{members: members, voting: voting}
+}
+
+// VerifyNews returns the combined verdict with each member's answer in
+// Votes. It only fails when no member answered.
+func (e *EnsembleVerifier) VerifyNews(ctx context.Context, request *VerificationRequest) (*VerificationResult, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "EnsembleVerifier.VerifyNews")
+	defer span.End()
+
+	results := make([]*VerificationResult, len(e.members))
+	errs := make([]error, len(e.members))
+	var wg sync.WaitGroup
+	for i, member := range e.members {
+		wg.Add(1)
+		go func(i int, member EnsembleMember) {
+			defer wg.Done()
+			results[i], errs[i] = member.Verifier.VerifyNews(ctx, request)
+		}(i, member)
+	}
+	wg.Wait()
+
+	combined := &VerificationResult{}
+	var answered []*VerificationResult
+	var failures []error
+	names := make([]string, len(e.members))
+	for i, member := range e.members {
+		names[i] = member.Model
+		vote := models.ModelVerdict{Model: member.Model}
+		if errs[i] != nil {
+			vote.Error = errs[i].Error()
+			failures = append(failures, fmt.Errorf("%s: %w", member.Model, errs[i]))
+		} else {
+			result := results[i]
+			vote.Status = result.Status
+			vote.Confidence = result.Confidence
+			vote.Explanation = result.Explanation
+			answered = append(answered, result)
+			combined.Members = append(combined.Members, result)
+			combined.Usage.PromptTokens += result.Usage.PromptTokens
+			combined.Usage.CompletionTokens += result.Usage.CompletionTokens
+			combined.Usage.TotalTokens += result.Usage.TotalTokens
+		}
+		combined.Votes = append(combined.Votes, vote)
+	}
+	combined.Model = strings.Join(names, "+")
+
+	if len(answered) == 0 {
+		return nil, fmt.Errorf("no ensemble model answered: %w", errors.Join(failures...))
+	}
+
+	status, winners := tally(e.voting, answered)
+	combined.Status = status
+	combined.Disagreement = len(winners) < len(answered)
+	// A single answer cannot confirm itself
+	combined.NeedsReview = combined.Disagreement || len(answered) < 2
+
+	var confidence float64
+	var best *VerificationResult
+	for _, result := range winners {
+		confidence += result.Confidence
+		if best == nil || result.Confidence > best.Confidence {
+			best = result
+		}
+	}
+	combined.Confidence = confidence / float64(len(answered))
+	if best != nil {
+		combined.Explanation = best.Explanation
+	} else {
+		combined.Explanation = disagreementExplanation(combined.Votes)
+	}
+
+	span.SetAttributes(
+		attribute.String("ensemble.voting", e.voting),
+		attribute.Int("ensemble.members", len(e.members)),
+		attribute.Int("ensemble.answered", len(answered)),
+		attribute.String("ensemble.verdict", status),
+		attribute.Bool("ensemble.disagreement", combined.Disagreement),
+	)
+	return combined, nil
+}
+
+// tally combines answers with the given strategy and returns the verdict and
+// the answers agreeing with it. Ties and failed unanimity are "uncertain"
+// with no winners unless the models said uncertain themselves.
+func tally(voting string, answered []*VerificationResult) (string, []*VerificationResult) {
+	byStatus := make(map[string][]*VerificationResult)
+	for _, result := range answered {
+		byStatus[result.Status] = append(byStatus[result.Status], result)
+	}
+
+	if voting == config.VotingUnanimous {
+		if len(byStatus) == 1 {
+			return answered[0].Status, answered
+		}
+		return "uncertain", byStatus["uncertain"]
+	}
+
+	scores := make(map[string]float64, len(byStatus))
+	for status, results := range byStatus {
+		for _, result := range results {
+			if voting == config.VotingWeighted {
+				scores[status] += voteWeight(result.Confidence)
+			} else {
+				scores[status]++
+			}
+		}
+	}
+
+	statuses := make([]string, 0, len(scores))
+	for status := range scores {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return scores[statuses[i]] > scores[statuses[j]]
+	})
+	if len(statuses) > 1 && scores[statuses[0]] == scores[statuses[1]] {
+		return "uncertain", byStatus["uncertain"]
+	}
+	return statuses[0], byStatus[statuses[0]]
+}
+
+// voteWeight is the weight of an answer in weighted voting. Answers without
+// a stated confidence count as a coin flip.
+func voteWeight(confidence float64) float64 {
+	if confidence <= 0 {
+		return 0.5
+	}
+	return confidence
+}
+
+// disagreementExplanation summarizes the answers when no model's
+// explanation supports the combined verdict
+func disagreementExplanation(votes []models.ModelVerdict) string {
+	var b strings.Builder
+	b.WriteString("UNCERTAIN: the models disagreed and this claim needs human review.")
+	for _, vote := range votes {
+		if vote.Error != "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\n\n%s (%s): %s", vote.Model, strings.ToUpper(vote.Status), strings.TrimSpace(vote.Explanation))
+	}
+	return b.String()
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"fact-check/internal/config"
+	"fact-check/internal/models"
+)
+
+type fixedVerifier struct {
+	status     string
+	confidence float64
+	err        error
+}
+
+func (v fixedVerifier) VerifyNews(ctx context.Context, request *VerificationRequest) (*VerificationResult, error) {
+	if v.err != nil {
+		return nil, v.err
+	}
+	return &VerificationResult{
+		Status:      v.status,
+		Explanation: strings.ToUpper(v.status) + ": checked",
+		Confidence:  v.confidence,
+		Model:       "model-" + v.status,
+		Usage:       models.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}, nil
+}
+
+func ensemble(voting string, verifiers ...fixedVerifier) *EnsembleVerifier {
+	members := make([]EnsembleMember, len(verifiers))
+	for i, verifier := range verifiers {
+		members[i] = EnsembleMember{Model: "m" + string(rune('a'+i)), Verifier: verifier}
+	}
+	return NewEnsembleVerifier(members, voting)
+}
+
+func TestEnsembleVoting(t *testing.T) {
+	tests := []struct {
+		name         string
+		verifier     *EnsembleVerifier
+		status       string
+		disagreement bool
+	}{
+		{
+			name:     "unanimous agreement",
+			verifier: ensemble(config.VotingUnanimous, fixedVerifier{status: "false"}, fixedVerifier{status: "false"}),
+			status:   "false",
+		},
+		{
+			name:         "unanimous disagreement",
+			verifier:     ensemble(config.VotingUnanimous, fixedVerifier{status: "false"}, fixedVerifier{status: "false"}, fixedVerifier{status: "true"}),
+			status:       "uncertain",
+			disagreement: true,
+		},
+		{
+			name:         "majority",
+			verifier:     ensemble(config.VotingMajority, fixedVerifier{status: "false"}, fixedVerifier{status: "false"}, fixedVerifier{status: "true"}),
+			status:       "false",
+			disagreement: true,
+		},
+		{
+			name:         "majority tie",
+			verifier:     ensemble(config.VotingMajority, fixedVerifier{status: "false"}, fixedVerifier{status: "true"}),
+			status:       "uncertain",
+			disagreement: true,
+		},
+		{
+			name: "weighted by confidence",
+			verifier: ensemble(config.VotingWeighted,
+				fixedVerifier{status: "false", confidence: 0.3},
+				fixedVerifier{status: "false", confidence: 0.3},
+				fixedVerifier{status: "true", confidence: 0.9}),
+			status:       "true",
+			disagreement: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := test.verifier.VerifyNews(context.Background(), &VerificationRequest{Content: "claim"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Status != test.status || result.Disagreement != test.disagreement || result.NeedsReview != test.disagreement {
+				t.Fatalf("expected %s (disagreement %v), got %+v", test.status, test.disagreement, result)
+			}
+			if len(result.Votes) != len(test.verifier.members) || len(result.Members) != len(test.verifier.members) {
+				t.Fatalf("expected every vote to be kept, got %+v", result.Votes)
+			}
+		})
+	}
+}
+
+func TestEnsembleToleratesFailedMembers(t *testing.T) {
+	verifier := ensemble(config.VotingMajority, fixedVerifier{status: "true", confidence: 0.8}, fixedVerifier{err: errors.New("quota exceeded")})
+	result, err := verifier.VerifyNews(context.Background(), &VerificationRequest{Content: "claim"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != "true" || result.Disagreement || !result.NeedsReview {
+		t.Fatalf("expected a flagged single-model verdict, got %+v", result)
+	}
+	if result.Votes[1].Error != "quota exceeded" || result.Model != "ma+mb" || result.Usage.TotalTokens != 15 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	verifier = ensemble(config.VotingMajority, fixedVerifier{err: errors.New("down")}, fixedVerifier{err: errors.New("down")})
+	if _, err := verifier.VerifyNews(context.Background(), &VerificationRequest{Content: "claim"}); err == nil {
+		t.Fatal("expected an error when no model answered")
+	}
+}
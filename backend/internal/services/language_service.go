@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"fact-check/internal/config"
+	"fact-check/internal/langdetect"
+	"fact-check/internal/models"
+	"fact-check/internal/redact"
+	"fact-check/internal/repository"
+	"fact-check/internal/tracing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrInvalidLocale is returned for locales not of the form "en" or "pt-BR"
+var ErrInvalidLocale = errors.New("invalid locale")
+
+// LanguageService decides which language a claim is verified and explained
+// in and keeps translations of news items
+type LanguageService struct {
+	config       config.LanguageConfig
+	users        repository.UserRepository
+	translations repository.TranslationRepository
+	news         *NewsService
+	translator   Translator
+	usage        *UsageService
+	redaction    *RedactionService
+	logger       *logrus.Logger
+}
+
+// NewLanguageService creates the service; redaction may be nil when texts
+// are always sent to the translator as written
+func NewLanguageService(cfg *config.Config, users repository.UserRepository, translations repository.TranslationRepository, news *NewsService, translator Translator, usage *UsageService, redaction *RedactionService, logger *logrus.Logger) *LanguageService {
+	return &LanguageService{
+		config:       cfg.Language,
+		users:        users,
+		translations: translations,
+		news:         news,
+		translator:   translator,
+		usage:        usage,
+		redaction:    redaction,
+		logger:       logger,
+	}
+}
+
+// SetLocale stores the user's preferred locale and returns it normalized.
+// An empty locale clears the preference.
+func (s *LanguageService) SetLocale(ctx context.Context, userID, locale string) (string, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return "", fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var stored *string
+	if locale != "" {
+		normalized, ok := langdetect.NormalizeLocale(locale)
+		if !ok {
+			return "", fmt.Errorf("%w: %q", ErrInvalidLocale, locale)
+		}
+		stored = &normalized
+	}
+
+	if err := s.users.SetLocale(ctx, userUUID, stored); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", ErrUserNotFound
+		}
+		return "", err
+	}
+	if stored == nil {
+		return "", nil
+	}
+	return *stored, nil
+}
+
+// PreferredLocale returns the locale explanations for the user are written
+// in: their preference, else the language of the claim, else the default
+func (s *LanguageService) PreferredLocale(ctx context.Context, userID, language string) string {
+	if userUUID, err := uuid.Parse(userID); err == nil {
+		user, err := s.users.GetByID(ctx, userUUID)
+		switch {
+		case err == nil && user.Locale != nil:
+			return *user.Locale
+		case err != nil && !errors.Is(err, repository.ErrNotFound):
+			s.logger.WithContext(ctx).Warnf("Failed to load preferred locale: %v", err)
+		}
+	}
+	if language != "" {
+		return language
+	}
+	return s.config.DefaultLocale
+}
+
+// Prepare fills in the language of a verification request and the locale to
+// explain in. In translate mode non-English claims also get an English
+// translation; if that fails the claim is verified in its own language.
+func (s *LanguageService) Prepare(ctx context.Context, userID string, news *models.News, request *VerificationRequest) {
+	ctx, span := tracing.Tracer().Start(ctx, "LanguageService.Prepare")
+	defer span.End()
+
+	language := newsLanguage(news)
+	request.Language = language
+	request.Locale = s.PreferredLocale(ctx, userID, language)
+
+	if s.config.Mode != config.LanguageModeTranslate || language == "" || langdetect.Base(language) == "en" {
+		return
+	}
+
+	stored, err := s.translations.Get(ctx, news.ID, "en")
+	if err == nil {
+		request.Translation = stored.Content
+		return
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		s.logger.WithContext(ctx).Warnf("Failed to load stored translation: %v", err)
+	}
+
+	result, err := s.translate(ctx, userID, news, news.Content, language, "en")
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Failed to translate news %s, verifying in %s: %v", news.ID, language, err)
+		return
+	}
+	request.Translation = result.Text
+
+	now := time.Now()
+	translation := &models.NewsTranslation{
+		ID:        uuid.New(),
+		NewsID:    news.ID,
+		Locale:    "en",
+		Content:   result.Text,
+		Model:     result.Model,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.translations.Upsert(ctx, translation); err != nil {
+		s.logger.WithContext(ctx).Errorf("Failed to store translation: %v", err)
+	}
+}
+
+// Translation returns the news item's content and explanation in locale,
+// translating and storing them when no up-to-date translation is stored.
+// Errors are ErrInvalidLocale, ErrNewsNotFound, *BudgetExceededError,
+// *VerifierError or a storage error.
+func (s *LanguageService) Translation(ctx context.Context, userID, newsID, locale string) (*models.NewsTranslation, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "LanguageService.Translation")
+	defer span.End()
+
+	locale, ok := langdetect.NormalizeLocale(locale)
+	if !ok {
+		return nil, ErrInvalidLocale
+	}
+
+	news, err := s.news.GetNewsByID(ctx, newsID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A translation is stale once the verdict changed after it was made
+	stored, err := s.translations.Get(ctx, news.ID, locale)
+	switch {
+	case err == nil && !stored.UpdatedAt.Before(news.UpdatedAt):
+		return stored, nil
+	case err != nil && !errors.Is(err, repository.ErrNotFound):
+		return nil, err
+	}
+
+	if err := s.usage.CheckBudget(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	translation := &models.NewsTranslation{
+		ID:        uuid.New(),
+		NewsID:    news.ID,
+		Locale:    locale,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	language := newsLanguage(news)
+	switch {
+	case stored != nil:
+		// The content never changes, only the explanation
+		translation.Content = stored.Content
+		translation.Model = stored.Model
+	case sameLanguage(language, locale):
+		translation.Content = news.Content
+	default:
+		result, err := s.translate(ctx, userID, news, news.Content, language, locale)
+		if err != nil {
+			return nil, err
+		}
+		translation.Content = result.Text
+		translation.Model = result.Model
+	}
+
+	if news.Explanation != nil && *news.Explanation != "" {
+		explanation := *news.Explanation
+		if from := langdetect.Detect(explanation); !sameLanguage(from, locale) {
+			result, err := s.translate(ctx, userID, news, explanation, knownLanguage(from), locale)
+			if err != nil {
+				return nil, err
+			}
+			explanation = result.Text
+			translation.Model = result.Model
+		}
+		translation.Explanation = &explanation
+	}
+
+	if err := s.translations.Upsert(ctx, translation); err != nil {
+		return nil, err
+	}
+	return translation, nil
+}
+
+// translate calls the translator, with personal data redacted if the news
+// owner's policy asks for it, and records the tokens used against the user
+func (s *LanguageService) translate(ctx context.Context, userID string, news *models.News, text, from, to string) (*TranslationResult, error) {
+	var redaction *redact.Session
+	if s.redaction != nil {
+		redaction = s.redaction.Session(ctx, news.UserID)
+	}
+	if redaction != nil {
+		text = redaction.Redact(text)
+	}
+
+	result, err := s.translator.Translate(ctx, text, from, to)
+	if err != nil {
+		return nil, &VerifierError{Err: err}
+	}
+	if result.Usage.TotalTokens > 0 || result.Usage.PromptTokens > 0 {
+		if _, err := s.usage.RecordUsage(ctx, userID, &news.ID, result.Model, result.Usage); err != nil {
+			s.logger.WithContext(ctx).Errorf("Failed to record LLM usage: %v", err)
+		}
+	}
+	if redaction != nil && redaction.Count() > 0 {
+		restored := *result
+		restored.Text = redaction.Restore(result.Text)
+		result = &restored
+	}
+	return result, nil
+}
+
+// newsLanguage returns the detected language of the news, empty if unknown
+func newsLanguage(news *models.News) string {
+	if news.Language == nil {
+		return ""
+	}
+	return knownLanguage(*news.Language)
+}
+
+func knownLanguage(language string) string {
+	if language == langdetect.Undetermined {
+		return ""
+	}
+	return language
+}
+
+// sameLanguage reports whether text in language needs no translation for
+// locale. Regional variants count as the same language.
+func sameLanguage(language, locale string) bool {
+	return language != "" && langdetect.Base(language) == langdetect.Base(locale)
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync/atomic"
+
+	"fact-check/internal/config"
+	"fact-check/internal/metrics"
+	"fact-check/internal/moderation"
+	"fact-check/internal/tracing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ModerationService screens submissions before they are stored or verified
+type ModerationService struct {
+	moderator Moderator
+	usage     *UsageService
+	redaction *RedactionService
+	gate      atomic.Pointer[moderation.Gate]
+	// rules runs the rule classifier alone and llm the LLM classifier alone,
+	// for batch rows the LLM classifies in the queue; llm holds nil when the
+	// LLM is not asked
+	rules  atomic.Pointer[moderation.Gate]
+	llm    atomic.Pointer[moderation.Gate]
+	logger *logrus.Logger
+}
+
+// NewModerationService creates the service; moderator may be nil when no
+// LLM is available to classify submissions, and redaction when personal
+// data is sent to it as is
+func NewModerationService(cfg *config.Config, moderator Moderator, usage *UsageService, redaction *RedactionService, logger *logrus.Logger) *ModerationService {
+	service := &ModerationService{
+		moderator: moderator,
+		usage:     usage,
+		redaction: redaction,
+		logger:    logger,
+	}
+	service.ApplyConfig(cfg)
+	return service
+}
+
+// ApplyConfig rebuilds the classifiers used from now on
+func (s *ModerationService) ApplyConfig(cfg *config.Config) {
+	rules, err := moderationRules(cfg.Moderation)
+	if err != nil {
+		// Validate only lets compilable rules through
+		s.logger.Errorf("Failed to apply moderation rules, keeping the current ones: %v", err)
+		return
+	}
+
+	ruleClassifier := moderation.NewRuleClassifier(rules)
+	classifiers := []moderation.Classifier{ruleClassifier}
+	var llm *moderation.Gate
+	if cfg.Moderation.LLM && s.moderator != nil {
+		classifier := &llmClassifier{service: s}
+		classifiers = append(classifiers, classifier)
+		llm = moderation.NewGate(cfg.Moderation.OnError, classifier)
+	}
+	s.gate.Store(moderation.NewGate(cfg.Moderation.OnError, classifiers...))
+	s.rules.Store(moderation.NewGate(cfg.Moderation.OnError, ruleClassifier))
+	s.llm.Store(llm)
+}
+
+// Check classifies a submission with every classifier. Classifier failures
+// are logged and handled with the configured on_error action.
+func (s *ModerationService) Check(ctx context.Context, submission *moderation.Submission) moderation.Decision {
+	ctx, span := tracing.Tracer().Start(ctx, "ModerationService.Check")
+	defer span.End()
+
+	return s.check(ctx, s.gate.Load(), submission)
+}
+
+// CheckRules classifies a submission with the rules only. deferred reports
+// whether the LLM still has to classify it with CheckLLM.
+func (s *ModerationService) CheckRules(ctx context.Context, submission *moderation.Submission) (decision moderation.Decision, deferred bool) {
+	ctx, span := tracing.Tracer().Start(ctx, "ModerationService.CheckRules")
+	defer span.End()
+
+	return s.check(ctx, s.rules.Load(), submission), s.llm.Load() != nil
+}
+
+// CheckLLM classifies a submission the rules already allowed with the LLM,
+// allowing it when the LLM is no longer asked
+func (s *ModerationService) CheckLLM(ctx context.Context, submission *moderation.Submission) moderation.Decision {
+	ctx, span := tracing.Tracer().Start(ctx, "ModerationService.CheckLLM")
+	defer span.End()
+
+	gate := s.llm.Load()
+	if gate == nil {
+		return moderation.Allow
+	}
+	return s.check(ctx, gate, submission)
+}
+
+func (s *ModerationService) check(ctx context.Context, gate *moderation.Gate, submission *moderation.Submission) moderation.Decision {
+	decision, err := gate.Check(ctx, submission)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Moderation classifier failed, taking action %s: %v", decision.Action, err)
+	}
+	classifier := decision.Classifier
+	if classifier == "" {
+		classifier = "none"
+	}
+	metrics.SubmissionsModerated.WithLabelValues(decision.Action, classifier).Inc()
+	return decision
+}
+
+// moderationRules compiles the keyword lists and rules of the configuration
+func moderationRules(cfg config.ModerationConfig) ([]moderation.Rule, error) {
+	var rules []moderation.Rule
+	addKeywords := func(category, action string, keywords []string) error {
+		pattern, err := moderation.KeywordPattern(keywords)
+		if err != nil {
+			return fmt.Errorf("%s rule: %w", category, err)
+		}
+		rules = append(rules, moderation.Rule{Category: category, Action: action, Pattern: pattern})
+		return nil
+	}
+
+	if len(cfg.RejectKeywords) > 0 {
+		if err := addKeywords("blocked", moderation.ActionReject, cfg.RejectKeywords); err != nil {
+			return nil, err
+		}
+	}
+	if len(cfg.QuarantineKeywords) > 0 {
+		if err := addKeywords("flagged", moderation.ActionQuarantine, cfg.QuarantineKeywords); err != nil {
+			return nil, err
+		}
+	}
+	for _, rule := range cfg.Rules {
+		if len(rule.Keywords) > 0 {
+			if err := addKeywords(rule.Category, rule.Action, rule.Keywords); err != nil {
+				return nil, err
+			}
+		}
+		if rule.Pattern != "" {
+			pattern, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("%s rule: %w", rule.Category, err)
+			}
+			rules = append(rules, moderation.Rule{Category: rule.Category, Action: rule.Action, Pattern: pattern})
+		}
+	}
+	return rules, nil
+}
+
+// llmClassifier asks the moderator, with personal data redacted if the
+// submitter's policy asks for it, and records the tokens used against them.
+// Submitters over an LLM budget are not sent to the model; their content is
+// quarantined for a moderator instead, whatever the on_error action.
+type llmClassifier struct {
+	service *ModerationService
+}
+
+func (c *llmClassifier) Name() string {
+	return "llm"
+}
+
+func (c *llmClassifier) Classify(ctx context.Context, submission *moderation.Submission) (moderation.Decision, error) {
+	s := c.service
+	if s.usage != nil {
+		if err := s.usage.CheckBudget(ctx, submission.UserID); err != nil {
+			var budgetErr *BudgetExceededError
+			if !errors.As(err, &budgetErr) {
+				return moderation.Decision{}, err
+			}
+			return moderation.Decision{
+				Action:     moderation.ActionQuarantine,
+				Category:   "budget_exceeded",
+				Reason:     "LLM budget used up before the content could be classified",
+				Classifier: c.Name(),
+			}, nil
+		}
+	}
+
+	content, link := submission.Content, submission.Link
+	if s.redaction != nil {
+		if ownerID, err := uuid.Parse(submission.UserID); err == nil {
+			if redaction := s.redaction.Session(ctx, ownerID); redaction != nil {
+				content = redaction.Redact(content)
+				link = redaction.RedactURL(link)
+			}
+		}
+	}
+	text := content
+	if link != "" {
+		text += "\n\nLink: " + link
+	}
+
+	result, err := s.moderator.Moderate(ctx, text)
+	if err != nil {
+		return moderation.Decision{}, err
+	}
+	if s.usage != nil && (result.Usage.TotalTokens > 0 || result.Usage.PromptTokens > 0) {
+		if _, err := s.usage.RecordUsage(ctx, submission.UserID, nil, result.Model, result.Usage); err != nil {
+			s.logger.WithContext(ctx).Errorf("Failed to record LLM usage: %v", err)
+		}
+	}
+	if !moderation.ValidAction(result.Action) {
+		return moderation.Decision{}, fmt.Errorf("unknown moderation action %q", result.Action)
+	}
+	return moderation.Decision{
+		Action:     result.Action,
+		Category:   result.Category,
+		Reason:     result.Reason,
+		Classifier: c.Name(),
+	}, nil
+}
@@ -1,141 +1,553 @@
 package services
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"fact-check/internal/config"
+	"fact-check/internal/langdetect"
 	"fact-check/internal/models"
+	"fact-check/internal/moderation"
+	"fact-check/internal/repository"
+	"fact-check/internal/tracing"
+	"fact-check/internal/urlcanon"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	// ErrNewsNotFound is returned when a news item does not exist
+	ErrNewsNotFound = errors.New("news not found")
+	// ErrNotFlagged is returned when resolving a verdict nobody asked to review
+	ErrNotFlagged = errors.New("news is not flagged for review")
+	// ErrHeldByModeration is returned when verifying news that moderation
+	// quarantined or rejected
+	ErrHeldByModeration = errors.New("news is held by moderation")
+	// ErrNotQuarantined is returned when moderating news that is not waiting
+	// for a moderator
+	ErrNotQuarantined = errors.New("news is not quarantined")
+)
+
+// SubmissionRejectedError is returned when moderation rejects a submission
+type SubmissionRejectedError struct {
+	Category string
+	Reason   string
+}
+
+func (e *SubmissionRejectedError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("submission rejected by moderation: %s", e.Category)
+	}
+	return fmt.Sprintf("submission rejected by moderation: %s: %s", e.Category, e.Reason)
+}
+
 type NewsService struct {
-	db     *sql.DB
-	logger *logrus.Logger
+	config     *config.Config
+	news       repository.NewsRepository
+	events     EventPublisher
+	moderation *ModerationService
+	links      *urlcanon.Resolver
+	logger     *logrus.Logger
 }
 
-func NewNewsService(cfg interface{}, db *sql.DB, logger *logrus.Logger) *NewsService {
-	return &NewsService{
-		db:     db,
-		logger: logger,
+// NewNewsService creates the service; events may be nil when nothing
+// subscribes to news events, and moderation when submissions are stored
+// without screening
+func NewNewsService(cfg *config.Config, news repository.NewsRepository, events EventPublisher, moderation *ModerationService, logger *logrus.Logger) *NewsService {
+	service := &NewsService{
+		config:     cfg,
+		news:       news,
+		events:     events,
+		moderation: moderation,
+		logger:     logger,
 	}
+	if cfg.Links.Resolve {
+		service.links = urlcanon.NewResolver(urlcanon.Options{
+			Timeout:      cfg.Links.Timeout,
+			MaxRedirects: cfg.Links.MaxRedirects,
+			MaxBodyBytes: cfg.Links.MaxBodyBytes,
+			AllowPrivate: cfg.Links.AllowPrivateTargets,
+			UserAgent:    "fact-check-link-resolver/1.0",
+		})
+	}
+	return service
 }
 
-func (s *NewsService) SubmitNews(userID string, submission *models.NewsSubmission) (*models.News, error) {
+// SubmitNews screens and stores a submission. Rejected submissions return a
+// *SubmissionRejectedError; quarantined ones are stored but not announced
+// or verified until a moderator allows them.
+func (s *NewsService) SubmitNews(ctx context.Context, userID string, submission *models.NewsSubmission) (*models.News, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "NewsService.SubmitNews")
+	defer span.End()
+
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 
+	decision := s.Moderate(ctx, userID, submission)
+	if decision.Action == moderation.ActionReject {
+		s.logger.WithContext(ctx).Infof("Submission rejected by %s moderation: %s", decision.Classifier, decision.Category)
+		return nil, &SubmissionRejectedError{Category: decision.Category, Reason: decision.Reason}
+	}
+
+	canonicalLink, fetchedHost := s.canonicalLink(ctx, submission.Link)
 	news := &models.News{
-		ID:        uuid.New(),
-		UserID:    userUUID,
-		Content:   submission.Content,
-		Link:      submission.Link,
-		PhotoURL:  submission.PhotoURL,
-		Status:    "pending",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:            uuid.New(),
+		UserID:        userUUID,
+		Content:       submission.Content,
+		Link:          submission.Link,
+		CanonicalLink: canonicalLink,
+		FetchedHost:   fetchedHost,
+		PhotoURL:      submission.PhotoURL,
+		Topic:         normalizeTopic(submission.Topic),
+		Language:      detectLanguage(submission.Content),
+		Status:        "pending",
+		Moderation:    models.ModerationAllowed,
+		Published:     submission.Publish,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	if decision.Action == moderation.ActionQuarantine {
+		news.Moderation = models.ModerationQuarantined
+		news.ModerationCategory = &decision.Category
+		news.ModerationReason = &decision.Reason
 	}
 
-	query := `INSERT INTO news (id, user_id, content, link, photo_url, status, created_at, updated_at) 
-			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
 
-	_, err = s.db.Exec(query, news.ID, news.UserID, news.Content, news.Link,
-		news.PhotoURL, news.Status, news.CreatedAt, news.UpdatedAt)
-	if err != nil {
-		return nil, fmt.Errorf("failed to insert news: %w", err)
+	if err := s.news.Create(ctx, news); err != nil {
+		return nil, err
 	}
 
-	s.logger.Infof("News submitted successfully: %s", news.ID)
+	if news.Moderation == models.ModerationQuarantined {
+		s.logger.WithContext(ctx).Infof("News quarantined by %s moderation: %s (%s)", decision.Classifier, news.ID, decision.Category)
+		return news, nil
+	}
+	s.logger.WithContext(ctx).Infof("News submitted successfully: %s", news.ID)
+	s.publish(ctx, models.EventNewsSubmitted, news, "")
 	return news, nil
 }
 
-func (s *NewsService) GetNewsByID(newsID string) (*models.News, error) {
+// Moderate screens a submission without storing it; everything is allowed
+// when moderation is off
+func (s *NewsService) Moderate(ctx context.Context, userID string, submission *models.NewsSubmission) moderation.Decision {
+	if s.moderation == nil {
+		return moderation.Allow
+	}
+	return s.moderation.Check(ctx, screening(userID, submission))
+}
+
+// ModerateRules screens a submission with the moderation rules only, for
+// uploads too large to wait for the LLM. deferred reports whether the LLM
+// still has to classify it, which Screen does before it is verified.
+func (s *NewsService) ModerateRules(ctx context.Context, userID string, submission *models.NewsSubmission) (decision moderation.Decision, deferred bool) {
+	if s.moderation == nil {
+		return moderation.Allow, false
+	}
+	return s.moderation.CheckRules(ctx, screening(userID, submission))
+}
+
+// Screen has the LLM classify news left pending by ModerateRules and stores
+// the outcome, announcing the news if it is allowed. Other news is left as
+// it is.
+func (s *NewsService) Screen(ctx context.Context, news *models.News) error {
+	if news.Moderation != models.ModerationPending {
+		return nil
+	}
+	ctx, span := tracing.Tracer().Start(ctx, "NewsService.Screen")
+	defer span.End()
+
+	decision := moderation.Allow
+	if s.moderation != nil {
+		submission := &models.NewsSubmission{Content: news.Content, Link: news.Link}
+		decision = s.moderation.CheckLLM(ctx, screening(news.UserID.String(), submission))
+	}
+	result := models.Screening{Moderation: models.ModerationAllowed}
+	switch decision.Action {
+	case moderation.ActionQuarantine:
+		result = models.Screening{Moderation: models.ModerationQuarantined, Category: decision.Category, Reason: decision.Reason}
+	case moderation.ActionReject:
+		result = models.Screening{Moderation: models.ModerationRejected, Category: decision.Category, Reason: decision.Reason}
+	}
+
+	dbCtx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
+
+	if err := s.news.Screen(dbCtx, news.ID, result); err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		// Screened by another worker in the meantime, or deleted
+		current, err := s.news.GetByID(dbCtx, news.ID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrNewsNotFound
+			}
+			return err
+		}
+		*news = *current
+		return nil
+	}
+
+	news.Moderation = result.Moderation
+	if result.Category != "" {
+		news.ModerationCategory = &result.Category
+	}
+	if result.Reason != "" {
+		news.ModerationReason = &result.Reason
+	}
+	news.UpdatedAt = time.Now()
+	if news.Moderation != models.ModerationAllowed {
+		s.logger.WithContext(ctx).Infof("Batch news %s by %s moderation: %s (%s)", news.Moderation, decision.Classifier, news.ID, decision.Category)
+		return nil
+	}
+	s.publish(ctx, models.EventNewsSubmitted, news, "")
+	return nil
+}
+
+// screening is what moderation gets to see of a submission
+func screening(userID string, submission *models.NewsSubmission) *moderation.Submission {
+	screened := &moderation.Submission{UserID: userID, Content: submission.Content}
+	if submission.Link != nil {
+		screened.Link = *submission.Link
+	}
+	return screened
+}
+
+func (s *NewsService) GetNewsByID(ctx context.Context, newsID string) (*models.News, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "NewsService.GetNewsByID")
+	defer span.End()
+
 	newsUUID, err := uuid.Parse(newsID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid news ID: %w", err)
+		// A malformed ID cannot name any news
+		return nil, fmt.Errorf("%w: invalid news ID %q", ErrNewsNotFound, newsID)
 	}
 
-	var news models.News
-	query := `SELECT id, user_id, content, link, photo_url, status, explanation, created_at, updated_at 
-			  FROM news WHERE id = $1`
-
-	err = s.db.QueryRow(query, newsUUID).Scan(
-		&news.ID, &news.UserID, &news.Content, &news.Link, &news.PhotoURL,
-		&news.Status, &news.Explanation, &news.CreatedAt, &news.UpdatedAt,
-	)
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
 
+	news, err := s.news.GetByID(ctx, newsUUID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("news not found")
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNewsNotFound
 		}
-		return nil, fmt.Errorf("failed to get news: %w", err)
+		return nil, err
 	}
 
-	return &news, nil
+	return news, nil
 }
 
-func (s *NewsService) GetUserNews(userID string) ([]*models.News, error) {
+func (s *NewsService) GetUserNews(ctx context.Context, userID string) ([]*models.News, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "NewsService.GetUserNews")
+	defer span.End()
+
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 
-	query := `SELECT id, user_id, content, link, photo_url, status, explanation, created_at, updated_at 
-			  FROM news WHERE user_id = $1 ORDER BY created_at DESC`
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
+
+	return s.news.ListByUser(ctx, userUUID)
+}
+
+// SetPublished opts the user's news in to or out of the public feeds. News
+// of other users is reported as not found.
+func (s *NewsService) SetPublished(ctx context.Context, userID, newsID string, published bool) (*models.News, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "NewsService.SetPublished")
+	defer span.End()
 
-	rows, err := s.db.Query(query, userUUID)
+	news, err := s.GetNewsByID(ctx, newsID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query user news: %w", err)
+		return nil, err
+	}
+	if news.UserID.String() != userID {
+		return nil, ErrNewsNotFound
 	}
-	defer rows.Close()
 
-	var newsList []*models.News
-	for rows.Next() {
-		var news models.News
-		err := rows.Scan(
-			&news.ID, &news.UserID, &news.Content, &news.Link, &news.PhotoURL,
-			&news.Status, &news.Explanation, &news.CreatedAt, &news.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan news row: %w", err)
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
+
+	if err := s.news.SetPublished(ctx, news.ID, published); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNewsNotFound
 		}
-		newsList = append(newsList, &news)
+		return nil, err
 	}
+	news.Published = published
+	news.UpdatedAt = time.Now()
+	return news, nil
+}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating over news rows: %w", err)
-	}
+// ListPublished returns verified news. The public feeds set filter.Public so
+// only news its submitter published, and no verdict awaiting review, is
+// listed.
+func (s *NewsService) ListPublished(ctx context.Context, filter repository.PublishedFilter) ([]*models.News, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "NewsService.ListPublished")
+	defer span.End()
 
-	return newsList, nil
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
+
+	filter.Topic = strings.ToLower(strings.TrimSpace(filter.Topic))
+	return s.news.ListPublished(ctx, filter)
 }
 
-func (s *NewsService) UpdateNewsStatus(newsID string, status string, explanation string) error {
+func (s *NewsService) UpdateNewsStatus(ctx context.Context, newsID string, verdict models.Verdict) error {
+	ctx, span := tracing.Tracer().Start(ctx, "NewsService.UpdateNewsStatus")
+	defer span.End()
+
 	newsUUID, err := uuid.Parse(newsID)
 	if err != nil {
-		return fmt.Errorf("invalid news ID: %w", err)
+		return fmt.Errorf("%w: invalid news ID %q", ErrNewsNotFound, newsID)
 	}
 
-	query := `UPDATE news SET status = $1, explanation = $2, updated_at = CURRENT_TIMESTAMP 
-			  WHERE id = $3`
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
 
-	result, err := s.db.Exec(query, status, explanation, newsUUID)
+	// The previous verdict decides whether verdict.changed fires
+	previous, err := s.news.GetByID(ctx, newsUUID)
 	if err != nil {
-		return fmt.Errorf("failed to update news status: %w", err)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNewsNotFound
+		}
+		return err
+	}
+
+	if err := s.news.UpdateStatus(ctx, newsUUID, verdict); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNewsNotFound
+		}
+		return err
+	}
+
+	s.logger.WithContext(ctx).Infof("News status updated successfully: %s -> %s", newsID, verdict.Status)
+
+	updated := *previous
+	updated.Status = verdict.Status
+	updated.Explanation = &verdict.Explanation
+	updated.PromptVersion = verdict.PromptVersion
+	updated.ModelVerdicts = verdict.ModelVerdicts
+	updated.NeedsReview = verdict.NeedsReview
+	updated.Confidence = &verdict.Confidence
+	updated.RawConfidence = &verdict.RawConfidence
+	updated.ModelStatus = &verdict.ModelStatus
+	updated.UpdatedAt = time.Now()
+	s.publish(ctx, models.EventNewsVerified, &updated, previous.Status)
+	if previous.Status != "pending" && previous.Status != verdict.Status {
+		s.publish(ctx, models.EventVerdictChanged, &updated, previous.Status)
 	}
+	return nil
+}
 
-	rowsAffected, err := result.RowsAffected()
+// ListReviews returns verdicts flagged for human review, most recent first
+func (s *NewsService) ListReviews(ctx context.Context, limit int) ([]*models.News, error) {
+	return s.ListPublished(ctx, repository.PublishedFilter{NeedsReview: true, Limit: limit})
+}
+
+// ResolveReview replaces a flagged verdict with the reviewer's one. The
+// model verdicts are kept so reviewed outcomes can be compared with them.
+func (s *NewsService) ResolveReview(ctx context.Context, reviewerID, newsID string, req *models.ReviewRequest) (*models.News, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "NewsService.ResolveReview")
+	defer span.End()
+
+	reviewerUUID, err := uuid.Parse(reviewerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	news, err := s.GetNewsByID(ctx, newsID)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return nil, err
+	}
+	if !news.NeedsReview {
+		return nil, ErrNotFlagged
+	}
+
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
+
+	resolution := models.Resolution{
+		Status:      req.Status,
+		Explanation: strings.TrimSpace(req.Explanation),
+		ReviewerID:  reviewerUUID,
+		ReviewedAt:  time.Now(),
+	}
+	if resolution.Explanation == "" && news.Explanation != nil {
+		resolution.Explanation = *news.Explanation
+	}
+	if err := s.news.Resolve(ctx, news.ID, resolution); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNewsNotFound
+		}
+		return nil, err
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("news not found")
+	s.logger.WithContext(ctx).Infof("Review resolved: %s -> %s", newsID, resolution.Status)
+
+	previousStatus := news.Status
+	news.Status = resolution.Status
+	news.Explanation = &resolution.Explanation
+	news.NeedsReview = false
+	news.ReviewedBy = &resolution.ReviewerID
+	news.ReviewedAt = &resolution.ReviewedAt
+	news.UpdatedAt = resolution.ReviewedAt
+	if previousStatus != resolution.Status {
+		s.publish(ctx, models.EventVerdictChanged, news, previousStatus)
 	}
+	return news, nil
+}
 
-	s.logger.Infof("News status updated successfully: %s -> %s", newsID, status)
-	return nil
+// ListQuarantined returns submissions waiting for a moderator, oldest first
+func (s *NewsService) ListQuarantined(ctx context.Context, limit int) ([]*models.News, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "NewsService.ListQuarantined")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
+
+	return s.news.ListQuarantined(ctx, limit)
+}
+
+// ResolveModeration allows or rejects a quarantined submission. Allowed
+// news is announced as submitted and can then be verified.
+func (s *NewsService) ResolveModeration(ctx context.Context, moderatorID, newsID string, req *models.ModerationRequest) (*models.News, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "NewsService.ResolveModeration")
+	defer span.End()
+
+	moderatorUUID, err := uuid.Parse(moderatorID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	news, err := s.GetNewsByID(ctx, newsID)
+	if err != nil {
+		return nil, err
+	}
+	if news.Moderation != models.ModerationQuarantined {
+		return nil, ErrNotQuarantined
+	}
+
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
+
+	decision := models.ModerationDecision{
+		Moderation:  models.ModerationRejected,
+		Reason:      strings.TrimSpace(req.Reason),
+		ModeratorID: moderatorUUID,
+		ModeratedAt: time.Now(),
+	}
+	if req.Action == moderation.ActionAllow {
+		decision.Moderation = models.ModerationAllowed
+	}
+	if err := s.news.Moderate(ctx, news.ID, decision); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNewsNotFound
+		}
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Infof("Moderation resolved: %s -> %s", newsID, decision.Moderation)
+
+	news.Moderation = decision.Moderation
+	if decision.Reason != "" {
+		news.ModerationReason = &decision.Reason
+	}
+	news.ModeratedBy = &decision.ModeratorID
+	news.ModeratedAt = &decision.ModeratedAt
+	news.UpdatedAt = decision.ModeratedAt
+	if news.Moderation == models.ModerationAllowed {
+		s.publish(ctx, models.EventNewsSubmitted, news, "")
+	}
+	return news, nil
+}
+
+// canonicalLink resolves a submitted link to its canonical URL and the host
+// it was fetched from, falling back to offline cleanup, without a host, when
+// the link cannot be fetched
+func (s *NewsService) canonicalLink(ctx context.Context, link *string) (canonical, host *string) {
+	if link == nil || strings.TrimSpace(*link) == "" {
+		return nil, nil
+	}
+	if s.links != nil {
+		result, err := s.links.Resolve(ctx, *link)
+		if err == nil {
+			return &result.Canonical, &result.Host
+		}
+		s.logger.WithContext(ctx).Warnf("Failed to resolve link, using it without redirects: %v", err)
+	}
+	return cleanLink(link), nil
+}
+
+// cleanLink canonicalizes a link without fetching it; unusable links, and
+// links too long to store, have no canonical form
+func cleanLink(link *string) *string {
+	if link == nil {
+		return nil
+	}
+	canonical, err := urlcanon.Clean(*link)
+	if err != nil || len(canonical) > urlcanon.MaxLength {
+		return nil
+	}
+	return &canonical
+}
+
+// normalizeTopic lowercases and trims a topic so feed filters match
+// regardless of how it was typed; a blank topic becomes nil
+func normalizeTopic(topic *string) *string {
+	if topic == nil {
+		return nil
+	}
+	normalized := strings.ToLower(strings.TrimSpace(*topic))
+	if normalized == "" {
+		return nil
+	}
+	return &normalized
+}
+
+// detectLanguage returns the language code stored with submitted content
+func detectLanguage(content string) *string {
+	language := langdetect.Detect(content)
+	return &language
+}
+
+// publish emits a news event. Failures are logged rather than returned so
+// that subscribers can never fail the operation that triggered the event.
+func (s *NewsService) publish(ctx context.Context, event string, news *models.News, previousStatus string) {
+	if s.events == nil {
+		return
+	}
+
+	publishNewsEvent(ctx, s.events, s.logger, event, news, previousStatus)
+}
+
+// publishNewsEvent emits a news event, logging rather than returning failures
+func publishNewsEvent(ctx context.Context, events EventPublisher, logger *logrus.Logger, event string, news *models.News, previousStatus string) {
+	data := models.NewsEventData{
+		NewsID:         news.ID,
+		UserID:         news.UserID,
+		Status:         news.Status,
+		PreviousStatus: previousStatus,
+		Explanation:    news.Explanation,
+		Link:           news.Link,
+		UpdatedAt:      news.UpdatedAt,
+	}
+	if err := events.Publish(ctx, event, news.UserID, data); err != nil {
+		logger.WithContext(ctx).Errorf("Failed to publish %s event for news %s: %v", event, news.ID, err)
+	}
+}
+
+// withTimeout derives a context with the given deadline; a zero timeout only
+// inherits the parent's deadline
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
 }
@@ -1,26 +1,58 @@
 package services
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"fact-check/internal/config"
+	"fact-check/internal/langdetect"
+	"fact-check/internal/metrics"
+	"fact-check/internal/models"
+	"fact-check/internal/resilience"
+	"fact-check/internal/tracing"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type OpenAIService struct {
-	config *config.Config
-	logger *logrus.Logger
+	config   *config.Config
+	settings atomic.Pointer[modelSettings]
+	client   *resilience.Client
+	logger   *logrus.Logger
+}
+
+// modelSettings are the request parameters that can change on config reload
+type modelSettings struct {
+	Model     string
+	MaxTokens int
 }
 
 type OpenAIRequest struct {
-	Model     string    `json:"model"`
-	Messages  []Message `json:"messages"`
-	MaxTokens int       `json:"max_tokens"`
+	Model         string         `json:"model"`
+	Messages      []Message      `json:"messages"`
+	MaxTokens     int            `json:"max_tokens"`
+	Stream        bool           `json:"stream,omitempty"`
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+}
+
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
 }
 
 type Message struct {
@@ -29,7 +61,9 @@ type Message struct {
 }
 
 type OpenAIResponse struct {
-	Choices []Choice `json:"choices"`
+	Model   string            `json:"model"`
+	Choices []Choice          `json:"choices"`
+	Usage   models.TokenUsage `json:"usage"`
 	Error   *struct {
 		Message string `json:"message"`
 		Type    string `json:"type"`
@@ -42,63 +76,339 @@ type Choice struct {
 }
 
 func NewOpenAIService(cfg *config.Config, logger *logrus.Logger) *OpenAIService {
-	return &OpenAIService{
+	breaker := resilience.NewCircuitBreaker("openai", cfg.LLMClient.BreakerFailures, cfg.LLMClient.BreakerCooldown)
+	client := resilience.NewClient(resilience.ClientConfig{
+		ConnectTimeout: cfg.LLMClient.ConnectTimeout,
+		RequestTimeout: cfg.LLMClient.RequestTimeout,
+		MaxRetries:     cfg.LLMClient.MaxRetries,
+		Backoff: resilience.Backoff{
+			Base: cfg.LLMClient.RetryBaseDelay,
+			Max:  cfg.LLMClient.RetryMaxDelay,
+		},
+	}, breaker)
+	client.ShouldRetry = shouldRetryOpenAI
+
+	service := &OpenAIService{
 		config: cfg,
+		client: client,
 		logger: logger,
 	}
+	service.ApplyConfig(cfg)
+	return service
+}
+
+// ApplyConfig switches the model settings used by subsequent requests
+func (s *OpenAIService) ApplyConfig(cfg *config.Config) {
+	s.settings.Store(&modelSettings{Model: cfg.OpenAIModel, MaxTokens: cfg.OpenAIMaxTokens})
+}
+
+// shouldRetryOpenAI retries rate limits and server errors, but not an
+// exhausted quota which is also reported as 429
+func shouldRetryOpenAI(resp *http.Response, body []byte) bool {
+	if !resilience.DefaultShouldRetry(resp, body) {
+		return false
+	}
+
+	var errorResp OpenAIResponse
+	if json.Unmarshal(body, &errorResp) == nil && errorResp.Error != nil {
+		return errorResp.Error.Code != "insufficient_quota"
+	}
+	return true
+}
+
+func (s *OpenAIService) VerifyNews(ctx context.Context, request *VerificationRequest) (*VerificationResult, error) {
+	return s.verify(ctx, request, "", nil)
+}
+
+// VerifyNewsStream uses the streaming chat API and passes the answer to
+// onDelta as it is generated. The result is the same as VerifyNews.
+func (s *OpenAIService) VerifyNewsStream(ctx context.Context, request *VerificationRequest, onDelta func(string)) (*VerificationResult, error) {
+	return s.verify(ctx, request, "", onDelta)
+}
+
+// ForModel returns a verifier that asks model instead of the configured one
+func (s *OpenAIService) ForModel(model string) Verifier {
+	return &modelVerifier{service: s, model: model}
+}
+
+// modelVerifier verifies with a fixed model of an OpenAIService
+type modelVerifier struct {
+	service *OpenAIService
+	model   string
+}
+
+func (v *modelVerifier) VerifyNews(ctx context.Context, request *VerificationRequest) (*VerificationResult, error) {
+	return v.service.verify(ctx, request, v.model, nil)
 }
 
-func (s *OpenAIService) VerifyNews(content string, link string, photoURL string) (string, string, error) {
+// verify asks model, or the configured model when it is empty, for a verdict
+func (s *OpenAIService) verify(ctx context.Context, verification *VerificationRequest, model string, onDelta func(string)) (result *VerificationResult, err error) {
+	settings := s.settings.Load()
+	if model != "" {
+		override := *settings
+		override.Model = model
+		settings = &override
+	}
+	streaming := onDelta != nil
+
+	ctx, span := tracing.Tracer().Start(ctx, "OpenAI chat.completions",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("llm.vendor", "openai"),
+			attribute.String("llm.request.model", settings.Model),
+			attribute.Bool("llm.stream", streaming),
+		),
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else if result != nil {
+			span.SetAttributes(
+				attribute.String("llm.response.model", result.Model),
+				attribute.Int("llm.usage.prompt_tokens", result.Usage.PromptTokens),
+				attribute.Int("llm.usage.completion_tokens", result.Usage.CompletionTokens),
+				attribute.String("llm.verdict", result.Status),
+			)
+		}
+		span.End()
+	}()
+
 	// Check if OpenAI API key is configured
 	if s.config.OpenAIAPIKey == "" || s.config.OpenAIAPIKey == "your-openai-api-key" {
-		return "uncertain", "OpenAI API not configured. Please configure your OpenAI API key to enable fact-checking.", nil
+		return &VerificationResult{
+			Status:      "uncertain",
+			Explanation: "OpenAI API not configured. Please configure your OpenAI API key to enable fact-checking.",
+			Model:       settings.Model,
+		}, nil
 	}
-	
-	prompt := s.buildPrompt(content, link, photoURL)
 
-	request := OpenAIRequest{
-		Model: "gpt-3.5-turbo",
-		Messages: []Message{
-			{
-				Role:    "system",
-				Content: "You are a fact-checking expert. Analyze the provided news content and determine if it's likely to be true or false. Provide a clear explanation for your assessment.",
-			},
-			{
-				Role:    "user",
-				Content: prompt,
-			},
+	template := verification.Prompt
+	if template == nil {
+		template = builtinPrompt
+	}
+	if template.Version != nil {
+		span.SetAttributes(attribute.Int("llm.prompt.version", *template.Version))
+	}
+	prompt, err := template.Render(verification)
+	if err != nil {
+		return nil, err
+	}
+
+	completion, err := s.complete(ctx, span, settings, []Message{
+		{
+			Role:    "system",
+			Content: template.System,
+		},
+		{
+			Role:    "user",
+			Content: prompt,
 		},
-		MaxTokens: 500,
+	}, onDelta)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the response to extract status and explanation
+	status, explanation := s.parseOpenAIResponse(completion.Content)
+
+	return &VerificationResult{
+		Status:      status,
+		Explanation: explanation,
+		Confidence:  parseConfidence(completion.Content),
+		Model:       completion.Model,
+		Usage:       completion.Usage,
+	}, nil
+}
+
+// Translate translates text with the chat API
+func (s *OpenAIService) Translate(ctx context.Context, text, from, to string) (result *TranslationResult, err error) {
+	settings := s.settings.Load()
+
+	ctx, span := tracing.Tracer().Start(ctx, "OpenAI translate",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("llm.vendor", "openai"),
+			attribute.String("llm.request.model", settings.Model),
+			attribute.String("llm.translate.to", to),
+		),
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	if s.config.OpenAIAPIKey == "" || s.config.OpenAIAPIKey == "your-openai-api-key" {
+		return nil, errors.New("OpenAI API not configured")
+	}
+
+	instruction := fmt.Sprintf("You are a professional translator. Translate the user's text into %s.", langdetect.Name(to))
+	if from != "" {
+		instruction = fmt.Sprintf("You are a professional translator. Translate the user's text from %s into %s.", langdetect.Name(from), langdetect.Name(to))
+	}
+	instruction += " Keep names, numbers and quotes exact and the words TRUE, FALSE and UNCERTAIN in English. Reply with the translation only."
+
+	completion, err := s.complete(ctx, span, settings, []Message{
+		{Role: "system", Content: instruction},
+		{Role: "user", Content: text},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TranslationResult{
+		Text:  strings.TrimSpace(completion.Content),
+		Model: completion.Model,
+		Usage: completion.Usage,
+	}, nil
+}
+
+// moderationInstruction asks for one line the moderation parser understands
+const moderationInstruction = `You are the content moderator of a fact-checking service. Users submit claims to be checked; controversial, false or offensive claims about public matters are legitimate submissions. Classify the user's submission and reply with exactly one line:
+ALLOW
+QUARANTINE: <category>: <short reason>   if a person should look at it first
+REJECT: <category>: <short reason>       for harassment of private individuals, hate speech, spam or advertising, sexual content involving minors, or content that is illegal to distribute
+Use one of these categories: harassment, hate, spam, sexual, violence, illegal, other.`
+
+// Moderate classifies submitted content with the chat API
+func (s *OpenAIService) Moderate(ctx context.Context, text string) (result *ModerationResult, err error) {
+	settings := s.settings.Load()
+
+	ctx, span := tracing.Tracer().Start(ctx, "OpenAI moderate",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("llm.vendor", "openai"),
+			attribute.String("llm.request.model", settings.Model),
+		),
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	if s.config.OpenAIAPIKey == "" || s.config.OpenAIAPIKey == "your-openai-api-key" {
+		return nil, errors.New("OpenAI API not configured")
+	}
+
+	completion, err := s.complete(ctx, span, settings, []Message{
+		{Role: "system", Content: moderationInstruction},
+		{Role: "user", Content: text},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err = parseModeration(completion.Content)
+	if err != nil {
+		return nil, err
+	}
+	result.Model = completion.Model
+	result.Usage = completion.Usage
+	return result, nil
+}
+
+// parseModeration reads the "REJECT: category: reason" line the moderation
+// prompt asks for
+func parseModeration(response string) (*ModerationResult, error) {
+	line := strings.TrimSpace(strings.SplitN(strings.TrimSpace(response), "\n", 2)[0])
+	parts := strings.SplitN(line, ":", 3)
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	result := &ModerationResult{Action: strings.ToLower(strings.Trim(parts[0], "*. "))}
+	switch result.Action {
+	case "allow":
+		return result, nil
+	case "quarantine", "reject":
+	default:
+		return nil, fmt.Errorf("unexpected moderation answer %q", line)
+	}
+	result.Category = "other"
+	if len(parts) > 1 && parts[1] != "" {
+		result.Category = strings.ToLower(parts[1])
+	}
+	if len(parts) > 2 {
+		result.Reason = parts[2]
+	}
+	return result, nil
+}
+
+// chatCompletion is the answer of a single chat API call
+type chatCompletion struct {
+	Content string
+	Model   string
+	Usage   models.TokenUsage
+}
+
+// complete sends one chat request, streaming the answer to onDelta when it
+// is not nil, and records latency, token and error metrics
+func (s *OpenAIService) complete(ctx context.Context, span trace.Span, settings *modelSettings, messages []Message, onDelta func(string)) (*chatCompletion, error) {
+	streaming := onDelta != nil
+	request := OpenAIRequest{
+		Model:     settings.Model,
+		Messages:  messages,
+		MaxTokens: settings.MaxTokens,
+	}
+	if streaming {
+		request.Stream = true
+		request.StreamOptions = &StreamOptions{IncludeUsage: true}
 	}
 
 	jsonData, err := json.Marshal(request)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to marshal OpenAI request: %w", err)
+		return nil, fmt.Errorf("failed to marshal OpenAI request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", s.config.OpenAIEndpoint+"/chat/completions", bytes.NewBuffer(jsonData))
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.LLMVerify)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.config.OpenAIEndpoint+"/chat/completions", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", "", fmt.Errorf("failed to create HTTP request: %w", err)
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+s.config.OpenAIAPIKey)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to make HTTP request: %w", err)
+	start := time.Now()
+	var resp *http.Response
+	var body []byte
+	if streaming {
+		resp, body, err = s.client.Stream(req)
+	} else {
+		resp, body, err = s.client.Do(req)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to read response body: %w", err)
+		metrics.LLMRequestDuration.WithLabelValues(request.Model, "error").Observe(time.Since(start).Seconds())
+		if errors.Is(err, resilience.ErrCircuitOpen) {
+			metrics.LLMErrors.WithLabelValues(request.Model, "circuit_open").Inc()
+			return nil, fmt.Errorf("OpenAI API temporarily unavailable: %w", err)
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			metrics.LLMErrors.WithLabelValues(request.Model, "timeout").Inc()
+		} else {
+			metrics.LLMErrors.WithLabelValues(request.Model, "transport").Inc()
+		}
+		return nil, fmt.Errorf("failed to make HTTP request: %w", err)
 	}
 
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
 	if resp.StatusCode != http.StatusOK {
-		s.logger.Errorf("OpenAI API error: %s", string(body))
-		
+		if body == nil {
+			// Stream leaves the body of any 2xx response unread
+			resp.Body.Close()
+		}
+		metrics.LLMRequestDuration.WithLabelValues(request.Model, "error").Observe(time.Since(start).Seconds())
+		s.logger.WithContext(ctx).Errorf("OpenAI API error: %s", string(body))
+
 		// Try to parse the error response for better error messages
 		var errorResp struct {
 			Error struct {
@@ -107,56 +417,136 @@ func (s *OpenAIService) VerifyNews(content string, link string, photoURL string)
 				Code    string `json:"code"`
 			} `json:"error"`
 		}
-		
+
 		if json.Unmarshal(body, &errorResp) == nil {
+			metrics.LLMErrors.WithLabelValues(request.Model, llmErrorCode(errorResp.Error.Code, resp.StatusCode)).Inc()
 			switch errorResp.Error.Code {
 			case "insufficient_quota":
-				return "", "", fmt.Errorf("OpenAI API quota exceeded: %s. Please check your billing and upgrade your plan.", errorResp.Error.Message)
+				return nil, fmt.Errorf("OpenAI API quota exceeded: %s. Please check your billing and upgrade your plan.", errorResp.Error.Message)
 			case "rate_limit_exceeded":
-				return "", "", fmt.Errorf("OpenAI API rate limit exceeded: %s. Please try again later.", errorResp.Error.Message)
+				return nil, fmt.Errorf("OpenAI API rate limit exceeded: %s. Please try again later.", errorResp.Error.Message)
 			default:
-				return "", "", fmt.Errorf("OpenAI API error (%s): %s", errorResp.Error.Code, errorResp.Error.Message)
+				return nil, fmt.Errorf("OpenAI API error (%s): %s", errorResp.Error.Code, errorResp.Error.Message)
 			}
 		}
-		
-		return "", "", fmt.Errorf("OpenAI API returned status %d", resp.StatusCode)
+
+		metrics.LLMErrors.WithLabelValues(request.Model, llmErrorCode("", resp.StatusCode)).Inc()
+		return nil, fmt.Errorf("OpenAI API returned status %d", resp.StatusCode)
 	}
 
 	var openAIResp OpenAIResponse
-	if err := json.Unmarshal(body, &openAIResp); err != nil {
-		return "", "", fmt.Errorf("failed to unmarshal OpenAI response: %w", err)
+	if streaming {
+		if err := readStream(resp.Body, &openAIResp, onDelta); err != nil {
+			metrics.LLMRequestDuration.WithLabelValues(request.Model, "error").Observe(time.Since(start).Seconds())
+			metrics.LLMErrors.WithLabelValues(request.Model, "bad_response").Inc()
+			return nil, err
+		}
+	} else if err := json.Unmarshal(body, &openAIResp); err != nil {
+		metrics.LLMRequestDuration.WithLabelValues(request.Model, "error").Observe(time.Since(start).Seconds())
+		metrics.LLMErrors.WithLabelValues(request.Model, "bad_response").Inc()
+		return nil, fmt.Errorf("failed to unmarshal OpenAI response: %w", err)
 	}
+	metrics.LLMRequestDuration.WithLabelValues(request.Model, "success").Observe(time.Since(start).Seconds())
 
 	if openAIResp.Error != nil {
-		return "", "", fmt.Errorf("OpenAI API error: %s", openAIResp.Error.Message)
+		metrics.LLMErrors.WithLabelValues(request.Model, llmErrorCode(openAIResp.Error.Code, resp.StatusCode)).Inc()
+		return nil, fmt.Errorf("OpenAI API error: %s", openAIResp.Error.Message)
 	}
 
 	if len(openAIResp.Choices) == 0 {
-		return "", "", fmt.Errorf("no response from OpenAI API")
+		metrics.LLMErrors.WithLabelValues(request.Model, "bad_response").Inc()
+		return nil, fmt.Errorf("no response from OpenAI API")
 	}
 
-	response := openAIResp.Choices[0].Message.Content
-
-	// Parse the response to extract status and explanation
-	status, explanation := s.parseOpenAIResponse(response)
+	model := openAIResp.Model
+	if model == "" {
+		model = request.Model
+	}
+	metrics.LLMTokens.WithLabelValues(model, "prompt").Add(float64(openAIResp.Usage.PromptTokens))
+	metrics.LLMTokens.WithLabelValues(model, "completion").Add(float64(openAIResp.Usage.CompletionTokens))
+
+	return &chatCompletion{
+		Content: openAIResp.Choices[0].Message.Content,
+		Model:   model,
+		Usage:   openAIResp.Usage,
+	}, nil
+}
 
-	return status, explanation, nil
+// streamChunk is one server-sent event of a streamed chat completion
+type streamChunk struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Delta Message `json:"delta"`
+	} `json:"choices"`
+	Usage *models.TokenUsage `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+		Code    string `json:"code"`
+	} `json:"error"`
 }
 
-func (s *OpenAIService) buildPrompt(content string, link string, photoURL string) string {
-	prompt := fmt.Sprintf("Please fact-check the following news content:\n\nContent: %s\n", content)
+// readStream reads a streamed chat completion into resp, passing content
+// deltas to onDelta as they arrive. It closes body.
+func readStream(body io.ReadCloser, resp *OpenAIResponse, onDelta func(string)) error {
+	defer body.Close()
+
+	var content strings.Builder
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
 
-	if link != "" {
-		prompt += fmt.Sprintf("Source Link: %s\n", link)
+		var chunk streamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("failed to parse OpenAI stream chunk: %w", err)
+		}
+		if chunk.Error != nil {
+			return fmt.Errorf("OpenAI API error: %s", chunk.Error.Message)
+		}
+		if chunk.Model != "" {
+			resp.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			resp.Usage = *chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				content.WriteString(choice.Delta.Content)
+				onDelta(choice.Delta.Content)
+			}
+		}
 	}
-
-	if photoURL != "" {
-		prompt += fmt.Sprintf("Photo URL: %s\n", photoURL)
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read OpenAI stream: %w", err)
 	}
 
-	prompt += "\nPlease respond with:\n1. A clear assessment: 'TRUE', 'FALSE', or 'UNCERTAIN'\n2. A detailed explanation for your assessment\n3. Any relevant context or sources you considered"
+	if content.Len() > 0 {
+		resp.Choices = []Choice{{Message: Message{Role: "assistant", Content: content.String()}}}
+	}
+	return nil
+}
 
-	return prompt
+// llmErrorCode maps a provider error to a low-cardinality metrics label
+func llmErrorCode(code string, statusCode int) string {
+	switch {
+	case code == "insufficient_quota":
+		return "quota"
+	case code == "rate_limit_exceeded" || statusCode == http.StatusTooManyRequests:
+		return "rate_limit"
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return "auth"
+	case statusCode >= 500:
+		return "server_error"
+	default:
+		return "api_error"
+	}
 }
 
 func (s *OpenAIService) parseOpenAIResponse(response string) (string, string) {
@@ -179,6 +569,23 @@ func (s *OpenAIService) parseOpenAIResponse(response string) (string, string) {
 	return status, explanation
 }
 
+// confidencePattern matches the "Confidence: 80%" line the prompt asks for
+var confidencePattern = regexp.MustCompile(`(?i)confidence\W{0,3}(\d{1,3}(?:\.\d+)?)\s*%`)
+
+// parseConfidence returns the confidence the model stated, between 0 and 1,
+// or 0 when it stated none
+func parseConfidence(response string) float64 {
+	match := confidencePattern.FindStringSubmatch(response)
+	if match == nil {
+		return 0
+	}
+	percent, err := strconv.ParseFloat(match[1], 64)
+	if err != nil || percent > 100 {
+		return 0
+	}
+	return percent / 100
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr ||
 		(len(s) > len(substr) && (s[:len(substr)] == substr ||
@@ -191,6 +598,37 @@ func (s *OpenAIService) IsAvailable() bool {
 	return s.config.OpenAIAPIKey != "" && s.config.OpenAIAPIKey != "your-openai-api-key"
 }
 
+// Probe checks that the provider is configured, the circuit breaker is not
+// open and the models endpoint answers. It does not retry or touch the breaker.
+func (s *OpenAIService) Probe(ctx context.Context) (map[string]interface{}, error) {
+	details := s.GetServiceStatus()
+	if !s.IsAvailable() {
+		return details, errors.New("OpenAI API key not configured")
+	}
+
+	breaker := s.client.Breaker().Snapshot()
+	if breaker.State == resilience.StateOpen {
+		return details, fmt.Errorf("circuit breaker open: %s", breaker.LastError)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", s.config.OpenAIEndpoint+"/models", nil)
+	if err != nil {
+		return details, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.config.OpenAIAPIKey)
+
+	resp, err := s.client.HTTPClient().Do(req)
+	if err != nil {
+		return details, fmt.Errorf("OpenAI API unreachable: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return details, fmt.Errorf("OpenAI API returned status %d", resp.StatusCode)
+	}
+	return details, nil
+}
+
 // GetServiceStatus returns the current status of the OpenAI service
 func (s *OpenAIService) GetServiceStatus() map[string]interface{} {
 	status := map[string]interface{}{
@@ -204,6 +642,13 @@ func (s *OpenAIService) GetServiceStatus() map[string]interface{} {
 		status["configured"] = false
 		status["message"] = "OpenAI API key not configured. Please set OPENAI_API_KEY environment variable."
 	}
+
+	breaker := s.client.Breaker().Snapshot()
+	status["circuit_breaker"] = breaker
+	if breaker.State == resilience.StateOpen {
+		status["available"] = false
+		status["message"] = "OpenAI API is failing, requests are paused until the circuit breaker retries"
+	}
 	
 	return status
 }
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fact-check/internal/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestVerifyNewsStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request OpenAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || !request.Stream || request.StreamOptions == nil {
+			t.Errorf("expected a streaming request, got %+v (%v)", request, err)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{
+			`{"model":"gpt-test","choices":[{"delta":{"role":"assistant"}}]}`,
+			`{"model":"gpt-test","choices":[{"delta":{"content":"FALSE: "}}]}`,
+			`{"model":"gpt-test","choices":[{"delta":{"content":"no such event"}}]}`,
+			`{"model":"gpt-test","choices":[],"usage":{"prompt_tokens":12,"completion_tokens":5,"total_tokens":17}}`,
+			`[DONE]`,
+		} {
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.Defaults()
+	cfg.OpenAIAPIKey = "test-key"
+	cfg.OpenAIEndpoint = server.URL
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	var deltas []string
+	result, err := NewOpenAIService(cfg, logger).VerifyNewsStream(context.Background(), &VerificationRequest{Content: "claim"}, func(text string) {
+		deltas = append(deltas, text)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(deltas, "|") != "FALSE: |no such event" {
+		t.Fatalf("unexpected deltas %q", deltas)
+	}
+	if result.Status != "false" || result.Model != "gpt-test" || result.Usage.TotalTokens != 17 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request OpenAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Stream || len(request.Messages) != 2 {
+			t.Errorf("expected a plain two-message request, got %+v (%v)", request, err)
+		} else if !strings.Contains(request.Messages[0].Content, "from Spanish into English") {
+			t.Errorf("unexpected instruction %q", request.Messages[0].Content)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"model":"gpt-test","choices":[{"message":{"role":"assistant","content":" Prices will fall \n"}}],"usage":{"prompt_tokens":30,"completion_tokens":4,"total_tokens":34}}`)
+	}))
+	defer server.Close()
+
+	cfg := config.Defaults()
+	cfg.OpenAIAPIKey = "test-key"
+	cfg.OpenAIEndpoint = server.URL
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	result, err := NewOpenAIService(cfg, logger).Translate(context.Background(), "Los precios bajarán", "es", "en")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Text != "Prices will fall" || result.Model != "gpt-test" || result.Usage.TotalTokens != 34 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestParseModeration(t *testing.T) {
+	tests := []struct {
+		response string
+		want     ModerationResult
+	}{
+		{"ALLOW", ModerationResult{Action: "allow"}},
+		{"**Allow.**\nThe claim is about public policy.", ModerationResult{Action: "allow"}},
+		{"QUARANTINE: Harassment: names a private person", ModerationResult{Action: "quarantine", Category: "harassment", Reason: "names a private person"}},
+		{"REJECT: spam: sells pills: and more", ModerationResult{Action: "reject", Category: "spam", Reason: "sells pills: and more"}},
+		{"REJECT", ModerationResult{Action: "reject", Category: "other"}},
+	}
+
+	for _, test := range tests {
+		result, err := parseModeration(test.response)
+		if err != nil || *result != test.want {
+			t.Errorf("%q: expected %+v, got %+v (%v)", test.response, test.want, result, err)
+		}
+	}
+
+	if _, err := parseModeration("I cannot help with that"); err == nil {
+		t.Error("expected an error for an unexpected answer")
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"fact-check/internal/models"
+	"fact-check/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrOrganizationNotFound is returned when an organization does not
+	// exist or the caller is not a member of it
+	ErrOrganizationNotFound = errors.New("organization not found")
+	// ErrNotOrganizationOwner is returned when a member tries an owner-only action
+	ErrNotOrganizationOwner = errors.New("only organization owners can do this")
+	// ErrInvalidOrganizationRole is returned for roles other than owner and member
+	ErrInvalidOrganizationRole = errors.New("role must be owner or member")
+)
+
+type OrganizationService struct {
+	orgs   repository.OrganizationRepository
+	logger *logrus.Logger
+}
+
+func NewOrganizationService(orgs repository.OrganizationRepository, logger *logrus.Logger) *OrganizationService {
+	return &OrganizationService{
+		orgs:   orgs,
+		logger: logger,
+	}
+}
+
+func (s *OrganizationService) Create(ctx context.Context, userID string, req *models.OrganizationRequest) (*models.Organization, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	org := &models.Organization{
+		ID:        uuid.New(),
+		Name:      strings.TrimSpace(req.Name),
+		CreatedAt: time.Now(),
+	}
+	if err := s.orgs.Create(ctx, org, userUUID); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Infof("Organization created: %s", org.ID)
+	return org, nil
+}
+
+func (s *OrganizationService) ListForUser(ctx context.Context, userID string) ([]*models.Organization, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	return s.orgs.ListByUser(ctx, userUUID)
+}
+
+// AddMember adds a user to the organization or changes their role. Only
+// owners can manage members.
+func (s *OrganizationService) AddMember(ctx context.Context, userID string, orgID uuid.UUID, req *models.OrganizationMemberRequest) (*models.OrganizationMember, error) {
+	if err := s.RequireOwner(ctx, userID, orgID); err != nil {
+		return nil, err
+	}
+
+	role := req.Role
+	if role == "" {
+		role = models.OrgRoleMember
+	}
+	if role != models.OrgRoleOwner && role != models.OrgRoleMember {
+		return nil, ErrInvalidOrganizationRole
+	}
+
+	member := &models.OrganizationMember{
+		OrganizationID: orgID,
+		UserID:         req.UserID,
+		Role:           role,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.orgs.AddMember(ctx, member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RequireOwner returns nil if the user owns the organization
+func (s *OrganizationService) RequireOwner(ctx context.Context, userID string, orgID uuid.UUID) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	member, err := s.orgs.GetMember(ctx, orgID, userUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrOrganizationNotFound
+		}
+		return err
+	}
+	if member.Role != models.OrgRoleOwner {
+		return ErrNotOrganizationOwner
+	}
+	return nil
+}
+
+// SetPolicy replaces the organization's policy. Only owners can change it.
+func (s *OrganizationService) SetPolicy(ctx context.Context, userID string, orgID uuid.UUID, policy *models.OrganizationPolicy) (*models.Organization, error) {
+	if err := s.RequireOwner(ctx, userID, orgID); err != nil {
+		return nil, err
+	}
+
+	if err := s.orgs.SetPolicy(ctx, orgID, *policy); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	org, err := s.orgs.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Infof("Organization policy updated: %s", orgID)
+	return org, nil
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"fact-check/internal/langdetect"
+	"fact-check/internal/models"
+	"fact-check/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrPromptNotFound is returned for unknown prompt versions
+	ErrPromptNotFound = errors.New("prompt version not found")
+	// ErrInvalidPrompt is returned for templates that fail to parse or render
+	ErrInvalidPrompt = errors.New("invalid prompt template")
+)
+
+var (
+	//go:embed prompts/system.txt
+	builtinSystemPrompt string
+	//go:embed prompts/verify.tmpl
+	builtinVerifyTemplate string
+
+	// builtinPrompt is used until a published version is in rotation
+	builtinPrompt = mustParsePrompt(nil, strings.TrimSpace(builtinSystemPrompt), builtinVerifyTemplate)
+)
+
+// promptFuncs are the functions available to prompt templates
+var promptFuncs = template.FuncMap{
+	"languageName": langdetect.Name,
+	"isEnglish": func(code string) bool {
+		return langdetect.Base(code) == "en"
+	},
+}
+
+// PromptTemplate is a parsed verification prompt. The template is executed
+// with the *VerificationRequest.
+type PromptTemplate struct {
+	// Version is the published version, nil for the built-in prompt
+	Version *int
+	System  string
+	user    *template.Template
+}
+
+// ParsePromptTemplate parses a prompt template
+func ParsePromptTemplate(version *int, system, text string) (*PromptTemplate, error) {
+	user, err := template.New("verify").Funcs(promptFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPrompt, err)
+	}
+	return &PromptTemplate{Version: version, System: system, user: user}, nil
+}
+
+func mustParsePrompt(version *int, system, text string) *PromptTemplate {
+	prompt, err := ParsePromptTemplate(version, system, text)
+	if err != nil {
+		panic(err)
+	}
+	return prompt
+}
+
+// Render returns the user prompt for a verification request
+func (p *PromptTemplate) Render(request *VerificationRequest) (string, error) {
+	var b strings.Builder
+	if err := p.user.Execute(&b, request); err != nil {
+		return "", fmt.Errorf("failed to render prompt template: %w", err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// PromptService publishes versioned prompt templates and assigns one to
+// each verification, weighted by the versions' A/B weights
+type PromptService struct {
+	prompts repository.PromptRepository
+	logger  *logrus.Logger
+
+	// parsed caches templates by version; published templates never change
+	parsed sync.Map
+	mu     sync.Mutex
+	rand   *rand.Rand
+}
+
+func NewPromptService(prompts repository.PromptRepository, logger *logrus.Logger) *PromptService {
+	return &PromptService{
+		prompts: prompts,
+		logger:  logger,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Publish stores a new prompt version after checking that it renders
+func (s *PromptService) Publish(ctx context.Context, userID string, req *models.PromptVersionRequest) (*models.PromptVersion, error) {
+	if strings.TrimSpace(req.System) == "" || strings.TrimSpace(req.Template) == "" {
+		return nil, fmt.Errorf("%w: system and template must not be blank", ErrInvalidPrompt)
+	}
+	parsed, err := ParsePromptTemplate(nil, req.System, req.Template)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parsed.Render(samplePromptRequest); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPrompt, err)
+	}
+
+	now := time.Now()
+	prompt := &models.PromptVersion{
+		Description: req.Description,
+		System:      req.System,
+		Template:    req.Template,
+		Weight:      req.Weight,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if id, err := uuid.Parse(userID); err == nil {
+		prompt.CreatedBy = &id
+	}
+	if err := s.prompts.Create(ctx, prompt); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Infof("Prompt version %d published with weight %d", prompt.Version, prompt.Weight)
+	return prompt, nil
+}
+
+func (s *PromptService) Get(ctx context.Context, version int) (*models.PromptVersion, error) {
+	prompt, err := s.prompts.Get(ctx, version)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrPromptNotFound
+	}
+	return prompt, err
+}
+
+// List returns all published versions, newest first
+func (s *PromptService) List(ctx context.Context) ([]*models.PromptVersion, error) {
+	return s.prompts.List(ctx)
+}
+
+// SetWeight changes a version's share of verifications; 0 takes it out of
+// rotation
+func (s *PromptService) SetWeight(ctx context.Context, version, weight int) (*models.PromptVersion, error) {
+	if err := s.prompts.SetWeight(ctx, version, weight); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrPromptNotFound
+		}
+		return nil, err
+	}
+	s.logger.WithContext(ctx).Infof("Prompt version %d weight set to %d", version, weight)
+	return s.Get(ctx, version)
+}
+
+// Assign picks the prompt for one verification among the versions in
+// rotation, each with probability proportional to its weight. Without any
+// it returns the built-in prompt.
+func (s *PromptService) Assign(ctx context.Context) (*PromptTemplate, error) {
+	active, err := s.prompts.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for _, prompt := range active {
+		total += prompt.Weight
+	}
+	if total == 0 {
+		return builtinPrompt, nil
+	}
+
+	s.mu.Lock()
+	pick := s.rand.Intn(total)
+	s.mu.Unlock()
+	for _, prompt := range active {
+		if pick < prompt.Weight {
+			return s.template(prompt)
+		}
+		pick -= prompt.Weight
+	}
+	return builtinPrompt, nil
+}
+
+func (s *PromptService) template(prompt *models.PromptVersion) (*PromptTemplate, error) {
+	if cached, ok := s.parsed.Load(prompt.Version); ok {
+		return cached.(*PromptTemplate), nil
+	}
+	version := prompt.Version
+	parsed, err := ParsePromptTemplate(&version, prompt.System, prompt.Template)
+	if err != nil {
+		return nil, fmt.Errorf("prompt version %d: %w", version, err)
+	}
+	s.parsed.Store(version, parsed)
+	return parsed, nil
+}
+
+// samplePromptRequest has every field set so that publishing catches
+// templates referring to fields that do not exist
+var samplePromptRequest = &VerificationRequest{
+	Content:     "Ejemplo de afirmación",
+	Link:        "https://example.com/story",
+	PhotoURL:    "https://example.com/photo.jpg",
+	Source:      &models.Source{Domain: "example.com", Name: "Example", Credibility: models.CredibilityMixed, Ownership: "Example Media", BiasNotes: "None", Satire: true},
+	Language:    "es",
+	Translation: "Example claim",
+	Locale:      "pt-BR",
+}
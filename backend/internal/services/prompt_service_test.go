@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+
+	"fact-check/internal/models"
+)
+
+func TestBuiltinPromptRender(t *testing.T) {
+	prompt, err := builtinPrompt.Render(&VerificationRequest{
+		Content:  "Prices will fall",
+		Link:     "https://example.com/story",
+		Source:   &models.Source{Domain: "example.com", Credibility: models.CredibilityLow, Satire: true},
+		Language: "es",
+		Locale:   "pt-BR",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "Please fact-check the following news content:\n\n" +
+		"Content: Prices will fall\n" +
+		"Language: Spanish\n" +
+		"Source Link: https://example.com/story\n" +
+		"\nSource profile for example.com (from our source registry):\n" +
+		"- Credibility: low\n" +
+		"- Known satire site: its content is not meant to be taken as fact\n" +
+		"\nPlease respond with:\n1. A clear assessment: 'TRUE', 'FALSE', or 'UNCERTAIN'\n2. A detailed explanation for your assessment\n3. Any relevant context or sources you considered\n4. Your confidence in the assessment as a percentage, written as 'Confidence: NN%'" +
+		"\n\nAssess the claim in Spanish, without translating it first, so wording and local context are not lost." +
+		"\n\nWrite the explanation in Portuguese (pt-BR), but keep the assessment word TRUE, FALSE or UNCERTAIN in English."
+	if prompt != want {
+		t.Fatalf("unexpected prompt:\n%s\nwant:\n%s", prompt, want)
+	}
+
+	prompt, err = builtinPrompt.Render(&VerificationRequest{Content: "claim"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "Please fact-check the following news content:\n\nContent: claim\n\nPlease respond with:\n1. A clear assessment: 'TRUE', 'FALSE', or 'UNCERTAIN'\n2. A detailed explanation for your assessment\n3. Any relevant context or sources you considered\n4. Your confidence in the assessment as a percentage, written as 'Confidence: NN%'"; prompt != want {
+		t.Fatalf("unexpected minimal prompt:\n%s", prompt)
+	}
+}
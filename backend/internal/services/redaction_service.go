@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+
+	"fact-check/internal/config"
+	"fact-check/internal/redact"
+	"fact-check/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// RedactionService decides whose content is redacted before it is sent to
+// an LLM and with which detectors
+type RedactionService struct {
+	orgs     repository.OrganizationRepository
+	settings atomic.Pointer[redactionSettings]
+	logger   *logrus.Logger
+}
+
+// redactionSettings holds the server default that can change on config reload
+type redactionSettings struct {
+	enabled  bool
+	redactor *redact.Redactor
+}
+
+func NewRedactionService(cfg *config.Config, orgs repository.OrganizationRepository, logger *logrus.Logger) *RedactionService {
+	service := &RedactionService{
+		orgs:   orgs,
+		logger: logger,
+	}
+	service.ApplyConfig(cfg)
+	return service
+}
+
+// ApplyConfig switches the default policy and detectors used from now on
+func (s *RedactionService) ApplyConfig(cfg *config.Config) {
+	redactor, err := redact.NewBuiltin(cfg.Redaction.Detectors)
+	if err != nil {
+		// Validate only lets known detectors through
+		s.logger.Errorf("Failed to apply redaction settings, keeping the current ones: %v", err)
+		return
+	}
+	s.settings.Store(&redactionSettings{enabled: cfg.Redaction.Enabled, redactor: redactor})
+}
+
+// Session starts redacting content owned by ownerID, or returns nil when it
+// is sent as is. The policies of the owner's organizations decide, and one
+// that redacts outweighs one that does not; owners outside organizations,
+// or whose organizations have no policy, get the server default. Content
+// is redacted when the organizations cannot be loaded.
+func (s *RedactionService) Session(ctx context.Context, ownerID uuid.UUID) *redact.Session {
+	settings := s.settings.Load()
+
+	orgs, err := s.orgs.ListByUser(ctx, ownerID)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Failed to load organization policies, redacting: %v", err)
+		return settings.redactor.Session()
+	}
+
+	enabled := settings.enabled
+	for _, org := range orgs {
+		if org.Policy.RedactPII == nil {
+			continue
+		}
+		enabled = *org.Policy.RedactPII
+		if enabled {
+			break
+		}
+	}
+	if !enabled {
+		return nil
+	}
+	return settings.redactor.Session()
+}
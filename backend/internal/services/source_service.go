@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fact-check/internal/models"
+	"fact-check/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrSourceNotFound is returned when a domain is not in the registry
+	ErrSourceNotFound = errors.New("source not found")
+	// ErrInvalidSource is returned for malformed registry entries or imports
+	ErrInvalidSource = errors.New("invalid source")
+)
+
+// SourceService manages the source credibility registry and matches
+// submitted links against it
+type SourceService struct {
+	sources repository.SourceRepository
+	logger  *logrus.Logger
+}
+
+func NewSourceService(sources repository.SourceRepository, logger *logrus.Logger) *SourceService {
+	return &SourceService{
+		sources: sources,
+		logger:  logger,
+	}
+}
+
+// NormalizeDomain reduces a domain or URL to the lowercase host name the
+// registry is keyed by, without port, trailing dot or leading "www."
+func NormalizeDomain(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	host := raw
+	if strings.Contains(raw, "://") {
+		parsed, err := url.Parse(raw)
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid URL %q", ErrInvalidSource, raw)
+		}
+		host = parsed.Hostname()
+	} else if h, _, err := net.SplitHostPort(raw); err == nil {
+		host = h
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	host = strings.TrimPrefix(host, "www.")
+	if len(host) > 253 || !strings.Contains(host, ".") || net.ParseIP(host) != nil {
+		return "", fmt.Errorf("%w: %q is not a domain name", ErrInvalidSource, raw)
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return "", fmt.Errorf("%w: %q is not a domain name", ErrInvalidSource, raw)
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				return "", fmt.Errorf("%w: %q is not a domain name", ErrInvalidSource, raw)
+			}
+		}
+	}
+	return host, nil
+}
+
+// Put creates or replaces the registry entry for a domain
+func (s *SourceService) Put(ctx context.Context, domain string, req *models.SourceRequest) (*models.Source, error) {
+	source, err := newSource(domain, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sources.Upsert(ctx, source); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Infof("Source %s rated %s", source.Domain, source.Credibility)
+	return source, nil
+}
+
+func newSource(domain string, req *models.SourceRequest) (*models.Source, error) {
+	normalized, err := NormalizeDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidSource)
+	}
+	credibility := strings.ToLower(strings.TrimSpace(req.Credibility))
+	if !slices.Contains(models.SourceCredibilities, credibility) {
+		return nil, fmt.Errorf("%w: credibility must be one of %s", ErrInvalidSource, strings.Join(models.SourceCredibilities, ", "))
+	}
+
+	now := time.Now()
+	return &models.Source{
+		ID:          uuid.New(),
+		Domain:      normalized,
+		Name:        name,
+		Credibility: credibility,
+		Ownership:   strings.TrimSpace(req.Ownership),
+		BiasNotes:   strings.TrimSpace(req.BiasNotes),
+		Satire:      req.Satire,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+func (s *SourceService) Get(ctx context.Context, domain string) (*models.Source, error) {
+	normalized, err := NormalizeDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	source, err := s.sources.GetByDomain(ctx, normalized)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrSourceNotFound
+	}
+	return source, err
+}
+
+func (s *SourceService) List(ctx context.Context) ([]*models.Source, error) {
+	return s.sources.List(ctx)
+}
+
+func (s *SourceService) Delete(ctx context.Context, domain string) error {
+	normalized, err := NormalizeDomain(domain)
+	if err != nil {
+		return err
+	}
+	if err := s.sources.Delete(ctx, normalized); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrSourceNotFound
+		}
+		return err
+	}
+
+	s.logger.WithContext(ctx).Infof("Source %s removed", normalized)
+	return nil
+}
+
+// Import upserts registry entries from CSV with a header row naming domain,
+// name and credibility columns and optional ownership, bias_notes and satire
+// columns. Invalid rows are reported and skipped.
+func (s *SourceService) Import(ctx context.Context, r io.Reader) (*models.SourceImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read CSV header: %w", ErrInvalidSource, err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"domain", "name", "credibility"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: CSV header must include a %s column", ErrInvalidSource, required)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	result := &models.SourceImportResult{}
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: row %d: %w", ErrInvalidSource, row, err)
+		}
+
+		req := &models.SourceRequest{
+			Name:        field(record, "name"),
+			Credibility: field(record, "credibility"),
+			Ownership:   field(record, "ownership"),
+			BiasNotes:   field(record, "bias_notes"),
+		}
+		var source *models.Source
+		if req.Satire, err = parseCSVBool(field(record, "satire")); err == nil {
+			source, err = newSource(field(record, "domain"), req)
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, models.BatchRowError{Row: row, Error: strings.TrimPrefix(err.Error(), ErrInvalidSource.Error()+": ")})
+			continue
+		}
+		if err := s.sources.Upsert(ctx, source); err != nil {
+			return nil, err
+		}
+		result.Imported++
+	}
+	result.Rejected = len(result.Errors)
+
+	s.logger.WithContext(ctx).Infof("Imported %d sources, rejected %d", result.Imported, result.Rejected)
+	return result, nil
+}
+
+func parseCSVBool(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(strings.ToLower(value))
+	if err != nil {
+		return false, fmt.Errorf("%w: satire must be true or false", ErrInvalidSource)
+	}
+	return parsed, nil
+}
+
+// Match returns the registry entry for a link's domain or the closest
+// parent domain listed, or nil when the source is unknown
+func (s *SourceService) Match(ctx context.Context, link string) (*models.Source, error) {
+	if link == "" {
+		return nil, nil
+	}
+	domain, err := NormalizeDomain(link)
+	if err != nil {
+		// Unparseable links simply have no profile
+		return nil, nil
+	}
+
+	// news.example.co.uk is looked up as itself, example.co.uk and co.uk
+	var candidates []string
+	for labels := strings.Split(domain, "."); len(labels) >= 2; labels = labels[1:] {
+		candidates = append(candidates, strings.Join(labels, "."))
+	}
+	sources, err := s.sources.FindByDomains(ctx, candidates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up source: %w", err)
+	}
+
+	var best *models.Source
+	for _, source := range sources {
+		if best == nil || len(source.Domain) > len(best.Domain) {
+			best = source
+		}
+	}
+	return best, nil
+}
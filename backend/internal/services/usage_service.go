@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"fact-check/internal/config"
+	"fact-check/internal/models"
+	"fact-check/internal/repository"
+	"fact-check/internal/tracing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// BudgetExceededError is returned when an LLM budget has been used up
+type BudgetExceededError struct {
+	Scope    string
+	Period   string
+	LimitUSD float64
+	SpentUSD float64
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("%s %s LLM budget exceeded: spent $%.4f of $%.4f", e.Scope, e.Period, e.SpentUSD, e.LimitUSD)
+}
+
+type UsageService struct {
+	config *config.Config
+	policy atomic.Pointer[usagePolicy]
+	usage  repository.UsageRepository
+	users  repository.UserRepository
+	logger *logrus.Logger
+}
+
+// usagePolicy holds the prices and budgets that can change on config reload
+type usagePolicy struct {
+	prices  map[string]config.ModelPrice
+	budgets config.BudgetConfig
+}
+
+func NewUsageService(cfg *config.Config, usage repository.UsageRepository, users repository.UserRepository, logger *logrus.Logger) *UsageService {
+	service := &UsageService{
+		config: cfg,
+		usage:  usage,
+		users:  users,
+		logger: logger,
+	}
+	service.ApplyConfig(cfg)
+	return service
+}
+
+// ApplyConfig switches the price table and budgets used from now on
+func (s *UsageService) ApplyConfig(cfg *config.Config) {
+	s.policy.Store(&usagePolicy{prices: cfg.LLMPrices, budgets: cfg.Budgets})
+}
+
+// EstimateCost returns the USD cost of a call using the configured price table
+func (s *UsageService) EstimateCost(model string, usage models.TokenUsage) float64 {
+	price, ok := s.policy.Load().prices[model]
+	if !ok {
+		s.logger.Warnf("No price configured for model %s, recording zero cost", model)
+		return 0
+	}
+
+	cost := float64(usage.PromptTokens)/1000*price.PromptPer1K +
+		float64(usage.CompletionTokens)/1000*price.CompletionPer1K
+	return math.Round(cost*1e6) / 1e6
+}
+
+// RecordUsage stores the token usage and estimated cost of an LLM call
+func (s *UsageService) RecordUsage(ctx context.Context, userID string, newsID *uuid.UUID, model string, usage models.TokenUsage) (*models.LLMUsage, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "UsageService.RecordUsage")
+	defer span.End()
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+
+	record := &models.LLMUsage{
+		ID:               uuid.New(),
+		UserID:           userUUID,
+		NewsID:           newsID,
+		Model:            model,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		CostUSD:          s.EstimateCost(model, usage),
+		CreatedAt:        time.Now(),
+	}
+
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
+
+	if err := s.usage.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Infof("Recorded LLM usage for user %s: %d tokens, $%.6f", userID, record.TotalTokens, record.CostUSD)
+	return record, nil
+}
+
+// CheckBudget returns a *BudgetExceededError if the user, their role or the
+// whole service has used up a daily or monthly budget
+func (s *UsageService) CheckBudget(ctx context.Context, userID string) error {
+	ctx, span := tracing.Tracer().Start(ctx, "UsageService.CheckBudget")
+	defer span.End()
+
+	statuses, err := s.budgetStatuses(ctx, userID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		if status.SpentUSD >= status.LimitUSD {
+			return &BudgetExceededError{
+				Scope:    status.Scope,
+				Period:   status.Period,
+				LimitUSD: status.LimitUSD,
+				SpentUSD: status.SpentUSD,
+			}
+		}
+	}
+
+	return nil
+}
+
+// UserReport returns the usage of a single user between from and to
+func (s *UsageService) UserReport(ctx context.Context, userID string, from, to time.Time) (*models.UsageReport, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "UsageService.UserReport")
+	defer span.End()
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	report := &models.UsageReport{From: from, To: to, UserID: &userUUID}
+
+	byModel, err := s.summarize(ctx, repository.UsageFilter{UserID: &userUUID, From: from, To: to}, repository.GroupByModel)
+	if err != nil {
+		return nil, err
+	}
+	report.ByModel = byModel
+	report.Total = total(byModel)
+
+	budgets, err := s.budgetStatuses(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	report.Budgets = budgets
+
+	return report, nil
+}
+
+// GlobalReport returns usage across all users between from and to
+func (s *UsageService) GlobalReport(ctx context.Context, from, to time.Time) (*models.UsageReport, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "UsageService.GlobalReport")
+	defer span.End()
+
+	report := &models.UsageReport{From: from, To: to}
+	filter := repository.UsageFilter{From: from, To: to}
+
+	byModel, err := s.summarize(ctx, filter, repository.GroupByModel)
+	if err != nil {
+		return nil, err
+	}
+	report.ByModel = byModel
+	report.Total = total(byModel)
+
+	byUser, err := s.summarize(ctx, filter, repository.GroupByUser)
+	if err != nil {
+		return nil, err
+	}
+	report.ByUser = byUser
+
+	now := time.Now()
+	budgets := s.policy.Load().budgets
+	for _, period := range []string{"daily", "monthly"} {
+		limit := budgets.GlobalDaily
+		if period == "monthly" {
+			limit = budgets.GlobalMonthly
+		}
+		if limit <= 0 {
+			continue
+		}
+		spent, err := s.spend(ctx, repository.UsageFilter{From: periodStart(period, now)})
+		if err != nil {
+			return nil, err
+		}
+		report.Budgets = append(report.Budgets, newBudgetStatus("global", period, limit, spent))
+	}
+
+	return report, nil
+}
+
+// budgetStatuses returns the configured budgets that apply to the user
+func (s *UsageService) budgetStatuses(ctx context.Context, userID string, now time.Time) ([]models.BudgetStatus, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	userCtx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
+
+	user, err := s.users.GetByID(userCtx, userUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user role: %w", err)
+	}
+	role := user.Role
+
+	budgets := s.policy.Load().budgets
+	type budget struct {
+		scope  string
+		period string
+		limit  float64
+		filter repository.UsageFilter
+	}
+	candidates := []budget{
+		{"user", "daily", budgets.UserDaily, repository.UsageFilter{UserID: &userUUID}},
+		{"user", "monthly", budgets.UserMonthly, repository.UsageFilter{UserID: &userUUID}},
+		{"role:" + role, "daily", budgets.RoleDaily[role], repository.UsageFilter{Role: role}},
+		{"role:" + role, "monthly", budgets.RoleMonthly[role], repository.UsageFilter{Role: role}},
+		{"global", "daily", budgets.GlobalDaily, repository.UsageFilter{}},
+		{"global", "monthly", budgets.GlobalMonthly, repository.UsageFilter{}},
+	}
+
+	var statuses []models.BudgetStatus
+	for _, b := range candidates {
+		if b.limit <= 0 {
+			continue
+		}
+		b.filter.From = periodStart(b.period, now)
+		spent, err := s.spend(ctx, b.filter)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, newBudgetStatus(b.scope, b.period, b.limit, spent))
+	}
+
+	return statuses, nil
+}
+
+func (s *UsageService) spend(ctx context.Context, filter repository.UsageFilter) (float64, error) {
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
+
+	return s.usage.Spend(ctx, filter)
+}
+
+func (s *UsageService) summarize(ctx context.Context, filter repository.UsageFilter, groupBy repository.UsageGroup) ([]models.UsageSummary, error) {
+	ctx, cancel := withTimeout(ctx, s.config.Timeouts.DBQuery)
+	defer cancel()
+
+	return s.usage.Summarize(ctx, filter, groupBy)
+}
+
+func total(summaries []models.UsageSummary) models.UsageSummary {
+	sum := models.UsageSummary{Key: "total"}
+	for _, summary := range summaries {
+		sum.Calls += summary.Calls
+		sum.PromptTokens += summary.PromptTokens
+		sum.CompletionTokens += summary.CompletionTokens
+		sum.TotalTokens += summary.TotalTokens
+		sum.CostUSD += summary.CostUSD
+	}
+	return sum
+}
+
+func newBudgetStatus(scope, period string, limit, spent float64) models.BudgetStatus {
+	return models.BudgetStatus{
+		Scope:        scope,
+		Period:       period,
+		LimitUSD:     limit,
+		SpentUSD:     spent,
+		RemainingUSD: math.Max(limit-spent, 0),
+	}
+}
+
+// periodStart returns the start of the current UTC day or month
+func periodStart(period string, now time.Time) time.Time {
+	now = now.UTC()
+	if period == "monthly" {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fact-check/internal/config"
+	"fact-check/internal/models"
+	"fact-check/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// VerificationQueue runs queued verification jobs on a pool of workers
+type VerificationQueue struct {
+	config        config.QueueConfig
+	timeout       time.Duration
+	jobs          repository.VerificationJobRepository
+	verifications *VerificationService
+	wake          chan struct{}
+	logger        *logrus.Logger
+
+	// workers counts running workers and lastActive is when one of them last
+	// polled or finished a job, in Unix nanoseconds
+	workers    atomic.Int32
+	lastActive atomic.Int64
+}
+
+func NewVerificationQueue(cfg *config.Config, jobs repository.VerificationJobRepository, verifications *VerificationService, logger *logrus.Logger) *VerificationQueue {
+	return &VerificationQueue{
+		config:        cfg.Queue,
+		timeout:       cfg.Timeouts.LLMVerify,
+		jobs:          jobs,
+		verifications: verifications,
+		wake:          make(chan struct{}, cfg.Queue.Workers),
+		logger:        logger,
+	}
+}
+
+// Notify wakes idle workers after new jobs were queued
+func (q *VerificationQueue) Notify() {
+	for {
+		select {
+		case q.wake <- struct{}{}:
+		default:
+			return
+		}
+	}
+}
+
+// Run processes jobs with the configured number of workers until ctx is done
+func (q *VerificationQueue) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < q.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.workers.Add(1)
+			defer q.workers.Add(-1)
+			q.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (q *VerificationQueue) work(ctx context.Context) {
+	ticker := time.NewTicker(q.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		q.lastActive.Store(time.Now().UnixNano())
+		processed, err := q.ProcessNext(ctx)
+		if err != nil {
+			q.logger.WithContext(ctx).Errorf("Failed to claim verification job: %v", err)
+		}
+		if processed && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// Probe reports the job backlog and fails when no worker is running, or when
+// none has polled for longer than a job may take
+func (q *VerificationQueue) Probe(ctx context.Context) (map[string]interface{}, error) {
+	backlog, err := q.jobs.Backlog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := int(q.workers.Load())
+	details := map[string]interface{}{
+		"queued":  backlog.Queued,
+		"running": backlog.Running,
+		"workers": workers,
+	}
+	if backlog.OldestQueuedAt != nil {
+		details["oldest_queued_age"] = time.Since(*backlog.OldestQueuedAt).Round(time.Second).String()
+	}
+	if workers == 0 {
+		return details, errors.New("no queue workers running")
+	}
+
+	lastActive := time.Unix(0, q.lastActive.Load())
+	details["last_active"] = lastActive
+	if idle := time.Since(lastActive); idle > q.timeout+2*q.config.PollInterval {
+		return details, fmt.Errorf("queue workers stalled for %s", idle.Round(time.Second))
+	}
+	return details, nil
+}
+
+// ProcessNext claims and runs one job, reporting whether there was one
+func (q *VerificationQueue) ProcessNext(ctx context.Context) (bool, error) {
+	// A job running longer than twice the verification timeout belongs to a
+	// worker that died and is picked up again
+	jobs, err := q.jobs.Claim(ctx, time.Now(), 2*q.timeout, 1)
+	if err != nil || len(jobs) == 0 {
+		return false, err
+	}
+
+	q.run(ctx, jobs[0])
+	return true, nil
+}
+
+// run verifies the job's news item and saves the outcome. Failures that may
+// be transient are re-queued until the attempt limit is reached.
+func (q *VerificationQueue) run(ctx context.Context, job *models.VerificationJob) {
+	logger := q.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"job_id":  job.ID,
+		"news_id": job.NewsID,
+	})
+
+	verifyCtx, cancel := withTimeout(ctx, q.timeout)
+	verification, err := q.verifications.Verify(verifyCtx, job.UserID.String(), job.NewsID.String(), nil)
+	cancel()
+	if ctx.Err() != nil {
+		// Shutting down; the job is reclaimed once its lease runs out
+		return
+	}
+
+	now := time.Now()
+	job.Error = nil
+	var budgetErr *BudgetExceededError
+	switch {
+	case err == nil:
+		job.Status = models.JobSucceeded
+		job.Verdict = &verification.Status
+		job.FinishedAt = &now
+	case errors.Is(err, ErrNewsNotFound), errors.Is(err, ErrHeldByModeration), errors.As(err, &budgetErr), job.Attempts >= q.config.MaxAttempts:
+		message := err.Error()
+		job.Status = models.JobFailed
+		job.Error = &message
+		job.FinishedAt = &now
+		logger.Warnf("Verification job failed after %d attempts: %v", job.Attempts, err)
+	default:
+		message := err.Error()
+		job.Status = models.JobQueued
+		job.Error = &message
+		logger.Infof("Verification job attempt %d failed, re-queued: %v", job.Attempts, err)
+	}
+
+	if err := q.jobs.UpdateJob(ctx, job); err != nil {
+		logger.Errorf("Failed to save verification job: %v", err)
+	}
+}
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"fact-check/internal/metrics"
+	"fact-check/internal/models"
+	"fact-check/internal/redact"
+	"fact-check/internal/tracing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Verification progress event types
+const (
+	EventStage   = "stage"
+	EventDelta   = "delta"
+	EventVerdict = "verdict"
+	EventError   = "error"
+)
+
+// Verification stages reported in stage events
+const (
+	StageCheckingBudget     = "checking_budget"
+	StageRetrievingEvidence = "retrieving_evidence"
+	StageCallingModel       = "calling_model"
+	StageSavingResult       = "saving_result"
+)
+
+// VerificationEvent is a progress update emitted while a verification runs
+type VerificationEvent struct {
+	Type string
+	Data interface{}
+}
+
+// StageEvent marks the start of a verification stage
+type StageEvent struct {
+	Stage   string `json:"stage"`
+	Message string `json:"message"`
+}
+
+// DeltaEvent carries a chunk of the model's answer as it is generated
+type DeltaEvent struct {
+	Text string `json:"text"`
+}
+
+// ErrorEvent reports why a verification failed
+type ErrorEvent struct {
+	Error    string  `json:"error"`
+	Code     string  `json:"code"`
+	Scope    string  `json:"scope,omitempty"`
+	Period   string  `json:"period,omitempty"`
+	LimitUSD float64 `json:"limit_usd,omitempty"`
+	SpentUSD float64 `json:"spent_usd,omitempty"`
+}
+
+// StreamingVerifier is implemented by verifiers that can report the model's
+// answer incrementally
+type StreamingVerifier interface {
+	Verifier
+	VerifyNewsStream(ctx context.Context, request *VerificationRequest, onDelta func(string)) (*VerificationResult, error)
+}
+
+// VerifierError wraps a failure of the LLM provider
+type VerifierError struct {
+	Err error
+}
+
+func (e *VerifierError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *VerifierError) Unwrap() error {
+	return e.Err
+}
+
+// VerificationService runs a fact-check end to end: budget check, LLM call,
+// usage accounting and storing the verdict
+type VerificationService struct {
+	news      *NewsService
+	verifier  Verifier
+	usage     *UsageService
+	sources   *SourceService
+	languages *LanguageService
+	prompts   *PromptService
+	calibrate *CalibrationService
+	redaction *RedactionService
+	logger    *logrus.Logger
+}
+
+// NewVerificationService creates the service; sources may be nil when no
+// source registry is used, languages when claims are always verified and
+// explained in English, prompts when only the built-in prompt is used,
+// calibrate when stated confidences are kept and verdicts never abstain and
+// redaction when content is always sent to the model as submitted
+func NewVerificationService(news *NewsService, verifier Verifier, usage *UsageService, sources *SourceService, languages *LanguageService, prompts *PromptService, calibrate *CalibrationService, redaction *RedactionService, logger *logrus.Logger) *VerificationService {
+	return &VerificationService{
+		news:      news,
+		verifier:  verifier,
+		usage:     usage,
+		sources:   sources,
+		languages: languages,
+		prompts:   prompts,
+		calibrate: calibrate,
+		redaction: redaction,
+		logger:    logger,
+	}
+}
+
+// Verify fact-checks a news item on behalf of userID. emit, if not nil,
+// receives progress events; token deltas are only emitted when the verifier
+// supports streaming. Errors are ErrNewsNotFound, ErrHeldByModeration,
+// *BudgetExceededError, *VerifierError or a storage error.
+func (s *VerificationService) Verify(ctx context.Context, userID, newsID string, emit func(VerificationEvent)) (*models.NewsVerification, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "VerificationService.Verify")
+	defer span.End()
+
+	if emit == nil {
+		emit = func(VerificationEvent) {}
+	}
+	stage := func(name, message string) {
+		emit(VerificationEvent{Type: EventStage, Data: StageEvent{Stage: name, Message: message}})
+	}
+
+	news, err := s.news.GetNewsByID(ctx, newsID)
+	if err != nil {
+		return nil, err
+	}
+	// Batch rows are classified by the LLM before their first verification
+	if err := s.news.Screen(ctx, news); err != nil {
+		return nil, err
+	}
+	if news.Moderation != models.ModerationAllowed {
+		return nil, ErrHeldByModeration
+	}
+
+	// Refuse to call the LLM once a budget is used up
+	stage(StageCheckingBudget, "Checking LLM usage budget")
+	if err := s.usage.CheckBudget(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	request := &VerificationRequest{Content: news.Content}
+	evidence := "news content"
+	link := news.Link
+	if news.CanonicalLink != nil {
+		link = news.CanonicalLink
+	}
+	if link != nil && *link != "" {
+		request.Link = *link
+		evidence += ", source link"
+	}
+	if news.PhotoURL != nil && *news.PhotoURL != "" {
+		request.PhotoURL = *news.PhotoURL
+		evidence += ", photo URL"
+	}
+	if s.sources != nil && request.Link != "" {
+		// The profile is that of the site that served the page, whatever
+		// canonical link it declares. A failed lookup only costs the model
+		// some context.
+		served := request.Link
+		if news.FetchedHost != nil {
+			served = *news.FetchedHost
+		}
+		request.Source, err = s.sources.Match(ctx, served)
+		if err != nil {
+			s.logger.WithContext(ctx).Warnf("Failed to match news source: %v", err)
+		}
+		if request.Source != nil {
+			evidence += ", source profile for " + request.Source.Domain
+		}
+	}
+	if s.languages != nil {
+		s.languages.Prepare(ctx, userID, news, request)
+		if request.Translation != "" {
+			evidence += ", English translation"
+		}
+	}
+	stage(StageRetrievingEvidence, "Collecting evidence: "+evidence)
+
+	if s.prompts != nil {
+		// Falling back to the built-in prompt beats failing the verification
+		request.Prompt, err = s.prompts.Assign(ctx)
+		if err != nil {
+			s.logger.WithContext(ctx).Warnf("Failed to assign prompt version, using the built-in prompt: %v", err)
+		}
+	}
+
+	// Personal data is replaced with placeholders the model's answer can refer
+	// to, and filled back in once it has answered. Links can carry personal
+	// data and credentials in their query string, so they are redacted too;
+	// the source profile was already matched on the real link.
+	var redaction *redact.Session
+	if s.redaction != nil {
+		redaction = s.redaction.Session(ctx, news.UserID)
+	}
+	if redaction != nil {
+		request.Content = redaction.Redact(request.Content)
+		request.Translation = redaction.Redact(request.Translation)
+		if request.Link != "" {
+			request.Link = redaction.RedactURL(request.Link)
+		}
+		request.Redacted = redaction.Count() > 0
+		if request.Redacted {
+			s.logger.WithContext(ctx).Infof("Redacted %d personal details from news %s", redaction.Count(), news.ID)
+		}
+	}
+
+	stage(StageCallingModel, "Asking the model for a verdict")
+	var result *VerificationResult
+	if streaming, ok := s.verifier.(StreamingVerifier); ok {
+		result, err = streaming.VerifyNewsStream(ctx, request, func(text string) {
+			emit(VerificationEvent{Type: EventDelta, Data: DeltaEvent{Text: text}})
+		})
+	} else {
+		result, err = s.verifier.VerifyNews(ctx, request)
+	}
+	if err != nil {
+		return nil, &VerifierError{Err: err}
+	}
+	if request.Redacted {
+		result = restoreRedacted(result, redaction)
+	}
+
+	var promptVersion *int
+	if request.Prompt != nil {
+		promptVersion = request.Prompt.Version
+	}
+	metrics.Verifications.WithLabelValues(result.Status, result.Model, promptLabel(promptVersion)).Inc()
+
+	stage(StageSavingResult, "Saving the verdict")
+
+	// Record token usage and cost against the requesting user, per model
+	// for ensembles since prices differ
+	calls := []*VerificationResult{result}
+	if result.Members != nil {
+		calls = result.Members
+	}
+	for _, call := range calls {
+		if call.Usage.TotalTokens > 0 || call.Usage.PromptTokens > 0 {
+			if _, err := s.usage.RecordUsage(ctx, userID, &news.ID, call.Model, call.Usage); err != nil {
+				s.logger.WithContext(ctx).Errorf("Failed to record LLM usage: %v", err)
+			}
+		}
+	}
+
+	// Verdicts too uncertain for their topic are published as unverifiable
+	// and left to a reviewer
+	status, needsReview, confidence := result.Status, result.NeedsReview, result.Confidence
+	if s.calibrate != nil {
+		topic := ""
+		if news.Topic != nil {
+			topic = *news.Topic
+		}
+		var abstain bool
+		confidence, abstain = s.calibrate.Assess(result.Status, result.Confidence, topic)
+		if abstain {
+			status, needsReview = "unverifiable", true
+			metrics.VerdictsFlagged.WithLabelValues("low_confidence").Inc()
+			s.logger.WithContext(ctx).Infof("Verdict for news %s flagged for review: low_confidence (%.2f)", news.ID, confidence)
+		}
+	}
+	if result.NeedsReview {
+		reason := "disagreement"
+		if !result.Disagreement {
+			reason = "incomplete_ensemble"
+		}
+		metrics.VerdictsFlagged.WithLabelValues(reason).Inc()
+		s.logger.WithContext(ctx).Infof("Verdict for news %s flagged for review: %s", news.ID, reason)
+	}
+
+	verdict := models.Verdict{
+		Status:        status,
+		Explanation:   result.Explanation,
+		PromptVersion: promptVersion,
+		ModelVerdicts: result.Votes,
+		NeedsReview:   needsReview,
+		Confidence:    confidence,
+		RawConfidence: result.Confidence,
+		ModelStatus:   result.Status,
+	}
+	if err := s.news.UpdateNewsStatus(ctx, newsID, verdict); err != nil {
+		return nil, fmt.Errorf("failed to update news status: %w", err)
+	}
+
+	verification := &models.NewsVerification{
+		ID:            news.ID,
+		Status:        status,
+		Explanation:   result.Explanation,
+		Model:         result.Model,
+		Source:        request.Source,
+		Language:      request.Language,
+		Locale:        request.Locale,
+		PromptVersion: promptVersion,
+		ModelVerdicts: result.Votes,
+		NeedsReview:   needsReview,
+		Confidence:    confidence,
+		RawConfidence: result.Confidence,
+	}
+	if status != result.Status {
+		verification.ModelStatus = result.Status
+	}
+	emit(VerificationEvent{Type: EventVerdict, Data: verification})
+
+	return verification, nil
+}
+
+// restoreRedacted returns a copy of result with the redacted values back in
+// place of their placeholders in every explanation
+func restoreRedacted(result *VerificationResult, redaction *redact.Session) *VerificationResult {
+	restored := *result
+	restored.Explanation = redaction.Restore(result.Explanation)
+	if result.Votes != nil {
+		restored.Votes = make([]models.ModelVerdict, len(result.Votes))
+		for i, vote := range result.Votes {
+			vote.Explanation = redaction.Restore(vote.Explanation)
+			restored.Votes[i] = vote
+		}
+	}
+	return &restored
+}
+
+// promptLabel is the metrics label of a prompt version
+func promptLabel(version *int) string {
+	if version == nil {
+		return "builtin"
+	}
+	return strconv.Itoa(*version)
+}
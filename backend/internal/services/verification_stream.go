@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// StreamEvent is a verification event with an ID clients can resume from.
+// IDs have the form "<run id>:<sequence>".
+type StreamEvent struct {
+	ID   string
+	Type string
+	Data interface{}
+}
+
+// VerificationStreams runs streamed verifications in the background and fans
+// their events out to SSE clients. A run outlives the request that started
+// it, and its events are kept for the retention period after it finishes so
+// a client can reconnect with Last-Event-ID and resume where it left off.
+type VerificationStreams struct {
+	verifications *VerificationService
+	timeout       time.Duration
+	retention     time.Duration
+	logger        *logrus.Logger
+
+	mu sync.Mutex
+	// runs are keyed by runKey so users only follow the runs they started
+	// and are billed for
+	runs map[string]*verificationRun
+}
+
+type verificationRun struct {
+	id string
+
+	mu      sync.Mutex
+	events  []StreamEvent
+	done    bool
+	updated chan struct{}
+}
+
+func NewVerificationStreams(verifications *VerificationService, timeout, retention time.Duration, logger *logrus.Logger) *VerificationStreams {
+	return &VerificationStreams{
+		verifications: verifications,
+		timeout:       timeout,
+		retention:     retention,
+		logger:        logger,
+		runs:          make(map[string]*verificationRun),
+	}
+}
+
+// runKey identifies the run of a user's verification of a news item
+func runKey(userID, newsID string) string {
+	return userID + ":" + newsID
+}
+
+// Subscribe returns the user's verification events for newsID. A client
+// that sends a Last-Event-ID from a retained run gets the events after it;
+// otherwise it joins the run in progress from the start or a new run is
+// started. The channel is closed after the final verdict or error event, or
+// when ctx is done.
+func (s *VerificationStreams) Subscribe(ctx context.Context, userID, newsID, lastEventID string) <-chan StreamEvent {
+	run, cursor := s.attach(ctx, userID, newsID, lastEventID)
+
+	events := make(chan StreamEvent)
+	go func() {
+		defer close(events)
+		for {
+			run.mu.Lock()
+			pending := append([]StreamEvent(nil), run.events[cursor:]...)
+			done := run.done
+			updated := run.updated
+			run.mu.Unlock()
+
+			for _, event := range pending {
+				select {
+				case events <- event:
+					cursor++
+				case <-ctx.Done():
+					return
+				}
+			}
+			if done {
+				return
+			}
+
+			select {
+			case <-updated:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// attach finds the run to follow and the index of the first event to send
+func (s *VerificationStreams) attach(ctx context.Context, userID, newsID, lastEventID string) (*verificationRun, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := runKey(userID, newsID)
+	run, ok := s.runs[key]
+	if ok {
+		run.mu.Lock()
+		received := len(run.events)
+		inProgress := !run.done
+		run.mu.Unlock()
+
+		if runID, seq, valid := parseEventID(lastEventID); valid && runID == run.id {
+			return run, min(seq, received)
+		}
+		if inProgress {
+			return run, 0
+		}
+	}
+
+	run = &verificationRun{id: uuid.NewString(), updated: make(chan struct{})}
+	s.runs[key] = run
+
+	// The run continues if the client disconnects, so it keeps the request's
+	// trace and log fields but not its cancellation
+	runCtx, cancel := withTimeout(context.WithoutCancel(ctx), s.timeout)
+	go func() {
+		defer cancel()
+		s.execute(runCtx, run, userID, newsID)
+		time.AfterFunc(s.retention, func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.runs[key] == run {
+				delete(s.runs, key)
+			}
+		})
+	}()
+
+	return run, 0
+}
+
+func (s *VerificationStreams) execute(ctx context.Context, run *verificationRun, userID, newsID string) {
+	_, err := s.verifications.Verify(ctx, userID, newsID, func(event VerificationEvent) {
+		run.publish(event.Type, event.Data, false)
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("Streamed verification of news %s failed: %v", newsID, err)
+		run.publish(EventError, verificationErrorEvent(err), true)
+		return
+	}
+	run.finish()
+}
+
+func (r *verificationRun) publish(eventType string, data interface{}, last bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, StreamEvent{
+		ID:   fmt.Sprintf("%s:%d", r.id, len(r.events)+1),
+		Type: eventType,
+		Data: data,
+	})
+	if last {
+		r.done = true
+	}
+	close(r.updated)
+	r.updated = make(chan struct{})
+}
+
+func (r *verificationRun) finish() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.done = true
+	close(r.updated)
+	r.updated = make(chan struct{})
+}
+
+// parseEventID splits "<run id>:<sequence>"; the sequence is also the number
+// of events the client has already received
+func parseEventID(id string) (string, int, bool) {
+	runID, rawSeq, ok := strings.Cut(id, ":")
+	if !ok {
+		return "", 0, false
+	}
+	seq, err := strconv.Atoi(rawSeq)
+	if err != nil || seq < 0 {
+		return "", 0, false
+	}
+	return runID, seq, true
+}
+
+// verificationErrorEvent maps a verification error to the payload sent to clients
+func verificationErrorEvent(err error) ErrorEvent {
+	var budgetErr *BudgetExceededError
+	var verifierErr *VerifierError
+	switch {
+	case errors.Is(err, ErrNewsNotFound):
+		return ErrorEvent{Error: "News not found", Code: "not_found"}
+	case errors.Is(err, ErrHeldByModeration):
+		return ErrorEvent{Error: "News is held by moderation", Code: "held_by_moderation"}
+	case errors.As(err, &budgetErr):
+		return ErrorEvent{
+			Error:    budgetErr.Error(),
+			Code:     "budget_exceeded",
+			Scope:    budgetErr.Scope,
+			Period:   budgetErr.Period,
+			LimitUSD: budgetErr.LimitUSD,
+			SpentUSD: budgetErr.SpentUSD,
+		}
+	case errors.As(err, &verifierErr):
+		return ErrorEvent{Error: verifierErr.Error(), Code: "verifier_failed"}
+	default:
+		return ErrorEvent{Error: "Failed to verify news", Code: "internal"}
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+
+	"fact-check/internal/models"
+)
+
+// Verifier fact-checks news content with an LLM provider
+type Verifier interface {
+	VerifyNews(ctx context.Context, request *VerificationRequest) (*VerificationResult, error)
+}
+
+// VerificationRequest is the claim to check and the context known about it
+type VerificationRequest struct {
+	Content  string
+	Link     string
+	PhotoURL string
+	// Source is the registry profile of the link's domain, if any
+	Source *models.Source
+	// Language is the detected language of Content, empty if unknown
+	Language string
+	// Translation is Content translated to English, when translating first
+	Translation string
+	// Locale is the language the explanation should be written in
+	Locale string
+	// Redacted reports that personal data in Content and Translation was
+	// replaced with placeholders such as [EMAIL_1]
+	Redacted bool
+	// Prompt is the template to verify with, nil for the built-in prompt
+	Prompt *PromptTemplate
+}
+
+// VerificationResult is the outcome of a single fact-check call
+type VerificationResult struct {
+	Status      string
+	Explanation string
+	// Confidence is the model's stated confidence in Status from 0 to 1, or
+	// 0 when it gave none
+	Confidence float64
+	Model      string
+	Usage      models.TokenUsage
+	// Votes holds every member's answer when an ensemble verified the claim,
+	// Members the results of the members that answered
+	Votes   []models.ModelVerdict
+	Members []*VerificationResult
+	// Disagreement is set when ensemble members gave different verdicts;
+	// NeedsReview when the verdict should be checked by a person
+	Disagreement bool
+	NeedsReview  bool
+}
+
+// Translator translates text between languages with an LLM provider
+type Translator interface {
+	// Translate translates text into the language of locale to; from may be
+	// empty when the source language is unknown
+	Translate(ctx context.Context, text, from, to string) (*TranslationResult, error)
+}
+
+// TranslationResult is the outcome of a single translation call
+type TranslationResult struct {
+	Text  string
+	Model string
+	Usage models.TokenUsage
+}
+
+// Moderator classifies submitted content with an LLM provider
+type Moderator interface {
+	Moderate(ctx context.Context, text string) (*ModerationResult, error)
+}
+
+// ModerationResult is the outcome of a single moderation call. Action is
+// allow, quarantine or reject; Category and Reason explain the latter two.
+type ModerationResult struct {
+	Action   string
+	Category string
+	Reason   string
+	Model    string
+	Usage    models.TokenUsage
+}
+
+var _ Translator = (*OpenAIService)(nil)
+
+var _ Moderator = (*OpenAIService)(nil)
+
+var _ Verifier = (*EnsembleVerifier)(nil)
+
+var _ StreamingVerifier = (*OpenAIService)(nil)
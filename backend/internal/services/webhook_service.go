@@ -0,0 +1,475 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"fact-check/internal/config"
+	"fact-check/internal/metrics"
+	"fact-check/internal/models"
+	"fact-check/internal/netguard"
+	"fact-check/internal/repository"
+	"fact-check/internal/resilience"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookEventHeader     = "X-FactCheck-Event"
+	WebhookDeliveryHeader  = "X-FactCheck-Delivery"
+	WebhookTimestampHeader = "X-FactCheck-Timestamp"
+	WebhookSignatureHeader = "X-FactCheck-Signature"
+)
+
+var (
+	// ErrWebhookNotFound is returned for unknown endpoints and endpoints the
+	// caller cannot manage
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrDeliveryNotFound is returned for unknown deliveries
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	// ErrInvalidWebhook wraps validation failures of an endpoint registration
+	ErrInvalidWebhook = errors.New("invalid webhook")
+)
+
+// EventPublisher fans out domain events to their subscribers
+type EventPublisher interface {
+	Publish(ctx context.Context, event string, userID uuid.UUID, data interface{}) error
+}
+
+// dispatchBatch is how many due deliveries one dispatch pass sends at most
+const dispatchBatch = 20
+
+// WebhookService registers webhook endpoints, records deliveries for
+// published events and sends them with retries
+type WebhookService struct {
+	config   config.WebhookConfig
+	webhooks repository.WebhookRepository
+	orgs     *OrganizationService
+	client   *http.Client
+	backoff  resilience.Backoff
+	wake     chan struct{}
+	logger   *logrus.Logger
+}
+
+func NewWebhookService(cfg *config.Config, webhooks repository.WebhookRepository, orgs *OrganizationService, logger *logrus.Logger) *WebhookService {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Connect directly so the dialer sees, and can refuse, the real target
+	transport.Proxy = nil
+	transport.DialContext = netguard.Dialer(cfg.Webhooks.Timeout, cfg.Webhooks.AllowPrivateTargets).DialContext
+
+	return &WebhookService{
+		config:   cfg.Webhooks,
+		webhooks: webhooks,
+		orgs:     orgs,
+		client: &http.Client{
+			Timeout:   cfg.Webhooks.Timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		backoff: resilience.Backoff{Base: cfg.Webhooks.RetryBaseDelay, Max: cfg.Webhooks.RetryMaxDelay},
+		wake:    make(chan struct{}, 1),
+		logger:  logger,
+	}
+}
+
+// SignWebhook returns the signature header value for a delivery body:
+// "t=<unix timestamp>,v1=<hex HMAC-SHA256 of "<timestamp>.<body>">"
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	ts := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// RegisterEndpoint stores a new endpoint owned by the user, or by an
+// organization the user owns. The generated signing secret is only
+// returned here.
+func (s *WebhookService) RegisterEndpoint(ctx context.Context, userID string, req *models.WebhookEndpointRequest) (*models.WebhookEndpointCreated, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	if _, err := netguard.ValidateURL(req.URL, s.config.AllowPrivateTargets); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+	events, err := validateEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &models.WebhookEndpoint{
+		ID:        uuid.New(),
+		URL:       req.URL,
+		Events:    events,
+		Active:    true,
+		CreatedAt: time.Now(),
+	}
+	if req.OrganizationID != nil {
+		if err := s.orgs.RequireOwner(ctx, userID, *req.OrganizationID); err != nil {
+			return nil, err
+		}
+		endpoint.OrganizationID = req.OrganizationID
+	} else {
+		endpoint.UserID = &userUUID
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Secret = secret
+
+	if err := s.webhooks.CreateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Infof("Webhook endpoint registered: %s", endpoint.ID)
+	return &models.WebhookEndpointCreated{WebhookEndpoint: *endpoint, Secret: secret}, nil
+}
+
+func validateEvents(events []string) ([]string, error) {
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: at least one event is required", ErrInvalidWebhook)
+	}
+
+	seen := make(map[string]bool)
+	var valid []string
+	for _, event := range events {
+		known := false
+		for _, supported := range models.WebhookEvents {
+			known = known || event == supported
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			valid = append(valid, event)
+		}
+	}
+	return valid, nil
+}
+
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// ListEndpoints returns the user's own endpoints, or an organization's
+// endpoints when orgID is set
+func (s *WebhookService) ListEndpoints(ctx context.Context, userID string, orgID *uuid.UUID) ([]*models.WebhookEndpoint, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	owner := repository.WebhookOwner{UserID: &userUUID}
+	if orgID != nil {
+		if err := s.orgs.RequireOwner(ctx, userID, *orgID); err != nil {
+			return nil, err
+		}
+		owner = repository.WebhookOwner{OrganizationID: orgID}
+	}
+	return s.webhooks.ListEndpoints(ctx, owner)
+}
+
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, userID string, endpointID uuid.UUID) error {
+	if _, err := s.managedEndpoint(ctx, userID, endpointID); err != nil {
+		return err
+	}
+	if err := s.webhooks.DeleteEndpoint(ctx, endpointID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrWebhookNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, userID string, endpointID uuid.UUID, limit int) ([]*models.WebhookDelivery, error) {
+	if _, err := s.managedEndpoint(ctx, userID, endpointID); err != nil {
+		return nil, err
+	}
+	return s.webhooks.ListDeliveries(ctx, endpointID, limit)
+}
+
+// Redeliver queues a fresh attempt of a previous delivery with the same
+// event ID and payload, regardless of how the original ended
+func (s *WebhookService) Redeliver(ctx context.Context, userID string, endpointID, deliveryID uuid.UUID) (*models.WebhookDelivery, error) {
+	if _, err := s.managedEndpoint(ctx, userID, endpointID); err != nil {
+		return nil, err
+	}
+
+	original, err := s.webhooks.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	if original.EndpointID != endpointID {
+		return nil, ErrDeliveryNotFound
+	}
+
+	now := time.Now()
+	delivery := &models.WebhookDelivery{
+		ID:            uuid.New(),
+		EndpointID:    endpointID,
+		EventID:       original.EventID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        models.DeliveryPending,
+		NextAttemptAt: &now,
+		RedeliveryOf:  &original.ID,
+		CreatedAt:     now,
+	}
+	if err := s.webhooks.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	s.notify()
+	return delivery, nil
+}
+
+// managedEndpoint loads an endpoint the user owns directly or through an
+// organization they own
+func (s *WebhookService) managedEndpoint(ctx context.Context, userID string, endpointID uuid.UUID) (*models.WebhookEndpoint, error) {
+	endpoint, err := s.webhooks.GetEndpoint(ctx, endpointID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	if endpoint.UserID != nil {
+		if endpoint.UserID.String() != userID {
+			return nil, ErrWebhookNotFound
+		}
+		return endpoint, nil
+	}
+	if err := s.orgs.RequireOwner(ctx, userID, *endpoint.OrganizationID); err != nil {
+		if errors.Is(err, ErrOrganizationNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// Publish records a pending delivery of the event for every active endpoint
+// of the user, and of the user's organizations, subscribed to it
+func (s *WebhookService) Publish(ctx context.Context, event string, userID uuid.UUID, data interface{}) error {
+	endpoints, err := s.webhooks.ListSubscribed(ctx, userID, event)
+	if err != nil {
+		return err
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	envelope := models.WebhookEvent{
+		ID:        uuid.New(),
+		Type:      event,
+		CreatedAt: now.UTC(),
+		Data:      data,
+	}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	var errs []error
+	for _, endpoint := range endpoints {
+		delivery := &models.WebhookDelivery{
+			ID:            uuid.New(),
+			EndpointID:    endpoint.ID,
+			EventID:       envelope.ID,
+			Event:         event,
+			Payload:       payload,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: &now,
+			CreatedAt:     now,
+		}
+		if err := s.webhooks.CreateDelivery(ctx, delivery); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	s.notify()
+	return errors.Join(errs...)
+}
+
+// notify wakes the dispatcher without blocking
+func (s *WebhookService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends due deliveries until ctx is done, polling every poll interval
+// and right after new deliveries are queued
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+
+		// Keep going while full batches come back so a backlog drains quickly
+		for {
+			sent, err := s.DispatchDue(ctx)
+			if err != nil {
+				s.logger.WithContext(ctx).Errorf("Failed to dispatch webhooks: %v", err)
+				break
+			}
+			if sent < dispatchBatch || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
+
+// DispatchDue claims the deliveries that are due and sends them
+// concurrently, returning how many were attempted
+func (s *WebhookService) DispatchDue(ctx context.Context) (int, error) {
+	// The lease covers the request timeout, after which an unfinished attempt
+	// (e.g. from a crashed instance) becomes due again
+	deliveries, err := s.webhooks.ClaimDue(ctx, time.Now(), 2*s.config.Timeout, dispatchBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery *models.WebhookDelivery) {
+			defer wg.Done()
+			s.attempt(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+// attempt sends one delivery and saves the outcome, scheduling a retry with
+// backoff until the attempt limit is reached
+func (s *WebhookService) attempt(ctx context.Context, delivery *models.WebhookDelivery) {
+	logger := s.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"delivery_id": delivery.ID,
+		"endpoint_id": delivery.EndpointID,
+		"event":       delivery.Event,
+	})
+
+	endpoint, err := s.webhooks.GetEndpoint(ctx, delivery.EndpointID)
+	if err != nil {
+		logger.Errorf("Failed to load webhook endpoint: %v", err)
+		return
+	}
+
+	statusCode, sendErr := s.send(ctx, endpoint, delivery)
+	if ctx.Err() != nil {
+		// Shutting down; the lease expires and another pass retries it
+		return
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastStatusCode = nil
+	delivery.LastError = nil
+	if statusCode != 0 {
+		delivery.LastStatusCode = &statusCode
+	}
+
+	outcome := models.DeliverySucceeded
+	switch {
+	case sendErr == nil:
+		delivery.Status = models.DeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+	case delivery.Attempts >= s.config.MaxAttempts || !endpoint.Active:
+		message := sendErr.Error()
+		delivery.LastError = &message
+		delivery.Status = models.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		outcome = models.DeliveryFailed
+		logger.Warnf("Webhook delivery failed permanently after %d attempts: %v", delivery.Attempts, sendErr)
+	default:
+		message := sendErr.Error()
+		delivery.LastError = &message
+		next := now.Add(s.backoff.Delay(delivery.Attempts - 1))
+		delivery.NextAttemptAt = &next
+		outcome = "retrying"
+		logger.Infof("Webhook delivery attempt %d failed, retrying at %s: %v", delivery.Attempts, next.Format(time.RFC3339), sendErr)
+	}
+	metrics.WebhookDeliveries.WithLabelValues(delivery.Event, outcome).Inc()
+
+	if err := s.webhooks.UpdateDelivery(ctx, delivery); err != nil {
+		logger.Errorf("Failed to save webhook delivery: %v", err)
+	}
+}
+
+// send posts the signed payload and returns the response status, if any.
+// Only 2xx responses count as delivered; redirects are not followed.
+func (s *WebhookService) send(ctx context.Context, endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery) (int, error) {
+	ctx, cancel := withTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "fact-check-webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID.String())
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(endpoint.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a bounded amount so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+var _ EventPublisher = (*WebhookService)(nil)
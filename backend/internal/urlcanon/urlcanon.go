@@ -0,0 +1,318 @@
+// Package urlcanon reduces the many URLs an article is shared under to one
+// canonical form: redirects from shorteners are followed, tracking
+// parameters dropped, AMP and mobile variants mapped to the regular page and
+// the page's own <link rel="canonical"> honored.
+package urlcanon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"fact-check/internal/netguard"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// MaxLength is the longest canonical URL returned, the size of the column
+// it is stored in
+const MaxLength = 2048
+
+var (
+	// ErrTooManyRedirects is returned when a redirect chain exceeds the limit
+	ErrTooManyRedirects = errors.New("too many redirects")
+	// ErrTooLong is returned when a URL exceeds MaxLength even without the
+	// page's canonical link
+	ErrTooLong = errors.New("URL too long")
+)
+
+// trackingParams are query parameters that only identify the campaign or
+// click that led to a page. Parameters starting with utm_ are dropped too.
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "gclsrc": true, "dclid": true, "msclkid": true, "yclid": true,
+	"twclid": true, "igshid": true, "mc_cid": true, "mc_eid": true, "_ga": true, "_gl": true,
+	"_hsenc": true, "_hsmi": true, "mkt_tok": true, "oly_anon_id": true, "oly_enc_id": true,
+	"vero_id": true, "ref_src": true, "ref_url": true, "cmpid": true, "s_cid": true, "ncid": true,
+	"ocid": true, "smid": true, "sr_share": true, "at_medium": true, "at_campaign": true,
+	"wt_mc": true, "spm": true,
+}
+
+// variantPrefixes are host prefixes of AMP and mobile editions
+var variantPrefixes = []string{"amp.", "m.", "mobile."}
+
+// Clean canonicalizes a URL without network access: scheme and host are
+// lowercased, default ports, fragments and tracking parameters removed, the
+// remaining parameters sorted, and AMP and mobile variants mapped to the
+// regular page.
+func Clean(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return "", fmt.Errorf("URL must be an absolute http or https URL")
+	}
+
+	unwrapAMPCache(u)
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	for _, prefix := range variantPrefixes {
+		if rest := strings.TrimPrefix(host, prefix); rest != host && strings.Contains(rest, ".") {
+			host = rest
+			break
+		}
+	}
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	u.Host = host
+	if port != "" {
+		u.Host += ":" + port
+	}
+
+	u.Path = cleanAMPPath(u.Path)
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	u.RawPath = ""
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.User = nil
+
+	query := u.Query()
+	for name, values := range query {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "utm_") || trackingParams[lower] || isAMPParam(lower, values) {
+			query.Del(name)
+		}
+	}
+	u.RawQuery = query.Encode()
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+// unwrapAMPCache turns https://example-com.cdn.ampproject.org/c/s/example.com/a
+// into https://example.com/a
+func unwrapAMPCache(u *url.URL) {
+	if !strings.HasSuffix(strings.ToLower(u.Hostname()), ".cdn.ampproject.org") {
+		return
+	}
+	parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 4)
+	if len(parts) < 2 || (parts[0] != "c" && parts[0] != "v" && parts[0] != "i") {
+		return
+	}
+	rest := parts[1:]
+	scheme := "http"
+	if rest[0] == "s" {
+		scheme = "https"
+		rest = rest[1:]
+	}
+	if len(rest) == 0 || rest[0] == "" {
+		return
+	}
+	u.Scheme = scheme
+	u.Host = rest[0]
+	u.Path = "/"
+	if len(rest) > 1 {
+		u.Path += rest[1]
+	}
+}
+
+// cleanAMPPath removes /amp path segments and .amp extensions
+func cleanAMPPath(path string) string {
+	trailingSlash := strings.HasSuffix(path, "/")
+	var kept []string
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if strings.EqualFold(segment, "amp") {
+			continue
+		}
+		segment = strings.Replace(segment, ".amp.", ".", 1)
+		kept = append(kept, segment)
+	}
+	cleaned := "/" + strings.Join(kept, "/")
+	if trailingSlash && cleaned != "/" && !strings.HasSuffix(path, "/amp/") {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+func isAMPParam(name string, values []string) bool {
+	switch name {
+	case "amp", "amp_js_v", "usqp":
+		return true
+	case "outputtype":
+		return len(values) == 1 && strings.EqualFold(values[0], "amp")
+	}
+	return false
+}
+
+// Options bound the work Resolve does for one URL
+type Options struct {
+	Timeout      time.Duration
+	MaxRedirects int
+	// MaxBodyBytes is how much of the final page is searched for a
+	// canonical link
+	MaxBodyBytes int64
+	// AllowPrivate permits loopback and private targets, for development
+	AllowPrivate bool
+	UserAgent    string
+}
+
+// Resolver follows redirects to the final page. Every hop is checked with
+// netguard, so user-supplied links cannot reach internal services.
+type Resolver struct {
+	options Options
+	client  *http.Client
+}
+
+func NewResolver(options Options) *Resolver {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Connect directly so the dialer sees, and can refuse, the real target
+	transport.Proxy = nil
+	transport.DialContext = netguard.Dialer(options.Timeout, options.AllowPrivate).DialContext
+
+	return &Resolver{
+		options: options,
+		client: &http.Client{
+			Timeout:   options.Timeout,
+			Transport: transport,
+			// Redirects are followed by hand so each hop can be validated
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Result is a link after resolution
+type Result struct {
+	// Canonical is the cleaned URL of the final page, or of the canonical
+	// link it declares
+	Canonical string
+	// Host is the host the final page was fetched from. Unlike Canonical it
+	// is not chosen by the page, so it says who actually serves the content.
+	Host string
+}
+
+// Resolve returns the canonical form of raw after following redirects and
+// the final page's <link rel="canonical">. A canonical link is only honored
+// on the same registrable domain as the final page, so a page cannot pass
+// itself off as another site, and only if it fits in MaxLength.
+func (r *Resolver) Resolve(ctx context.Context, raw string) (*Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.options.Timeout)
+	defer cancel()
+
+	current, err := netguard.ValidateURL(strings.TrimSpace(raw), r.options.AllowPrivate)
+	if err != nil {
+		return nil, err
+	}
+
+	for redirects := 0; ; redirects++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, current.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.5")
+		if r.options.UserAgent != "" {
+			req.Header.Set("User-Agent", r.options.UserAgent)
+		}
+
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", current.Redacted(), err)
+		}
+
+		location := resp.Header.Get("Location")
+		if resp.StatusCode >= 300 && resp.StatusCode < 400 && location != "" {
+			resp.Body.Close()
+			if redirects >= r.options.MaxRedirects {
+				return nil, ErrTooManyRedirects
+			}
+			next, err := current.Parse(location)
+			if err != nil {
+				return nil, fmt.Errorf("invalid redirect location: %w", err)
+			}
+			if current, err = netguard.ValidateURL(next.String(), r.options.AllowPrivate); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		canonical := ""
+		if resp.StatusCode == http.StatusOK && strings.Contains(resp.Header.Get("Content-Type"), "html") {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, r.options.MaxBodyBytes))
+			if href := findCanonical(body); href != "" && len(href) <= MaxLength {
+				if declared, err := current.Parse(href); err == nil && sameSite(current, declared) {
+					if _, err := netguard.ValidateURL(declared.String(), r.options.AllowPrivate); err == nil {
+						canonical, _ = Clean(declared.String())
+					}
+				}
+			}
+		}
+		resp.Body.Close()
+
+		if canonical == "" || len(canonical) > MaxLength {
+			if canonical, err = Clean(current.String()); err != nil {
+				return nil, err
+			}
+			if len(canonical) > MaxLength {
+				return nil, ErrTooLong
+			}
+		}
+		return &Result{Canonical: canonical, Host: strings.ToLower(current.Hostname())}, nil
+	}
+}
+
+// sameSite reports whether two URLs are on the same registrable domain,
+// such as news.example.co.uk and www.example.co.uk. IP addresses and hosts
+// without a registrable domain only match themselves.
+func sameSite(a, b *url.URL) bool {
+	hostA := strings.TrimSuffix(strings.ToLower(a.Hostname()), ".")
+	hostB := strings.TrimSuffix(strings.ToLower(b.Hostname()), ".")
+	if hostA == hostB {
+		return true
+	}
+	siteA, err := publicsuffix.EffectiveTLDPlusOne(hostA)
+	if err != nil {
+		return false
+	}
+	siteB, err := publicsuffix.EffectiveTLDPlusOne(hostB)
+	return err == nil && siteA == siteB
+}
+
+var (
+	linkTag   = regexp.MustCompile(`(?is)<link\b[^>]*>`)
+	attribute = regexp.MustCompile(`(?is)([a-z][a-z0-9_:-]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	headEnd   = regexp.MustCompile(`(?i)</head\s*>`)
+)
+
+// findCanonical returns the href of the first <link rel="canonical"> in the
+// document head
+func findCanonical(document []byte) string {
+	if end := headEnd.FindIndex(document); end != nil {
+		document = document[:end[0]]
+	}
+	for _, tag := range linkTag.FindAll(document, -1) {
+		attrs := make(map[string]string)
+		for _, match := range attribute.FindAllSubmatch(tag, -1) {
+			attrs[strings.ToLower(string(match[1]))] = string(match[2]) + string(match[3]) + string(match[4])
+		}
+		if slices.Contains(strings.Fields(strings.ToLower(attrs["rel"])), "canonical") {
+			if href := strings.TrimSpace(html.UnescapeString(attrs["href"])); href != "" {
+				return href
+			}
+		}
+	}
+	return ""
+}
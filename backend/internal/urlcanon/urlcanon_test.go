@@ -0,0 +1,114 @@
+package urlcanon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"fact-check/internal/netguard"
+)
+
+func TestClean(t *testing.T) {
+	cases := map[string]string{
+		"HTTPS://Example.COM:443/news/story?utm_source=x&utm_medium=y&id=7&fbclid=abc#comments": "https://example.com/news/story?id=7",
+		"https://www.example.com/a?b=2&a=1":                                                     "https://www.example.com/a?a=1&b=2",
+		"https://m.example.com/politics/story":                                                  "https://example.com/politics/story",
+		"https://amp.example.co.uk/story.amp.html":                                              "https://example.co.uk/story.html",
+		"https://example.com/news/story/amp/":                                                   "https://example.com/news/story",
+		"https://example.com/amp/news/story?amp=1":                                              "https://example.com/news/story",
+		"https://example-com.cdn.ampproject.org/c/s/example.com/news/story?outputType=amp":      "https://example.com/news/story",
+		"http://example.com":                                                                    "http://example.com/",
+		"https://m.co/x":                                                                        "https://m.co/x",
+	}
+	for raw, want := range cases {
+		got, err := Clean(raw)
+		if err != nil {
+			t.Fatalf("Clean(%q): unexpected error: %v", raw, err)
+		}
+		if got != want {
+			t.Errorf("Clean(%q) = %q, want %q", raw, got, want)
+		}
+	}
+
+	if _, err := Clean("ftp://example.com/file"); err == nil {
+		t.Fatal("expected non-http URL to be rejected")
+	}
+}
+
+func TestSameSite(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"https://news.example.co.uk/a", "https://www.example.co.uk/b", true},
+		{"https://hoax.example.com/a", "https://www.reuters.com/a", false},
+		{"https://alice.blogspot.com/a", "https://bob.blogspot.com/a", false},
+		{"http://127.0.0.1:8080/a", "http://127.0.0.1/b", true},
+		{"http://127.0.0.1/a", "http://127.0.0.2/a", false},
+	}
+	for _, c := range cases {
+		a, _ := url.Parse(c.a)
+		b, _ := url.Parse(c.b)
+		if got := sameSite(a, b); got != c.want {
+			t.Errorf("sameSite(%q, %q) = %v, want %v", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestResolveFollowsRedirectsAndCanonical(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/short", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/story?utm_campaign=share", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/story", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<html><head><title>x</title><link rel="alternate" href="/feed"><LINK HREF='/news/story?ref_src=twsrc&amp;id=1' rel="canonical"></head><body></body></html>`))
+	})
+	mux.HandleFunc("/hoax", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<link rel="canonical" href="https://www.reuters.com/world/story">`))
+	})
+	mux.HandleFunc("/long", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<link rel="canonical" href="/` + strings.Repeat("a", MaxLength) + `">`))
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+
+	resolver := NewResolver(Options{Timeout: 5 * time.Second, MaxRedirects: 3, MaxBodyBytes: 1 << 16, AllowPrivate: true})
+	got, err := resolver.Resolve(context.Background(), server.URL+"/short")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	host := strings.Split(strings.TrimPrefix(server.URL, "http://"), ":")[0]
+	if want := server.URL + "/news/story?id=1"; got.Canonical != want || got.Host != host {
+		t.Fatalf("expected %q on %s, got %+v", want, host, got)
+	}
+
+	// Pages cannot claim another site's URL, or one too long to store
+	for _, path := range []string{"/hoax", "/long"} {
+		got, err := resolver.Resolve(context.Background(), server.URL+path)
+		if err != nil || got.Canonical != server.URL+path {
+			t.Fatalf("%s: expected the canonical link ignored, got %+v (%v)", path, got, err)
+		}
+	}
+
+	if _, err := resolver.Resolve(context.Background(), server.URL+"/loop"); !errors.Is(err, ErrTooManyRedirects) {
+		t.Fatalf("expected ErrTooManyRedirects, got %v", err)
+	}
+
+	// Without AllowPrivate the loopback test server is off limits
+	strict := NewResolver(Options{Timeout: 5 * time.Second, MaxRedirects: 3, MaxBodyBytes: 1 << 16})
+	if _, err := strict.Resolve(context.Background(), server.URL+"/short"); !errors.Is(err, netguard.ErrForbiddenAddress) {
+		t.Fatalf("expected ErrForbiddenAddress, got %v", err)
+	}
+}